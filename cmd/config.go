@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/charemma/ikno/internal/ai"
@@ -54,7 +56,8 @@ var configPathCmd = &cobra.Command{
 var validConfigKeys = []string{
 	"week_start", "author_email",
 	"ai_backend", "ai_cli_command", "ai_base_url", "ai_model", "ai_api_key", "ai_prompt",
-	"ai_default_style", "ai_language",
+	"ai_default_style", "ai_language", "ai_token_budget", "ai_chunk_by",
+	"ai_summary_cache",
 }
 
 var configSetCmd = &cobra.Command{
@@ -98,8 +101,8 @@ func applyConfigKey(cfg *config.Config, key, value string) error {
 		cfg.AuthorEmail = value
 	case "ai_backend":
 		v := strings.ToLower(value)
-		if v != "api" && v != "cli" {
-			return fmt.Errorf("ai_backend must be 'api' or 'cli'")
+		if !slices.Contains(ai.Backends, v) {
+			return fmt.Errorf("ai_backend must be one of %s", strings.Join(ai.Backends, ", "))
 		}
 		cfg.AIBackend = v
 	case "ai_cli_command":
@@ -120,6 +123,24 @@ func applyConfigKey(cfg *config.Config, key, value string) error {
 		cfg.AIDefaultStyle = v
 	case "ai_language":
 		cfg.AILanguage = value
+	case "ai_token_budget":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("ai_token_budget must be a number of tokens, 0 to disable")
+		}
+		cfg.AITokenBudget = n
+	case "ai_chunk_by":
+		v := strings.ToLower(value)
+		if v != "day" && v != "project" {
+			return fmt.Errorf("ai_chunk_by must be 'day' or 'project'")
+		}
+		cfg.AIChunkBy = v
+	case "ai_summary_cache":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("ai_summary_cache must be true or false")
+		}
+		cfg.AISummaryCache = b
 	default:
 		return fmt.Errorf("unknown key %q\n\nValid keys: %s", key, strings.Join(validConfigKeys, ", "))
 	}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/config"
+	"github.com/charemma/ikno/internal/git"
+	"github.com/charemma/ikno/internal/timesheet"
+	"github.com/charemma/ikno/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat    string
+	exportOutput    string
+	exportProject   string
+	exportIncrement string
+	exportMinimum   string
+	exportRounding  string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export activity for other tools",
+}
+
+var exportTimesheetCmd = &cobra.Command{
+	Use:   "timesheet [timespec]",
+	Short: "Export estimated hours as a timesheet",
+	Long: `Export a timesheet with one row per day and project.
+
+Durations are the estimates of ikno hours, rounded up to 15 minutes with a
+15 minute minimum unless configured otherwise. Descriptions are built from
+commit messages, completed tasks and session topics. No AI backend needed.
+
+Formats (--format):
+  csv        date, project, client, billable, hours, estimated hours, description (default)
+  harvest    Harvest time entry import (Date, Client, Project, Task, Notes, Hours, names)
+  toggl      Toggl Track CSV import (Email, Start date/time, Duration, ...)
+  ics        iCalendar, one event per row starting at the first session
+
+Client and billable flag come from the projects section of the config.
+Rounding defaults can be set under "timesheet" in the config.
+
+Examples:
+  ikno export timesheet lastweek
+  ikno export timesheet "2026-03-01..31" --format harvest -o march.csv
+  ikno export timesheet lastweek --format toggl --project acme-shop
+  ikno export timesheet thisweek --format ics --increment 30m --rounding nearest`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if !slices.Contains(timesheet.Formats, exportFormat) {
+			return fmt.Errorf("unsupported format %q (must be one of %s)", exportFormat, strings.Join(timesheet.Formats, ", "))
+		}
+		rounding, err := exportRoundingRules(cmd, cfg)
+		if err != nil {
+			return err
+		}
+
+		timespec := "lastweek"
+		if len(args) > 0 {
+			timespec = args[0]
+		}
+
+		result, err := buildHours(cmd.Context(), cfg, timespec, exportProject)
+		if err != nil || result == nil {
+			return err
+		}
+		rows := timesheet.Build(result.Hours, cfg.Projects, rounding)
+		if len(rows) == 0 {
+			_, _ = fmt.Fprintln(os.Stderr, ui.StyleMuted.Render("No activity found for "+timespec))
+			return nil
+		}
+
+		if exportOutput == "" || exportOutput == "-" {
+			if err := timesheet.Write(os.Stdout, exportFormat, rows, timesheetOptions(cfg)); err != nil {
+				return fmt.Errorf("failed to write timesheet: %w", err)
+			}
+			return nil
+		}
+
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", exportOutput, err)
+		}
+		if err := timesheet.Write(f, exportFormat, rows, timesheetOptions(cfg)); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to write timesheet: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write %s: %w", exportOutput, err)
+		}
+
+		var total time.Duration
+		for _, r := range rows {
+			total += r.Duration
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "%s %d rows (%.2fh) to %s\n", ui.StyleSuccess.Render("exported"), len(rows), total.Hours(), exportOutput)
+		return nil
+	},
+}
+
+// exportRoundingRules applies the rounding flags on top of the config.
+func exportRoundingRules(cmd *cobra.Command, cfg *config.Config) (timesheet.Rounding, error) {
+	rounding := cfg.GetTimesheetRounding()
+	for _, f := range []struct {
+		name   string
+		value  string
+		target *time.Duration
+	}{
+		{"increment", exportIncrement, &rounding.Increment},
+		{"minimum", exportMinimum, &rounding.Minimum},
+	} {
+		if !cmd.Flags().Changed(f.name) {
+			continue
+		}
+		d, err := time.ParseDuration(f.value)
+		if err != nil {
+			return rounding, fmt.Errorf("invalid --%s: %w", f.name, err)
+		}
+		*f.target = d
+	}
+	if cmd.Flags().Changed("rounding") {
+		rounding.Mode = strings.ToLower(exportRounding)
+	}
+	return rounding, rounding.Validate()
+}
+
+// timesheetOptions fills the per-user fields of the import formats from
+// the config and git identity.
+func timesheetOptions(cfg *config.Config) timesheet.Options {
+	opts := timesheet.Options{
+		Task:  cfg.Timesheet.Task,
+		Email: cfg.AuthorEmail,
+	}
+	if opts.Task == "" {
+		opts.Task = "Development"
+	}
+	if name, err := git.GetAuthorName(); err == nil {
+		opts.FirstName, opts.LastName = timesheet.SplitName(name)
+	}
+	return opts
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportTimesheetCmd)
+
+	exportTimesheetCmd.Flags().StringVar(&exportFormat, "format", timesheet.FormatCSV, "Output format: csv, harvest, toggl, ics")
+	exportTimesheetCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write to a file instead of stdout")
+	exportTimesheetCmd.Flags().StringVar(&exportProject, "project", "", "Only export this project (see projects in config)")
+	exportTimesheetCmd.Flags().StringVar(&exportIncrement, "increment", "", "Round durations to multiples of this, e.g. 15m, 6m, 0s (default from config or 15m)")
+	exportTimesheetCmd.Flags().StringVar(&exportMinimum, "minimum", "", "Shortest row duration, e.g. 30m (default from config or 15m)")
+	exportTimesheetCmd.Flags().StringVar(&exportRounding, "rounding", "", "Rounding mode: up, nearest, down (default from config or up)")
+}
@@ -1,14 +1,31 @@
 package cmd
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"path/filepath"
 	"strings"
 
+	"github.com/charemma/ikno/internal/paths"
 	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/sources/activitywatch"
+	"github.com/charemma/ikno/internal/sources/ci"
 	"github.com/charemma/ikno/internal/sources/claude"
+	"github.com/charemma/ikno/internal/sources/containers"
+	"github.com/charemma/ikno/internal/sources/editor"
 	"github.com/charemma/ikno/internal/sources/git"
+	"github.com/charemma/ikno/internal/sources/gitbug"
+	"github.com/charemma/ikno/internal/sources/logseq"
 	"github.com/charemma/ikno/internal/sources/markdown"
 	"github.com/charemma/ikno/internal/sources/obsidian"
+	"github.com/charemma/ikno/internal/sources/org"
+	"github.com/charemma/ikno/internal/sources/records"
+	"github.com/charemma/ikno/internal/sources/taskwarrior"
+	"github.com/charemma/ikno/internal/sources/timecsv"
+	"github.com/charemma/ikno/internal/sources/timewarrior"
+	"github.com/charemma/ikno/internal/sources/todotxt"
+	"github.com/charemma/ikno/internal/sources/watson"
 )
 
 // createSource instantiates a Source from a stored Config.
@@ -20,11 +37,53 @@ func createSource(cfg sources.Config) (sources.Source, error) {
 	case "markdown":
 		tags := splitTrimmed(cfg.Metadata["tags"], ",")
 		headings := splitTrimmed(cfg.Metadata["headings"], ",")
-		return markdown.NewMarkdownSource(cfg.Path, tags, headings), nil
+		src := markdown.NewMarkdownSource(cfg.Path, tags, headings)
+		if cfg.Metadata["tasks"] == "true" {
+			src.EnableTasks(statePath("tasks", cfg))
+		}
+		return src, nil
 	case "obsidian":
-		return obsidian.NewObsidianSource(cfg.Path), nil
+		src := obsidian.NewObsidianSource(cfg.Path).WithFilters(obsidian.Filters{
+			IncludeFolders: splitTrimmed(cfg.Metadata["include_folders"], ","),
+			ExcludeFolders: splitTrimmed(cfg.Metadata["exclude_folders"], ","),
+			IncludeTags:    splitTrimmed(cfg.Metadata["include_tags"], ","),
+			ExcludeTags:    splitTrimmed(cfg.Metadata["exclude_tags"], ","),
+		}).WithDailyNotes(cfg.Metadata["daily_notes"] != "false")
+		if p := statePath("obsidian", cfg); p != "" {
+			src.EnableSnapshots(p)
+		}
+		if cfg.Metadata["tasks"] == "true" {
+			src.EnableTasks(statePath("tasks", cfg))
+		}
+		return src, nil
 	case "claude":
 		return claude.NewClaudeSource(cfg.Path), nil
+	case "org":
+		return org.NewOrgSource(cfg.Path), nil
+	case "logseq":
+		return logseq.NewLogseqSource(cfg.Path), nil
+	case "todotxt":
+		return todotxt.NewTodoTxtSource(cfg.Path), nil
+	case "taskwarrior":
+		return taskwarrior.NewTaskwarriorSource(cfg.Path), nil
+	case "git-bug":
+		return gitbug.NewGitBugSource(cfg.Path, cfg.Metadata["author"]), nil
+	case "ci":
+		return ci.NewCISource(cfg.Path, cfg.Metadata), nil
+	case "containers":
+		return containers.NewContainerSource(cfg.Path, cfg.Metadata), nil
+	case "editor":
+		return editor.NewEditorSource(cfg.Path), nil
+	case "timewarrior":
+		return timewarrior.NewTimewarriorSource(cfg.Path), nil
+	case "watson":
+		return watson.NewWatsonSource(cfg.Path), nil
+	case "activitywatch":
+		return activitywatch.NewActivityWatchSource(cfg.Path), nil
+	case "toggl", "clockify":
+		return timecsv.NewCSVSource(cfg.Type, cfg.Path), nil
+	case records.FormatJSONL, records.FormatCSV:
+		return records.NewRecordsSource(cfg.Type, cfg.Path, cfg.Metadata), nil
 	default:
 		return nil, fmt.Errorf("unsupported source type: %s", cfg.Type)
 	}
@@ -40,3 +99,15 @@ func splitTrimmed(s, sep string) []string {
 	}
 	return parts
 }
+
+// statePath returns the file under <config>/state/<kind>/ where a source
+// keeps state between runs, or "" if the config directory cannot be resolved.
+// Sources treat an empty path as "no state" and degrade gracefully.
+func statePath(kind string, cfg sources.Config) string {
+	dir, err := paths.GetConfigDir()
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(cfg.Type + ":" + cfg.Path))
+	return filepath.Join(dir, "state", kind, hex.EncodeToString(sum[:8])+".json")
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charemma/ikno/internal/history"
+	"github.com/charemma/ikno/internal/paths"
+	"github.com/charemma/ikno/internal/ui"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyStyle string
+	historyLimit int
+	historyRaw   bool
+	historyAll   bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Browse, compare and remove archived AI recaps",
+	Long: `Every AI summary of ikno recap is archived with its time range, style,
+language, model, entry count and a hash of the prompt. Read it again or
+compare regenerated reports without another generation.
+
+Recaps are referred to by ID, a unique prefix of one, or "latest".
+
+Examples:
+  ikno history list
+  ikno history list --style brief
+  ikno history show
+  ikno history show 20260414
+  ikno history diff
+  ikno history diff 20260410-170212 20260414-091500
+  ikno history rm 20260410-170212`,
+}
+
+var historyListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List archived recaps, newest first",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		archive, err := historyArchive()
+		if err != nil {
+			return err
+		}
+		records, err := archive.List()
+		if err != nil {
+			return fmt.Errorf("failed to list history: %w", err)
+		}
+		if historyStyle != "" {
+			var filtered []*history.Record
+			for _, r := range records {
+				if strings.EqualFold(r.Style, historyStyle) {
+					filtered = append(filtered, r)
+				}
+			}
+			records = filtered
+		}
+		if len(records) == 0 {
+			_, _ = fmt.Fprintln(os.Stdout, ui.StyleMuted.Render("No recaps archived yet"))
+			return nil
+		}
+		if historyLimit > 0 && len(records) > historyLimit {
+			records = records[:historyLimit]
+		}
+		renderHistoryList(os.Stdout, records, ui.IsPlain(cmd))
+		return nil
+	},
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show [id]",
+	Short: "Show an archived recap (default: the latest)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		archive, err := historyArchive()
+		if err != nil {
+			return err
+		}
+		r, err := archive.Get(refArg(args, 0))
+		if err != nil {
+			return err
+		}
+
+		if historyRaw {
+			_, _ = fmt.Fprintln(os.Stdout, r.Text)
+			return nil
+		}
+		_, _ = fmt.Fprintln(os.Stderr, ui.StyleMuted.Render(recordSummary(r)))
+		text := r.Text + "\n"
+		// Stats style has pre-formatted ASCII bars -- glamour would break the layout.
+		if isatty.IsTerminal(os.Stdout.Fd()) && r.Style != "stats" {
+			if rendered, err := glamour.Render(text, "auto"); err == nil {
+				text = rendered
+			}
+		}
+		_, _ = fmt.Fprint(os.Stdout, text)
+		return nil
+	},
+}
+
+var historyDiffCmd = &cobra.Command{
+	Use:   "diff [old] [new]",
+	Short: "Show how two archived recaps differ",
+	Long: `Show a line diff of two archived recaps.
+
+With one recap, it is compared with the newest earlier recap of the same
+period, for example the first generation of a weekly report. Without
+arguments, the latest recap is compared that way.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		archive, err := historyArchive()
+		if err != nil {
+			return err
+		}
+		var older, newer *history.Record
+		if len(args) == 2 {
+			if older, err = archive.Get(args[0]); err != nil {
+				return err
+			}
+			if newer, err = archive.Get(args[1]); err != nil {
+				return err
+			}
+		} else {
+			if newer, err = archive.Get(refArg(args, 0)); err != nil {
+				return err
+			}
+			if older, err = archive.Previous(newer); err != nil {
+				return err
+			}
+		}
+		renderHistoryDiff(os.Stdout, older, newer, ui.IsPlain(cmd))
+		return nil
+	},
+}
+
+var historyRmCmd = &cobra.Command{
+	Use:     "rm [id...]",
+	Aliases: []string{"remove"},
+	Short:   "Remove archived recaps",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !historyAll {
+			return fmt.Errorf("name the recaps to remove, or use --all")
+		}
+		archive, err := historyArchive()
+		if err != nil {
+			return err
+		}
+
+		var records []*history.Record
+		if historyAll {
+			if records, err = archive.List(); err != nil {
+				return fmt.Errorf("failed to list history: %w", err)
+			}
+		}
+		for _, ref := range args {
+			r, err := archive.Get(ref)
+			if err != nil {
+				return err
+			}
+			records = append(records, r)
+		}
+
+		for _, r := range records {
+			if err := archive.Remove(r.ID); err != nil {
+				return err
+			}
+			_, _ = fmt.Printf("%s %s\n", ui.StyleSuccess.Render("removed"), recordSummary(r))
+		}
+		return nil
+	},
+}
+
+// historyArchive returns the recap archive in the config directory.
+func historyArchive() (*history.Archive, error) {
+	dir, err := paths.GetConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ikno config directory: %w", err)
+	}
+	return history.NewArchive(filepath.Join(dir, "history")), nil
+}
+
+// refArg returns args[i], or "latest" if it is missing.
+func refArg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return "latest"
+}
+
+// recordPeriod describes the period of a record, e.g.
+// "thisweek (2026-04-13 to 2026-04-19)".
+func recordPeriod(r *history.Record) string {
+	period := fmt.Sprintf("%s (%s to %s)", r.Timespec, r.From, r.To)
+	if r.Compare != "" {
+		period += " vs " + r.Compare
+	}
+	if r.Project != "" {
+		period += ", project " + r.Project
+	}
+	return period
+}
+
+// recordSummary describes a record in one line.
+func recordSummary(r *history.Record) string {
+	parts := []string{r.ID, recordPeriod(r)}
+	for _, s := range []string{r.Style, r.Language, r.Model} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	parts = append(parts, fmt.Sprintf("%d entries", r.Entries))
+	return strings.Join(parts, " · ")
+}
+
+func renderHistoryList(w io.Writer, records []*history.Record, plain bool) {
+	style := func(s string, st lipgloss.Style) string {
+		if plain {
+			return s
+		}
+		return st.Render(s)
+	}
+
+	idWidth, styleWidth := 0, 0
+	for _, r := range records {
+		idWidth = max(idWidth, len(r.ID))
+		styleWidth = max(styleWidth, len(r.Style))
+	}
+	for _, r := range records {
+		_, _ = fmt.Fprintf(w, "%s  %s  %s  %s\n",
+			style(fmt.Sprintf("%-*s", idWidth, r.ID), ui.StyleBold),
+			fmt.Sprintf("%-*s", styleWidth, r.Style),
+			recordPeriod(r),
+			style(fmt.Sprintf("%d entries, %s", r.Entries, r.Language), ui.StyleMuted))
+	}
+}
+
+// historyDiffContext is the number of unchanged lines shown around changes.
+const historyDiffContext = 3
+
+func renderHistoryDiff(w io.Writer, older, newer *history.Record, plain bool) {
+	style := func(s string, st lipgloss.Style) string {
+		if plain {
+			return s
+		}
+		return st.Render(s)
+	}
+	removed := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "124", Dark: "9"})
+
+	_, _ = fmt.Fprintln(w, style("--- "+recordSummary(older), ui.StyleBold))
+	_, _ = fmt.Fprintln(w, style("+++ "+recordSummary(newer), ui.StyleBold))
+	if older.PromptHash != newer.PromptHash {
+		_, _ = fmt.Fprintln(w, style("The prompts differ.", ui.StyleMuted))
+	}
+
+	hunks := history.Hunks(history.DiffLines(older.Text, newer.Text), historyDiffContext)
+	if len(hunks) == 0 {
+		_, _ = fmt.Fprintln(w, style("The recaps are identical.", ui.StyleMuted))
+		return
+	}
+	for _, h := range hunks {
+		_, _ = fmt.Fprintln(w, style(h.Header(), ui.StyleMuted))
+		for _, l := range h.Lines {
+			line := string(l.Op) + l.Text
+			switch l.Op {
+			case history.Delete:
+				line = style(line, removed)
+			case history.Insert:
+				line = style(line, ui.StyleSuccess)
+			}
+			_, _ = fmt.Fprintln(w, line)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyListCmd, historyShowCmd, historyDiffCmd, historyRmCmd)
+
+	historyListCmd.Flags().StringVar(&historyStyle, "style", "", "Only list recaps of this style")
+	historyListCmd.Flags().IntVar(&historyLimit, "limit", 20, "Number of recaps listed, 0 for all")
+	historyListCmd.Flags().Bool("plain", false, "Plain output without colors")
+	historyShowCmd.Flags().BoolVar(&historyRaw, "raw", false, "Only the recap text, unformatted")
+	historyDiffCmd.Flags().Bool("plain", false, "Plain output without colors")
+	historyRmCmd.Flags().BoolVar(&historyAll, "all", false, "Remove all archived recaps")
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charemma/ikno/internal/history"
+)
+
+func TestRenderHistoryDiff(t *testing.T) {
+	older := &history.Record{ID: "20260417-163000", Timespec: "thisweek", From: "2026-04-13", To: "2026-04-19", Style: "report", Entries: 40, PromptHash: "a", Text: "## Done\n\n- shipped login"}
+	newer := &history.Record{ID: "20260417-170500", Timespec: "thisweek", From: "2026-04-13", To: "2026-04-19", Style: "report", Entries: 42, PromptHash: "b", Text: "## Done\n\n- shipped login\n- fixed checkout"}
+
+	var buf strings.Builder
+	renderHistoryDiff(&buf, older, newer, true)
+	want := `--- 20260417-163000 · thisweek (2026-04-13 to 2026-04-19) · report · 40 entries
++++ 20260417-170500 · thisweek (2026-04-13 to 2026-04-19) · report · 42 entries
+The prompts differ.
+@@ -1,3 +1,4 @@
+ ## Done
+ 
+ - shipped login
++- fixed checkout
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	renderHistoryDiff(&buf, older, older, true)
+	if !strings.HasSuffix(buf.String(), "The recaps are identical.\n") {
+		t.Errorf("identical recaps: %s", buf.String())
+	}
+}
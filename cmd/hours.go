@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/config"
+	"github.com/charemma/ikno/internal/recap"
+	"github.com/charemma/ikno/internal/storage"
+	"github.com/charemma/ikno/internal/timerange"
+	"github.com/charemma/ikno/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	hoursJSON    bool
+	hoursProject string
+)
+
+var hoursCmd = &cobra.Command{
+	Use:   "hours [timespec]",
+	Short: "Estimate working time per day and project",
+	Long: `Estimate active working time per day and project from your activity.
+
+Entries of a project are grouped into sessions. An entry less than the idle
+gap (default 30m) after the previous one extends the session; otherwise a
+new session starts. Each session begins with a lead-in before its first
+entry, because a commit marks the end of work, not its start (git: 20m,
+notes and tasks: 10m). AI sessions, editor bursts and time trackers count
+their recorded duration. Tune both under "hours" in the config.
+
+Projects come from the projects section of the config, then from
+repository names, Claude working directories and tracker projects.
+
+The day total counts parallel work once, so it can be less than the sum of
+its projects. These are estimates, not a time sheet.
+
+Examples:
+  ikno hours
+  ikno hours lastweek
+  ikno hours "last 30 days" --project acme-shop
+  ikno hours thisweek --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		timespec := "thisweek"
+		if len(args) > 0 {
+			timespec = args[0]
+		}
+
+		result, err := buildHours(cmd.Context(), cfg, timespec, hoursProject)
+		if err != nil || result == nil {
+			return err
+		}
+
+		if hoursJSON {
+			return recap.RenderJSON(os.Stdout, result)
+		}
+
+		if len(result.Entries) == 0 {
+			_, _ = fmt.Fprintln(os.Stdout, ui.StyleMuted.Render("No activity found for "+timespec))
+			return nil
+		}
+
+		renderHours(os.Stdout, result, ui.IsPlain(cmd))
+		return nil
+	},
+}
+
+// buildHours collects the entries of a period, optionally limited to one
+// project, and estimates their working time. It returns a nil result after
+// telling the user when no sources are configured.
+func buildHours(ctx context.Context, cfg *config.Config, timespec, projectName string) (*recap.RecapResult, error) {
+	project, err := resolveProject(cfg, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := timerange.NewParser(cfg.GetTimerangeConfig())
+	tr, err := parser.Parse(timespec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time specification: %w", err)
+	}
+
+	store, err := storage.NewStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	sourceConfigs, err := store.GetSources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sources: %w", err)
+	}
+	if len(sourceConfigs) == 0 {
+		_, _ = fmt.Fprintln(os.Stdout, ui.StyleMuted.Render("No sources configured yet. Run: ikno source add"))
+		return nil, nil
+	}
+
+	result, err := recap.BuildRecap(ctx, sourceConfigs, tr, timespec, cfg.GetBuildOptions(), createSource)
+	if err != nil {
+		return nil, err
+	}
+	printFailures(os.Stderr, result.Failures())
+	if project != "" {
+		result.Entries = filterByProject(result.Entries, project)
+	}
+	result.Hours = recap.EstimateHours(result.Entries, cfg.GetHoursOptions())
+	return result, nil
+}
+
+// hoursBarWidth is the width of the bar chart next to each project.
+const hoursBarWidth = 20
+
+// renderHours writes the estimate per day with a bar per project, followed
+// by the period totals.
+func renderHours(w io.Writer, result *recap.RecapResult, plain bool) {
+	style := func(s string, render func(...string) string) string {
+		if plain {
+			return s
+		}
+		return render(s)
+	}
+	estimate := result.Hours
+
+	header := fmt.Sprintf("Estimated working time, %s to %s", result.TimeRange.From.Format("2006-01-02"), result.TimeRange.To.Format("2006-01-02"))
+	_, _ = fmt.Fprintln(w, style(header, ui.StyleSectionHeader.Render))
+	_, _ = fmt.Fprintln(w)
+
+	width := len("Total")
+	for _, p := range estimate.Projects {
+		width = max(width, len(p.Project))
+	}
+
+	// Scale bars to the longest project-day.
+	var longest time.Duration
+	for _, d := range estimate.Days {
+		for _, p := range d.Projects {
+			longest = max(longest, p.Duration)
+		}
+	}
+
+	for _, d := range estimate.Days {
+		day := fmt.Sprintf("%-*s  %7s", width+2, d.Date.Format("Mon 2006-01-02"), recap.FormatHours(d.Total))
+		_, _ = fmt.Fprintln(w, style(day, ui.StyleDay.Render))
+		for _, p := range d.Projects {
+			_, _ = fmt.Fprintf(w, "  %-*s  %7s  %s\n", width, p.Project, recap.FormatHours(p.Duration), hoursBar(p.Duration, longest))
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+
+	_, _ = fmt.Fprintln(w, style("Projects", ui.StyleBold.Render))
+	for _, p := range estimate.Projects {
+		sessions := fmt.Sprintf("%d sessions", p.Sessions)
+		if p.Sessions == 1 {
+			sessions = "1 session"
+		}
+		_, _ = fmt.Fprintf(w, "  %-*s  %7s  %s\n", width, p.Project, recap.FormatHours(p.Duration), style(sessions, ui.StyleMuted.Render))
+	}
+	_, _ = fmt.Fprintf(w, "  %-*s  %7s\n", width, "Total", recap.FormatHours(estimate.Total))
+}
+
+func hoursBar(d, longest time.Duration) string {
+	if longest <= 0 {
+		return ""
+	}
+	n := int(float64(hoursBarWidth)*float64(d)/float64(longest) + 0.5)
+	return strings.Repeat("█", n) + strings.Repeat("░", hoursBarWidth-n)
+}
+
+func init() {
+	rootCmd.AddCommand(hoursCmd)
+	hoursCmd.Flags().BoolVar(&hoursJSON, "json", false, "Structured JSON output")
+	hoursCmd.Flags().StringVar(&hoursProject, "project", "", "Only include activity mapped to this project (see projects in config)")
+	hoursCmd.Flags().Bool("plain", false, "Plain output without colors")
+}
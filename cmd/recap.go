@@ -2,22 +2,26 @@ package cmd
 
 import (
 	"bytes"
-	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charemma/ikno/internal/ai"
 	"github.com/charemma/ikno/internal/config"
+	"github.com/charemma/ikno/internal/filter"
+	"github.com/charemma/ikno/internal/history"
 	"github.com/charemma/ikno/internal/paths"
+	"github.com/charemma/ikno/internal/projects"
 	"github.com/charemma/ikno/internal/recap"
 	"github.com/charemma/ikno/internal/sources"
 	"github.com/charemma/ikno/internal/storage"
 	"github.com/charemma/ikno/internal/timerange"
 	"github.com/charemma/ikno/internal/ui"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 )
 
@@ -30,6 +34,15 @@ var (
 	recapLang    string
 	recapStyles  bool
 	recapVerbose bool
+	recapProject string
+	recapExplain bool
+	recapCompare string
+	recapNoCache bool
+
+	recapWhere          string
+	recapSources        []string
+	recapExcludeSources []string
+	recapGrep           string
 )
 
 var recapCmd = &cobra.Command{
@@ -54,12 +67,44 @@ Output styles (--style):
   status           Progress/Blocker/Next, progress-focused
   report           Polished formal report, deliveries
   retro            Retrospective: good/bad/learnings
+  compare          What changed versus the --compare period (defaults to thisweek)
 
 Output modes:
   (default)        AI-generated summary (requires AI backend)
   --raw            Unformatted entry dump, one per line -- for pipes, grep
   --json           Structured JSON
 
+Filtering (applies to AI, --raw and --json output):
+  --project NAME   Only activity mapped to a project from the config
+                   "projects" section
+  --source TYPE    Only these source types (repeatable, comma-separated)
+  --exclude-source TYPE
+                   Leave out these source types
+  --grep REGEX     Only entries whose text matches (case-insensitive)
+  --where EXPR     Filter expression, replaces the style's default filter:
+                     source=git and repo~ikno and not content~"^chore"
+                     source in (git, claude) or tag=acme
+                   Fields: source, location, path, repo, project, content,
+                   tag, branch, or meta.KEY for a metadata key. Operators:
+                   = != ~ !~ in, combined with and, or, not and parentheses.
+
+Comparing periods:
+  --compare SPEC   Also collect SPEC and compare: hours and entries per
+                   project, entries per source and category, new and
+                   dropped projects. Prints the table and summarizes the
+                   changes with the compare style; --raw prints only the
+                   table, --json the deltas.
+
+Day summaries:
+  Recaps of several days are composed from summaries of single days,
+  which are cached under the config directory. Only days whose activity
+  changed are summarized again. --no-cache summarizes every day again;
+  ai_summary_cache: false in the config turns the cache off.
+
+Diagnostics:
+  --explain        Show per source how many entries were read, how long it
+                   took and why it failed (on stderr)
+
 Examples:
   ikno recap
   ikno recap thisweek
@@ -67,6 +112,12 @@ Examples:
   ikno recap thisweek --style digest
   ikno recap --style brief
   ikno recap lastweek --raw | grep feat
+  ikno recap thisweek --project acme-shop
+  ikno recap lastweek --explain --raw
+  ikno recap thisweek --compare lastweek
+  ikno recap "last 30 days" --compare "2025-11-01..2025-11-30" --raw
+  ikno recap thisweek --where 'source=git and not content~"^chore"'
+  ikno recap lastweek --exclude-source activitywatch,ci --grep auth
   ikno recap 2025-12-01..2025-12-31 --json`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -79,8 +130,14 @@ Examples:
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
-		// Resolve style: --style flag > config ai_default_style > "digest"
+		// Resolve style: --style flag > compare with --compare > config ai_default_style > "digest"
 		style := resolveStyle(recapStyle, cfg.AIDefaultStyle)
+		if recapCompare != "" && recapStyle == "" {
+			style = ai.StyleCompare
+		}
+		if style == ai.StyleCompare && recapCompare == "" && !recapRaw && !recapJSON {
+			return fmt.Errorf("style compare needs a period to compare with, e.g. ikno recap thisweek --compare lastweek")
+		}
 
 		// Resolve language: --lang flag > config ai_language > "english"
 		lang := resolveLanguage(recapLang, cfg.AILanguage)
@@ -91,11 +148,35 @@ Examples:
 			timespec = args[0]
 		}
 
+		project, err := resolveProject(cfg, recapProject)
+		if err != nil {
+			return err
+		}
+
+		tmpl, hasTemplate, err := loadCustomTemplate(string(style))
+		if err != nil {
+			return fmt.Errorf("failed to load custom template: %w", err)
+		}
+		where := ai.DefaultFilter(style)
+		if hasTemplate && tmpl.Where != "" {
+			where = tmpl.Where
+		}
+		entryFilter, err := recapFilter(where)
+		if err != nil {
+			return err
+		}
+
 		parser := timerange.NewParser(cfg.GetTimerangeConfig())
 		tr, err := parser.Parse(timespec)
 		if err != nil {
 			return fmt.Errorf("invalid time specification: %w", err)
 		}
+		var compareTR *timerange.TimeRange
+		if recapCompare != "" {
+			if compareTR, err = parser.Parse(recapCompare); err != nil {
+				return fmt.Errorf("invalid --compare time specification: %w", err)
+			}
+		}
 
 		store, err := storage.NewStore()
 		if err != nil {
@@ -116,15 +197,34 @@ Examples:
 			return nil
 		}
 
-		result, err := recap.BuildRecap(sourceConfigs, tr, timespec, recap.BuildOptions{EnrichDiffs: false}, createSource, os.Stderr)
+		opts := cfg.GetBuildOptions()
+		opts.Filter = entryFilter
+		result, err := collectRecap(cmd, cfg, sourceConfigs, tr, timespec, project, opts)
 		if err != nil {
 			return err
 		}
 
-		// Apply source filter for style before any rendering.
-		result.Entries = filterByStyle(result.Entries, style)
+		var comparison *recap.Comparison
+		if compareTR != nil {
+			previous, err := collectRecap(cmd, cfg, sourceConfigs, compareTR, recapCompare, project, opts)
+			if err != nil {
+				return err
+			}
+			if len(result.Entries) == 0 && len(previous.Entries) == 0 {
+				_, _ = fmt.Fprintln(os.Stdout, ui.StyleMuted.Render("No activity found for "+timespec+" or "+recapCompare))
+				return nil
+			}
+			comparison = recap.Compare(result, previous)
 
-		if len(result.Entries) == 0 {
+			if recapJSON {
+				return recap.RenderCompareJSON(os.Stdout, comparison)
+			}
+			renderCompare(os.Stdout, comparison, recapRaw || ui.IsPlain(cmd))
+			if recapRaw {
+				return nil
+			}
+			_, _ = fmt.Fprintln(os.Stdout)
+		} else if len(result.Entries) == 0 {
 			_, _ = fmt.Fprintln(os.Stdout, ui.StyleMuted.Render("No activity found for "+timespec))
 			return nil
 		}
@@ -152,24 +252,61 @@ Examples:
 		}
 
 		var buf bytes.Buffer
-		if err := recap.RenderForAI(&buf, result); err != nil {
+		if comparison != nil {
+			err = recap.RenderCompareForAI(&buf, comparison)
+		} else {
+			err = recap.RenderForAI(&buf, result)
+		}
+		if err != nil {
 			return fmt.Errorf("failed to render recap: %w", err)
 		}
+		var appendix bytes.Buffer
+		if style == ai.StyleStats && comparison == nil {
+			recap.RenderHoursForAI(&appendix, result.Hours)
+		}
+		var split func() ([]ai.Chunk, error)
+		var cache *ai.SummaryCache
+		if comparison == nil {
+			split = func() ([]ai.Chunk, error) { return aiChunks(result, cfg.AIChunkBy) }
+			if cfg.AISummaryCache && cfg.AIChunkBy == "day" {
+				if cache, err = summaryCache(); err != nil {
+					return err
+				}
+				cache.Refresh = recapNoCache
+			}
+		}
 
 		// Resolve prompt: --prompt flag > config ai_prompt > custom template file > style template
 		promptOverride := recapPrompt
 		if promptOverride == "" && cfg.AIPrompt == "" {
-			if custom, found, err := loadCustomTemplate(string(style), lang); err != nil {
-				return fmt.Errorf("failed to load custom template: %w", err)
-			} else if found {
-				promptOverride = custom
+			if hasTemplate {
+				promptOverride = strings.ReplaceAll(tmpl.Body, "{language}", lang)
 			} else {
 				promptOverride = ai.PromptWithLanguage(style, lang)
 			}
 		}
 
 		period := fmt.Sprintf("%s (%s to %s)", timespec, tr.From.Format("2006-01-02"), tr.To.Format("2006-01-02"))
-		return ai.Transform(context.Background(), os.Stdout, buf.String(), period, ai.TransformConfig{
+		entryCount := len(result.Entries)
+		if comparison != nil {
+			period += fmt.Sprintf(" vs %s (%s to %s)", recapCompare, compareTR.From.Format("2006-01-02"), compareTR.To.Format("2006-01-02"))
+			entryCount += len(comparison.Previous.Entries)
+		}
+		archive, err := historyArchive()
+		if err != nil {
+			return err
+		}
+		record := &history.Record{
+			Timespec: timespec,
+			From:     tr.From.Format("2006-01-02"),
+			To:       tr.To.Format("2006-01-02"),
+			Compare:  recapCompare,
+			Project:  project,
+			Style:    string(style),
+			Language: lang,
+			Entries:  entryCount,
+		}
+		return ai.Transform(cmd.Context(), os.Stdout, buf.String(), period, ai.TransformConfig{
 			AIPrompt:      cfg.AIPrompt,
 			AIBackend:     cfg.AIBackend,
 			AICLICommand:  cfg.AICLICommand,
@@ -177,16 +314,80 @@ Examples:
 			AIModel:       cfg.AIModel,
 			AIAPIKey:      cfg.AIAPIKey,
 			AIHTTPTimeout: cfg.AIHTTPTimeout.ToDuration(),
-			EntryCount:    len(result.Entries),
+			EntryCount:    entryCount,
 			Style:         string(style),
 			Language:      lang,
+			TokenBudget:   cfg.AITokenBudget,
+			Split:         split,
+			Appendix:      appendix.String(),
+			Cache:         cache,
+			OnResult: func(r ai.Result) {
+				record.Text, record.Model, record.PromptHash = r.Text, r.Model, history.HashPrompt(r.Prompt)
+				if err := archive.Save(record); err != nil {
+					_, _ = fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			},
 		}, promptOverride, recapAPIKey)
 	},
 }
 
+// collectRecap builds the recap of one period, reports failed sources,
+// applies the project filter and estimates working hours.
+func collectRecap(cmd *cobra.Command, cfg *config.Config, sourceConfigs []sources.Config, tr *timerange.TimeRange, timespec, project string, opts recap.BuildOptions) (*recap.RecapResult, error) {
+	result, err := recap.BuildRecap(cmd.Context(), sourceConfigs, tr, timespec, opts, createSource)
+	if err != nil {
+		return nil, err
+	}
+	if recapExplain {
+		renderExplain(os.Stderr, result.Sources)
+	} else {
+		printFailures(os.Stderr, result.Failures())
+	}
+
+	if project != "" {
+		result.Entries = filterByProject(result.Entries, project)
+	}
+	result.Hours = recap.EstimateHours(result.Entries, cfg.GetHoursOptions())
+	return result, nil
+}
+
+// aiChunks renders a recap in parts, per day or per project, for
+// summarizing recaps that exceed the AI token budget. Days are keyed by
+// date for the summary cache.
+func aiChunks(result *recap.RecapResult, by string) ([]ai.Chunk, error) {
+	byDay := by != "project"
+	parts := recap.SplitByDay(result)
+	if !byDay {
+		parts = recap.SplitByProject(result)
+	}
+	chunks := make([]ai.Chunk, 0, len(parts))
+	for _, p := range parts {
+		var b strings.Builder
+		if err := recap.RenderForAI(&b, p); err != nil {
+			return nil, err
+		}
+		chunk := ai.Chunk{Label: p.Timespec, Text: b.String()}
+		if byDay {
+			chunk.Key = p.TimeRange.From.Format("2006-01-02")
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// summaryCache returns the cache of day summaries in the config directory.
+func summaryCache() (*ai.SummaryCache, error) {
+	dir, err := paths.GetConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ikno config directory: %w", err)
+	}
+	return ai.NewSummaryCache(filepath.Join(dir, "cache", "summaries")), nil
+}
+
 // parsedTemplate holds the result of parsing a .md template file.
 type parsedTemplate struct {
 	Description string
+	Where       string // default entry filter, see package filter
 	Body        string
 }
 
@@ -195,18 +396,20 @@ type parsedTemplate struct {
 //
 //	---
 //	description: Short description shown in --styles
+//	where: source in (git, claude)
 //	---
 //
 //	## Prompt
 //
 //	Prompt text here...
 //
+// The optional where line sets the style's default entry filter.
 // Frontmatter and the optional "## Prompt" heading are stripped from the body.
 // Files without frontmatter are returned as-is with an empty description.
 func parseTemplateFile(data []byte) parsedTemplate {
 	lines := strings.Split(string(data), "\n")
 
-	var description, body string
+	var description, where, body string
 
 	// Detect frontmatter: first non-empty line must be "---" (after trimming).
 	firstLine := ""
@@ -230,6 +433,9 @@ func parseTemplateFile(data []byte) parsedTemplate {
 					description = strings.TrimSpace(strings.TrimPrefix(line, "description:"))
 					description = strings.Trim(description, `"'`)
 				}
+				if strings.HasPrefix(line, "where:") {
+					where = trimQuotes(strings.TrimSpace(strings.TrimPrefix(line, "where:")))
+				}
 			}
 			body = strings.TrimSpace(strings.Join(lines[closeIdx+1:], "\n"))
 		} else {
@@ -244,28 +450,35 @@ func parseTemplateFile(data []byte) parsedTemplate {
 		body = strings.TrimSpace(strings.TrimPrefix(body, "## Prompt\n"))
 	}
 
-	return parsedTemplate{Description: description, Body: body}
+	return parsedTemplate{Description: description, Where: where, Body: body}
 }
 
-// loadCustomTemplate looks for ~/.config/ikno/templates/<name>.md, parses its
-// frontmatter, and returns the prompt body with {language} injected.
-// Returns (prompt, true, nil) if found, ("", false, nil) if not found.
-func loadCustomTemplate(name, lang string) (string, bool, error) {
+// trimQuotes removes one pair of matching outer quotes. Filter expressions
+// cannot start with a quote, so a quoted where value is always YAML quoting.
+func trimQuotes(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// loadCustomTemplate looks for ~/.config/ikno/templates/<name>.md and parses
+// it. The body still contains the {language} placeholder.
+// Returns (template, true, nil) if found, (_, false, nil) if not found.
+func loadCustomTemplate(name string) (parsedTemplate, bool, error) {
 	home, err := paths.GetConfigDir()
 	if err != nil {
-		return "", false, err
+		return parsedTemplate{}, false, err
 	}
 	p := filepath.Join(home, "templates", name+".md")
 	data, err := os.ReadFile(p)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", false, nil
+			return parsedTemplate{}, false, nil
 		}
-		return "", false, err
+		return parsedTemplate{}, false, err
 	}
-	tmpl := parseTemplateFile(data)
-	prompt := strings.ReplaceAll(tmpl.Body, "{language}", lang)
-	return prompt, true, nil
+	return parseTemplateFile(data), true, nil
 }
 
 // runListStyles prints all available styles to w.
@@ -378,28 +591,122 @@ func resolveStyle(flagValue, configDefault string) ai.Style {
 	return ai.StyleDigest
 }
 
-// filterByStyle removes entries whose source type is not allowed for the given style.
-// If the style has no source restriction, the entries are returned unchanged.
-func filterByStyle(entries []sources.Entry, style ai.Style) []sources.Entry {
-	allowed := ai.AllowedSources(style)
-	if len(allowed) == 0 {
-		return entries
+// recapFilter combines the filter flags into one expression. --where
+// replaces the style's default filter; --source, --exclude-source and
+// --grep narrow it further.
+func recapFilter(defaultWhere string) (filter.Expr, error) {
+	where, origin := defaultWhere, "style filter"
+	if recapWhere != "" {
+		where, origin = recapWhere, "--where"
+	}
+	expr, err := filter.Parse(where)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", origin, err)
+	}
+
+	var include, exclude filter.Expr
+	if len(recapSources) > 0 {
+		include = filter.In("source", recapSources...)
+	}
+	if len(recapExcludeSources) > 0 {
+		exclude = filter.Not(filter.In("source", recapExcludeSources...))
+	}
+	var grep filter.Expr
+	if recapGrep != "" {
+		if grep, err = filter.Matches("content", recapGrep); err != nil {
+			return nil, fmt.Errorf("invalid --grep: %w", err)
+		}
+	}
+	return filter.And(expr, include, exclude, grep), nil
+}
+
+// resolveProject checks a --project value against the configured projects
+// and returns its canonical name. An empty name means no filter.
+func resolveProject(cfg *config.Config, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	if len(cfg.Projects) == 0 {
+		return "", fmt.Errorf("--project needs a projects section in the config (run ikno config to add one)")
 	}
+	p, ok := projects.Find(cfg.Projects, name)
+	if !ok {
+		return "", fmt.Errorf("unknown project %q (configured: %s)", name, strings.Join(projects.Names(cfg.Projects), ", "))
+	}
+	return p.Name, nil
+}
+
+// filterByProject keeps the entries mapped to the named project.
+func filterByProject(entries []sources.Entry, name string) []sources.Entry {
 	filtered := entries[:0]
 	for _, e := range entries {
-		if slices.Contains(allowed, e.Source) {
+		if e.Metadata[projects.KeyProject] == name {
 			filtered = append(filtered, e)
 		}
 	}
 	return filtered
 }
 
+// printFailures warns about sources that could not be read completely.
+func printFailures(w io.Writer, failures []recap.SourceFailure) {
+	for _, f := range failures {
+		_, _ = fmt.Fprintf(w, "Warning: %v\n", f)
+	}
+}
+
+// renderExplain writes one line per source: status, type, location, entry
+// count, time taken and the failure, if any.
+func renderExplain(w io.Writer, reports []recap.SourceReport) {
+	var failed int
+	var longest time.Duration
+	typeWidth, locWidth := 0, 0
+	for _, r := range reports {
+		if !r.OK() {
+			failed++
+		}
+		longest = max(longest, r.Duration)
+		typeWidth = max(typeWidth, len(r.Type))
+		locWidth = max(locWidth, len(r.Location))
+	}
+
+	header := fmt.Sprintf("Sources: %d read, %d failed (%s)", len(reports)-failed, failed, longest.Round(time.Millisecond))
+	_, _ = fmt.Fprintln(w, ui.StyleSectionHeader.Render(header))
+	for _, r := range reports {
+		status := ui.StyleSuccess.Render(fmt.Sprintf("%-6s", "ok"))
+		if !r.OK() {
+			status = ui.StyleBold.Render(fmt.Sprintf("%-6s", "failed"))
+		}
+		typ := lipgloss.NewStyle().Foreground(ui.SourceColor(r.Type)).Render(fmt.Sprintf("%-*s", typeWidth, r.Type))
+		line := fmt.Sprintf("  %s  %s  %-*s  %5d entries  %6s", status, typ, locWidth, r.Location, r.Entries, r.Duration.Round(time.Millisecond))
+		if r.Failure != nil {
+			line += "  " + ui.StyleMuted.Render(explainFailure(*r.Failure))
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// explainFailure describes a failure without repeating type and location.
+func explainFailure(f recap.SourceFailure) string {
+	switch f.Stage {
+	case recap.StageEnrich:
+		return "diffs missing: " + f.Err.Error()
+	case recap.StageTag:
+		return "projects not mapped: " + f.Err.Error()
+	default:
+		return f.Err.Error()
+	}
+}
+
 // isAIConfigured reports whether the config has a usable AI backend.
 func isAIConfigured(cfg *config.Config) bool {
-	if cfg.AIBackend == "cli" {
+	switch cfg.AIBackend {
+	case ai.BackendCLI:
 		return cfg.AICLICommand != ""
+	case ai.BackendOllama:
+		return true
 	}
-	// api backend: needs an API key (config or env)
+	// api and anthropic backends: need an API key (config or env)
 	return cfg.AIAPIKey != "" || os.Getenv("AI_API_KEY") != ""
 }
 
@@ -448,8 +755,16 @@ func init() {
 	recapCmd.Flags().StringVar(&recapAPIKey, "api-key", "", "API key for AI summary")
 	recapCmd.Flags().BoolVar(&recapRaw, "raw", false, "Unformatted entry dump -- for pipes, scripts, grep")
 	recapCmd.Flags().BoolVar(&recapJSON, "json", false, "Structured JSON output")
-	recapCmd.Flags().StringVar(&recapStyle, "style", "", "Summary style: brief, digest, status, report, retro")
+	recapCmd.Flags().StringVar(&recapWhere, "where", "", `Filter expression, e.g. 'source=git and not content~"^chore"'`)
+	recapCmd.Flags().StringSliceVar(&recapSources, "source", nil, "Only include these source types")
+	recapCmd.Flags().StringSliceVar(&recapExcludeSources, "exclude-source", nil, "Leave out these source types")
+	recapCmd.Flags().StringVar(&recapGrep, "grep", "", "Only include entries whose text matches this regular expression")
+	recapCmd.Flags().StringVar(&recapCompare, "compare", "", "Compare with another period, e.g. lastweek")
+	recapCmd.Flags().BoolVar(&recapNoCache, "no-cache", false, "Summarize every day again instead of reusing cached day summaries")
+	recapCmd.Flags().BoolVar(&recapExplain, "explain", false, "Show entries, timing and errors per source on stderr")
+	recapCmd.Flags().StringVar(&recapStyle, "style", "", "Summary style: brief, digest, status, report, retro, stats, compare")
 	recapCmd.Flags().StringVar(&recapLang, "lang", "", "Report language passed to the AI model (e.g. deutsch, english, greek -- use full names, not ISO codes)")
 	recapCmd.Flags().BoolVar(&recapStyles, "styles", false, "List available styles and exit")
 	recapCmd.Flags().BoolVar(&recapVerbose, "verbose", false, "Show full prompt text when used with --styles")
+	recapCmd.Flags().StringVar(&recapProject, "project", "", "Only include activity mapped to this project (see projects in config)")
 }
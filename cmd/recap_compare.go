@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charemma/ikno/internal/recap"
+	"github.com/charemma/ikno/internal/ui"
+)
+
+// renderCompare writes the comparison as tables: estimated hours and
+// entries per project, then entries per source and category, each with the
+// previous period and the change.
+func renderCompare(w io.Writer, c *recap.Comparison, plain bool) {
+	style := func(s string, render func(...string) string) string {
+		if plain {
+			return s
+		}
+		return render(s)
+	}
+	period := func(r *recap.RecapResult) string {
+		return fmt.Sprintf("%s (%s to %s)", r.Timespec, r.TimeRange.From.Format("2006-01-02"), r.TimeRange.To.Format("2006-01-02"))
+	}
+
+	header := period(c.Current) + " vs " + period(c.Previous)
+	_, _ = fmt.Fprintln(w, style(header, ui.StyleSectionHeader.Render))
+	_, _ = fmt.Fprintln(w)
+
+	width := len("Categories")
+	for _, ds := range [][]recap.Delta{c.Projects, c.Sources, c.Categories} {
+		for _, d := range ds {
+			width = max(width, len(d.Name)+2)
+		}
+	}
+
+	columns := fmt.Sprintf("%-*s  %7s  %7s  %7s  %7s  %5s  %6s", width, "Projects", "hours", "prev", "change", "entries", "prev", "change")
+	_, _ = fmt.Fprintln(w, style(columns, ui.StyleBold.Render))
+	row := func(name string, d recap.Delta) {
+		_, _ = fmt.Fprintf(w, "%-*s  %7s  %7s  %7s  %7d  %5d  %6s\n", width, name,
+			recap.FormatHours(d.CurrentHours), recap.FormatHours(d.PreviousHours), recap.FormatHoursChange(d.HoursChange()),
+			d.Current, d.Previous, recap.FormatChange(d.Change()))
+	}
+	for _, d := range c.Projects {
+		row("  "+d.Name, d)
+	}
+	row(c.Total.Name, c.Total)
+	if len(c.NewProjects) > 0 {
+		_, _ = fmt.Fprintf(w, "%s %s\n", style("New:", ui.StyleMuted.Render), strings.Join(c.NewProjects, ", "))
+	}
+	if len(c.DroppedProjects) > 0 {
+		_, _ = fmt.Fprintf(w, "%s %s\n", style("Dropped:", ui.StyleMuted.Render), strings.Join(c.DroppedProjects, ", "))
+	}
+
+	for _, section := range []struct {
+		title  string
+		deltas []recap.Delta
+	}{{"Sources", c.Sources}, {"Categories", c.Categories}} {
+		_, _ = fmt.Fprintln(w)
+		columns := fmt.Sprintf("%-*s  %7s  %5s  %6s", width, section.title, "entries", "prev", "change")
+		_, _ = fmt.Fprintln(w, style(columns, ui.StyleBold.Render))
+		for _, d := range section.deltas {
+			_, _ = fmt.Fprintf(w, "%-*s  %7d  %5d  %6s\n", width, "  "+d.Name, d.Current, d.Previous, recap.FormatChange(d.Change()))
+		}
+	}
+}
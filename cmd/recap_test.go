@@ -1,6 +1,17 @@
 package cmd
 
-import "testing"
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charemma/ikno/internal/filter"
+	"github.com/charemma/ikno/internal/recap"
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/timerange"
+)
 
 func TestResolveLanguage(t *testing.T) {
 	tests := []struct {
@@ -51,6 +62,7 @@ func TestParseTemplateFile(t *testing.T) {
 		name        string
 		input       string
 		wantDesc    string
+		wantWhere   string
 		wantBodyPfx string // first non-empty line of body
 	}{
 		{
@@ -77,6 +89,13 @@ func TestParseTemplateFile(t *testing.T) {
 			wantDesc:    "Quoted description",
 			wantBodyPfx: "Prompt body.",
 		},
+		{
+			name:        "with where filter",
+			input:       "---\ndescription: Code only\nwhere: 'source=git and not content~\"^chore\"'\n---\n\nPrompt body.\n",
+			wantDesc:    "Code only",
+			wantWhere:   `source=git and not content~"^chore"`,
+			wantBodyPfx: "Prompt body.",
+		},
 	}
 
 	for _, tt := range tests {
@@ -85,6 +104,9 @@ func TestParseTemplateFile(t *testing.T) {
 			if got.Description != tt.wantDesc {
 				t.Errorf("description = %q, want %q", got.Description, tt.wantDesc)
 			}
+			if got.Where != tt.wantWhere {
+				t.Errorf("where = %q, want %q", got.Where, tt.wantWhere)
+			}
 			if tt.wantBodyPfx != "" && len(got.Body) < len(tt.wantBodyPfx) {
 				t.Errorf("body too short: %q", got.Body)
 			} else if tt.wantBodyPfx != "" && got.Body[:len(tt.wantBodyPfx)] != tt.wantBodyPfx {
@@ -93,3 +115,105 @@ func TestParseTemplateFile(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderExplain(t *testing.T) {
+	reports := []recap.SourceReport{
+		{Type: "git", Location: "/code/ikno", Entries: 42, Duration: 300 * time.Millisecond},
+		{Type: "obsidian", Location: "/vault", Duration: 5 * time.Second, Failure: &recap.SourceFailure{
+			Type: "obsidian", Location: "/vault", Stage: recap.StageCollect, TimedOut: true,
+			Err: errors.New("timed out after 5s: context deadline exceeded"),
+		}},
+	}
+
+	var buf strings.Builder
+	renderExplain(&buf, reports)
+	out := buf.String()
+
+	for _, want := range []string{
+		"Sources: 1 read, 1 failed (5s)",
+		"ok      git       /code/ikno     42 entries   300ms",
+		"failed  obsidian  /vault          0 entries      5s  timed out after 5s",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRecapFilter(t *testing.T) {
+	entries := []sources.Entry{
+		{Source: "git", Location: "/code/ikno", Content: "feat: auth tokens"},
+		{Source: "git", Location: "/code/ikno", Content: "chore: bump deps"},
+		{Source: "claude", Location: "/home/u/.claude", Content: "[ikno] auth refactor"},
+		{Source: "obsidian", Location: "/vault", Content: "auth notes"},
+		{Source: "ci", Location: "/code/ikno", Content: "build passed"},
+	}
+	tests := []struct {
+		name         string
+		defaultWhere string
+		where        string
+		include      []string
+		exclude      []string
+		grep         string
+		want         int
+	}{
+		{name: "no filter", want: 5},
+		{name: "style default", defaultWhere: "source in (git, claude)", want: 3},
+		{name: "where replaces default", defaultWhere: "source in (git, claude)", where: "source=obsidian", want: 1},
+		{name: "source flag", include: []string{"git", "ci"}, want: 3},
+		{name: "exclude flag narrows default", defaultWhere: "source in (git, claude)", exclude: []string{"claude"}, want: 2},
+		{name: "grep", grep: "^auth|auth ", want: 3},
+		{name: "all combined", where: `not content~"^chore"`, include: []string{"git", "claude"}, grep: "auth", want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recapWhere, recapSources, recapExcludeSources, recapGrep = tt.where, tt.include, tt.exclude, tt.grep
+			t.Cleanup(func() { recapWhere, recapSources, recapExcludeSources, recapGrep = "", nil, nil, "" })
+
+			expr, err := recapFilter(tt.defaultWhere)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := filter.Apply(slices.Clone(entries), expr)
+			if len(got) != tt.want {
+				t.Errorf("got %d entries, want %d: %+v", len(got), tt.want, got)
+			}
+		})
+	}
+
+	recapWhere = "source="
+	defer func() { recapWhere = "" }()
+	if _, err := recapFilter(""); err == nil || !strings.Contains(err.Error(), "invalid --where") {
+		t.Errorf("expected --where error, got %v", err)
+	}
+}
+
+func TestRenderCompare(t *testing.T) {
+	week := time.Date(2026, 4, 13, 0, 0, 0, 0, time.Local)
+	c := &recap.Comparison{
+		Current:    &recap.RecapResult{Timespec: "thisweek", TimeRange: &timerange.TimeRange{From: week, To: week.AddDate(0, 0, 6)}},
+		Previous:   &recap.RecapResult{Timespec: "lastweek", TimeRange: &timerange.TimeRange{From: week.AddDate(0, 0, -7), To: week.AddDate(0, 0, -1)}},
+		Total:      recap.Delta{Name: "Total", Current: 12, Previous: 9, CurrentHours: 5 * time.Hour, PreviousHours: 6 * time.Hour},
+		Projects:   []recap.Delta{{Name: "ikno", Current: 12, Previous: 4, CurrentHours: 5 * time.Hour, PreviousHours: 2 * time.Hour}},
+		Sources:    []recap.Delta{{Name: "git", Current: 12, Previous: 9}},
+		Categories: []recap.Delta{{Name: "feat", Current: 7, Previous: 2}},
+
+		DroppedProjects: []string{"shop"},
+	}
+
+	var buf strings.Builder
+	renderCompare(&buf, c, true)
+	out := buf.String()
+
+	for _, want := range []string{
+		"thisweek (2026-04-13 to 2026-04-19) vs lastweek (2026-04-06 to 2026-04-12)",
+		"  ikno        5h00m    2h00m   +3h00m       12      4      +8",
+		"Total         5h00m    6h00m   -1h00m       12      9      +3",
+		"Dropped: shop",
+		"  feat            7      2      +5",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
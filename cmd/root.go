@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 )
@@ -21,10 +24,16 @@ without time tracking or background agents. Think about what you did, not how to
 	Version: Version,
 }
 
+// Execute runs the root command. Ctrl-C or SIGTERM cancels the command's
+// context, which stops running sources and kills their child processes.
 func Execute() {
 	rootCmd.Version = Version
 	rootCmd.SetVersionTemplate(fmt.Sprintf("ikno version %s (commit: %s, built: %s)\n", Version, Commit, Date))
-	if err := rootCmd.Execute(); err != nil {
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
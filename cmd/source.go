@@ -4,12 +4,20 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/charemma/ikno/internal/config"
 	"github.com/charemma/ikno/internal/git"
 	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/sources/activitywatch"
+	"github.com/charemma/ikno/internal/sources/ci"
 	claudesource "github.com/charemma/ikno/internal/sources/claude"
+	"github.com/charemma/ikno/internal/sources/containers"
+	"github.com/charemma/ikno/internal/sources/records"
+	"github.com/charemma/ikno/internal/sources/taskwarrior"
+	"github.com/charemma/ikno/internal/sources/timewarrior"
+	"github.com/charemma/ikno/internal/sources/watson"
 	"github.com/charemma/ikno/internal/storage"
 	"github.com/charemma/ikno/internal/ui"
 	"github.com/charmbracelet/lipgloss"
@@ -20,16 +28,30 @@ var (
 	gitAuthors       []string
 	markdownTags     []string
 	markdownHeadings []string
+	trackTasks       bool
+	includeFolders   []string
+	excludeFolders   []string
+	includeTags      []string
+	excludeTags      []string
+	ciUser           string
+	ciProvider       string
+	kubeContext      string
+	containerTools   []string
+	eventsFile       string
+	fieldMappings    []string
+	timeFormat       string
+	csvDelimiter     string
 	addType          string
 	addYes           bool
 )
 
-// knownTypes is the set of built-in source type identifiers.
-var knownTypes = map[string]bool{
-	"git":      true,
-	"markdown": true,
-	"obsidian": true,
-	"claude":   true,
+// sourceTypes are the built-in source type identifiers in the order they
+// are documented.
+var sourceTypes = []string{
+	"git", "markdown", "obsidian", "claude", "org", "logseq", "todotxt",
+	"taskwarrior", "git-bug", "editor", "ci", "containers",
+	"timewarrior", "watson", "activitywatch", "toggl", "clockify",
+	"jsonl", "csv",
 }
 
 var sourceCmd = &cobra.Command{
@@ -44,10 +66,29 @@ var sourceAddCmd = &cobra.Command{
 	Long: `Add a new data source for tracking.
 
 Supported types:
-  git      - Track git repository commits
-  markdown - Track markdown files (notes, journals, etc.)
-  obsidian - Track Obsidian vault file changes
-  claude   - Track Claude Code session interactions
+  git           - Track git repository commits
+  markdown      - Track markdown files (notes, journals, etc.)
+  obsidian      - Track Obsidian vault file changes
+  claude        - Track Claude Code session interactions
+  org           - Track org-mode files (closed items, CLOCK entries, journals)
+  logseq        - Track a Logseq graph (journal pages, clocked blocks)
+  todotxt       - Track todo.txt/done.txt (completed and due tasks)
+  taskwarrior   - Track Taskwarrior tasks completed, started or annotated (default: ~/.task)
+  git-bug       - Track issues stored in git refs by git-bug (--author filters)
+  editor        - Track file edits from VS Code, JetBrains and Vim history (default: ~)
+  ci            - Track GitHub Actions / GitLab CI runs you triggered (default: all git sources)
+  containers    - Track kubectl changes, Helm releases and Docker/Podman events (default: ~/.kube/config)
+
+Time trackers (path optional where a default location exists):
+  timewarrior   - Timewarrior data files (default: ~/.timewarrior/data)
+  watson        - Watson frames (default: ~/.config/watson)
+  activitywatch - ActivityWatch server (default: http://localhost:5600)
+  toggl         - Toggl Track detailed CSV export (file or directory)
+  clockify      - Clockify detailed CSV export (file or directory)
+
+Generic record files (--map timestamp=FIELD --map content=FIELD|TEMPLATE):
+  jsonl         - JSON Lines file or directory (.jsonl, .ndjson), fields as dot paths
+  csv           - CSV file or directory, fields as column headers
 
 With auto-detection:
   ikno source add                      detect and add cwd
@@ -64,16 +105,33 @@ Examples:
   ikno source add git . --author user@example.com
   ikno source add markdown ~/Obsidian/Daily
   ikno source add markdown ~/notes --tags work,done
+  ikno source add markdown ~/notes --tasks
   ikno source add obsidian ~/Documents/Obsidian
-  ikno source add claude`,
+  ikno source add obsidian ~/Documents/Obsidian --tasks
+  ikno source add obsidian ~/Documents/Obsidian --exclude-folders Journal --exclude-tags private
+  ikno source add claude
+  ikno source add org ~/org
+  ikno source add logseq ~/logseq-graph
+  ikno source add todotxt ~/todo
+  ikno source add taskwarrior
+  ikno source add git-bug ~/code/my-project --author user@example.com
+  ikno source add editor
+  ikno source add ci
+  ikno source add ci ~/code/my-project --ci-user octocat
+  ikno source add containers --kube-context prod --tools kubectl,helm
+  ikno source add containers --events-file ~/.docker-events.jsonl
+  ikno source add timewarrior
+  ikno source add activitywatch http://localhost:5600
+  ikno source add toggl ~/Downloads/Toggl_time_entries.csv
+  ikno source add jsonl ~/logs/deploys.jsonl --map timestamp=ts --map content="deployed {service} {version}" --map env=target.env
+  ikno source add csv ~/exports/pages.csv --map timestamp="Created At" --map content=Summary --time-format unix`,
 	Args: cobra.RangeArgs(0, 2),
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
 			// Complete both known types and directories
-			types := []string{"git", "markdown", "obsidian", "claude"}
-			return types, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveFilterDirs
+			return sourceTypes, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveFilterDirs
 		}
-		if len(args) == 1 && knownTypes[args[0]] {
+		if len(args) == 1 && slices.Contains(sourceTypes, args[0]) {
 			return nil, cobra.ShellCompDirectiveFilterDirs
 		}
 		return nil, cobra.ShellCompDirectiveNoFileComp
@@ -87,7 +145,7 @@ Examples:
 		sourceType, path, explicit := parseAddArgs(args)
 
 		// Validate ambiguous 2-arg case where first arg is not a known type
-		if len(args) == 2 && !knownTypes[args[0]] {
+		if len(args) == 2 && !slices.Contains(sourceTypes, args[0]) {
 			return fmt.Errorf("unrecognized source type %q -- use: ikno source add <type> <path> or ikno source add <path>", args[0])
 		}
 
@@ -140,7 +198,7 @@ func parseAddArgs(args []string) (sourceType, path string, explicit bool) {
 		cwd, _ := os.Getwd()
 		path = cwd
 	case 1:
-		if knownTypes[args[0]] {
+		if slices.Contains(sourceTypes, args[0]) {
 			// legacy: "ikno source add claude" or "ikno source add git"
 			sourceType = args[0]
 			explicit = true
@@ -149,7 +207,7 @@ func parseAddArgs(args []string) (sourceType, path string, explicit bool) {
 			path = args[0]
 		}
 	case 2:
-		if knownTypes[args[0]] {
+		if slices.Contains(sourceTypes, args[0]) {
 			// legacy: "ikno source add git ~/path"
 			sourceType = args[0]
 			path = args[1]
@@ -278,7 +336,14 @@ func addSingleSource(store *storage.Store, sourceType, path string) error {
 		Metadata: make(map[string]string),
 	}
 
-	if path == "" && sourceType != "claude" {
+	if path == "" && sourceType == "ci" {
+		return addCISources(store)
+	}
+	if path == "" {
+		path = defaultSourcePath(sourceType)
+		srcCfg.Path = path
+	}
+	if path == "" {
 		return fmt.Errorf("path is required for source type: %s", sourceType)
 	}
 
@@ -328,15 +393,63 @@ func addSingleSource(store *storage.Store, sourceType, path string) error {
 		if len(markdownHeadings) > 0 {
 			srcCfg.Metadata["headings"] = strings.Join(markdownHeadings, ",")
 		}
+		if trackTasks {
+			srcCfg.Metadata["tasks"] = "true"
+		}
 	case "obsidian":
-		// no additional metadata
-	case "claude":
-		if path == "" {
-			path = claudesource.DefaultClaudeHome()
-			srcCfg.Path = path
+		if trackTasks {
+			srcCfg.Metadata["tasks"] = "true"
+		}
+		if len(includeFolders) > 0 {
+			srcCfg.Metadata["include_folders"] = strings.Join(includeFolders, ",")
+		}
+		if len(excludeFolders) > 0 {
+			srcCfg.Metadata["exclude_folders"] = strings.Join(excludeFolders, ",")
+		}
+		if len(includeTags) > 0 {
+			srcCfg.Metadata["include_tags"] = strings.Join(includeTags, ",")
+		}
+		if len(excludeTags) > 0 {
+			srcCfg.Metadata["exclude_tags"] = strings.Join(excludeTags, ",")
+		}
+	case "git-bug":
+		if len(gitAuthors) > 0 {
+			srcCfg.Metadata["author"] = strings.Join(gitAuthors, ",")
+		}
+	case "ci":
+		if ciUser != "" {
+			srcCfg.Metadata["user"] = ciUser
 		}
+		if ciProvider != "" {
+			srcCfg.Metadata["provider"] = ciProvider
+		}
+		if err := ci.NewCISource(path, srcCfg.Metadata).Validate(); err != nil {
+			return err
+		}
+	case "containers":
+		for _, t := range containerTools {
+			if !slices.Contains(containers.DefaultTools, t) {
+				return fmt.Errorf("unknown tool %q (supported: %s)", t, strings.Join(containers.DefaultTools, ", "))
+			}
+		}
+		if len(containerTools) > 0 {
+			srcCfg.Metadata["tools"] = strings.Join(containerTools, ",")
+		}
+		if kubeContext != "" {
+			srcCfg.Metadata["context"] = kubeContext
+		}
+		if eventsFile != "" {
+			srcCfg.Metadata["events_file"] = eventsFile
+		}
+	case records.FormatJSONL, records.FormatCSV:
+		if err := addRecordMapping(srcCfg.Metadata); err != nil {
+			return err
+		}
+	case "claude", "org", "logseq", "todotxt", "taskwarrior", "editor",
+		"timewarrior", "watson", "activitywatch", "toggl", "clockify":
+		// no type-specific options
 	default:
-		return fmt.Errorf("unsupported source type: %s (supported: git, markdown, obsidian, claude)", sourceType)
+		return fmt.Errorf("unsupported source type: %s (supported: %s)", sourceType, strings.Join(sourceTypes, ", "))
 	}
 
 	if err := store.AddSource(srcCfg); err != nil {
@@ -351,6 +464,85 @@ func addSingleSource(store *storage.Store, sourceType, path string) error {
 	return nil
 }
 
+// addCISources adds a ci source for every registered git source, reusing
+// the repository's remote to find its CI runs. Repositories whose remote
+// is not on GitHub or GitLab are skipped.
+func addCISources(store *storage.Store) error {
+	registered, err := store.GetSources()
+	if err != nil {
+		return fmt.Errorf("failed to load sources: %w", err)
+	}
+
+	added := 0
+	for _, src := range registered {
+		if src.Type != "git" {
+			continue
+		}
+		if err := addSingleSource(store, "ci", src.Path); err != nil {
+			_, _ = fmt.Println(ui.StyleMuted.Render(fmt.Sprintf("skipped %s: %v", src.Path, err)))
+			continue
+		}
+		added++
+	}
+	if added == 0 {
+		return fmt.Errorf("no git sources with a GitHub or GitLab remote to add CI runs for")
+	}
+	return nil
+}
+
+// addRecordMapping stores the --map, --time-format and --delimiter flags
+// in the metadata of a jsonl or csv source and checks the result.
+func addRecordMapping(meta map[string]string) error {
+	for _, m := range fieldMappings {
+		key, field, ok := strings.Cut(m, "=")
+		key, field = strings.TrimSpace(key), strings.TrimSpace(field)
+		if !ok || key == "" || field == "" {
+			return fmt.Errorf("invalid --map %q: expected key=field", m)
+		}
+		switch key {
+		case records.KeyTimestamp, records.KeyContent, records.KeyLocation:
+			meta[key] = field
+		default:
+			meta[records.FieldPrefix+key] = field
+		}
+	}
+	if timeFormat != "" {
+		meta[records.KeyTimeFormat] = timeFormat
+	}
+	if csvDelimiter != "" {
+		meta[records.KeyDelimiter] = csvDelimiter
+	}
+
+	if _, err := records.ParseMapping(meta); err != nil {
+		return fmt.Errorf("invalid field mapping: %w", err)
+	}
+	return nil
+}
+
+// defaultSourcePath returns the well-known location for source types that
+// can be added without a path, or "" if the type requires one.
+func defaultSourcePath(sourceType string) string {
+	switch sourceType {
+	case "claude":
+		return claudesource.DefaultClaudeHome()
+	case "timewarrior":
+		return timewarrior.DefaultDataDir()
+	case "watson":
+		return watson.DefaultDir()
+	case "activitywatch":
+		return activitywatch.DefaultURL
+	case "taskwarrior":
+		return taskwarrior.DefaultDataDir()
+	case "editor":
+		home, _ := os.UserHomeDir()
+		return home
+	case "containers":
+		return containers.DefaultKubeconfig()
+	default:
+		return ""
+	}
+}
+
 // isTTY reports whether stdout is a terminal.
 func isTTY() bool {
 	info, err := os.Stdout.Stat()
@@ -448,9 +640,22 @@ func init() {
 	sourceCmd.AddCommand(sourceListCmd)
 	sourceCmd.AddCommand(sourceRemoveCmd)
 
-	sourceAddCmd.Flags().StringSliceVar(&gitAuthors, "author", nil, "Git author email(s) to filter commits (can be specified multiple times)")
+	sourceAddCmd.Flags().StringSliceVar(&gitAuthors, "author", nil, "Git author email(s) to filter commits and git-bug activity (can be specified multiple times)")
 	sourceAddCmd.Flags().StringSliceVar(&markdownTags, "tags", nil, "Filter markdown by tags (comma-separated)")
 	sourceAddCmd.Flags().StringSliceVar(&markdownHeadings, "headings", nil, "Filter markdown by headings (comma-separated)")
+	sourceAddCmd.Flags().BoolVar(&trackTasks, "tasks", false, "Track completed and due checkbox tasks (markdown, obsidian)")
+	sourceAddCmd.Flags().StringSliceVar(&includeFolders, "include-folders", nil, "Only report obsidian notes in these folders (comma-separated)")
+	sourceAddCmd.Flags().StringSliceVar(&excludeFolders, "exclude-folders", nil, "Skip obsidian notes in these folders (comma-separated)")
+	sourceAddCmd.Flags().StringSliceVar(&includeTags, "include-tags", nil, "Only report obsidian notes with these tags (comma-separated)")
+	sourceAddCmd.Flags().StringSliceVar(&excludeTags, "exclude-tags", nil, "Skip obsidian notes with these tags (comma-separated)")
+	sourceAddCmd.Flags().StringVar(&ciUser, "ci-user", "", "GitHub/GitLab login whose CI runs to track (default: owner of GITHUB_TOKEN/GITLAB_TOKEN)")
+	sourceAddCmd.Flags().StringVar(&ciProvider, "ci-provider", "", "CI provider for self-hosted remotes: github or gitlab")
+	sourceAddCmd.Flags().StringSliceVar(&containerTools, "tools", nil, "Tools to query for containers: kubectl, helm, docker, podman (comma-separated, default: all installed)")
+	sourceAddCmd.Flags().StringVar(&kubeContext, "kube-context", "", "Kubernetes context for kubectl and helm (default: current context)")
+	sourceAddCmd.Flags().StringVar(&eventsFile, "events-file", "", "JSON log captured with docker events --format '{{json .}}' (containers)")
+	sourceAddCmd.Flags().StringArrayVar(&fieldMappings, "map", nil, "Map a record field for jsonl/csv: timestamp=, content=, location= or <metadata key>= (can be specified multiple times)")
+	sourceAddCmd.Flags().StringVar(&timeFormat, "time-format", "", "Timestamp format for jsonl/csv: rfc3339 (default), unix, unix_ms or a Go layout")
+	sourceAddCmd.Flags().StringVar(&csvDelimiter, "delimiter", "", `CSV field delimiter (default ",", use "\t" for tabs)`)
 	sourceAddCmd.Flags().StringVarP(&addType, "type", "t", "", "Force source type (overrides auto-detection)")
 	sourceAddCmd.Flags().BoolVarP(&addYes, "yes", "y", false, "Skip interactive confirmation, add all discovered sources")
 }
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/config"
+	"github.com/charemma/ikno/internal/recap"
+	"github.com/charemma/ikno/internal/ui"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsJSON    bool
+	statsProject string
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [timespec]",
+	Short: "Show activity statistics: heatmap, streaks, weekdays, hours",
+	Long: `Show statistics of your activity, counted from the entries without AI.
+
+  - a calendar heatmap of entries per day, one column per week
+  - entries and estimated hours per weekday
+  - entries per hour of the day
+  - entries per source and estimated hours per project
+  - active days and working streaks
+
+A streak is a run of days with activity; weekends without activity do not
+break it, and neither does today before the first entry. Hours are
+estimated as in ikno hours.
+
+The default period is the last 90 days.
+
+Examples:
+  ikno stats
+  ikno stats "last 365 days"
+  ikno stats lastmonth --project acme-shop
+  ikno stats thisweek --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		timespec := "last 90 days"
+		if len(args) > 0 {
+			timespec = args[0]
+		}
+
+		result, err := buildHours(cmd.Context(), cfg, timespec, statsProject)
+		if err != nil || result == nil {
+			return err
+		}
+		stats := recap.ComputeStats(result, time.Now())
+
+		if statsJSON {
+			return recap.RenderStatsJSON(os.Stdout, stats)
+		}
+
+		if len(result.Entries) == 0 {
+			_, _ = fmt.Fprintln(os.Stdout, ui.StyleMuted.Render("No activity found for "+timespec))
+			return nil
+		}
+
+		renderStats(os.Stdout, stats, ui.IsPlain(cmd))
+		return nil
+	},
+}
+
+// statsProjectLimit is the number of projects listed; the rest are summed
+// up in one line.
+const statsProjectLimit = 10
+
+// heatmapLevels are the heatmap cells from no activity to the busiest days.
+var heatmapLevels = []string{"·", "░", "▒", "▓", "█"}
+
+// renderStats writes the summary line, the heatmap and the breakdowns.
+func renderStats(w io.Writer, s *recap.Stats, plain bool) {
+	style := func(str string, st lipgloss.Style) string {
+		if plain {
+			return str
+		}
+		return st.Render(str)
+	}
+
+	header := fmt.Sprintf("Activity, %s to %s", s.From.Format("2006-01-02"), s.To.Format("2006-01-02"))
+	_, _ = fmt.Fprintln(w, style(header, ui.StyleSectionHeader))
+	summary := fmt.Sprintf("%d entries · ~%s · %d of %d days active · streak %s (longest %s)",
+		s.Total, recap.FormatHours(s.Hours), s.ActiveDays, len(s.Days), streakDays(s.CurrentStreak), streakDays(s.LongestStreak))
+	_, _ = fmt.Fprintln(w, summary)
+	_, _ = fmt.Fprintln(w)
+
+	renderHeatmap(w, s, style)
+	_, _ = fmt.Fprintln(w)
+
+	_, _ = fmt.Fprintln(w, style("Weekdays", ui.StyleBold))
+	mostEntries := 0
+	for _, n := range s.Weekdays {
+		mostEntries = max(mostEntries, n)
+	}
+	for i, n := range s.Weekdays {
+		name := time.Weekday((i + 1) % 7).String()[:3]
+		_, _ = fmt.Fprintf(w, "  %s  %s  %6d  %7s\n", name, countBar(n, mostEntries), n, recap.FormatHours(s.WeekdayHours[i]))
+	}
+	_, _ = fmt.Fprintln(w)
+
+	_, _ = fmt.Fprintln(w, style("Hours of day", ui.StyleBold))
+	first, last, busiest := -1, 0, 0
+	for h, n := range s.HoursOfDay {
+		if n > 0 {
+			if first < 0 {
+				first = h
+			}
+			last = h
+		}
+		busiest = max(busiest, n)
+	}
+	for h := max(first, 0); h <= last; h++ {
+		_, _ = fmt.Fprintf(w, "  %02d   %s  %6d\n", h, countBar(s.HoursOfDay[h], busiest), s.HoursOfDay[h])
+	}
+	_, _ = fmt.Fprintln(w)
+
+	width := 0
+	for _, src := range s.Sources {
+		width = max(width, len(src.Source))
+	}
+	for i, p := range s.Projects {
+		if i < statsProjectLimit {
+			width = max(width, len(p.Project))
+		}
+	}
+
+	_, _ = fmt.Fprintln(w, style("Sources", ui.StyleBold))
+	for _, src := range s.Sources {
+		name := style(fmt.Sprintf("%-*s", width, src.Source), lipgloss.NewStyle().Foreground(ui.SourceColor(src.Source)))
+		_, _ = fmt.Fprintf(w, "  %s  %s  %6d  %3d%%\n", name, countBar(src.Entries, s.Sources[0].Entries), src.Entries, src.Entries*100/max(s.Total, 1))
+	}
+
+	if len(s.Projects) > 0 {
+		_, _ = fmt.Fprintln(w)
+		_, _ = fmt.Fprintln(w, style("Projects", ui.StyleBold))
+		var rest time.Duration
+		for i, p := range s.Projects {
+			if i >= statsProjectLimit {
+				rest += p.Duration
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "  %-*s  %s  %7s\n", width, p.Project, hoursBar(p.Duration, s.Projects[0].Duration), recap.FormatHours(p.Duration))
+		}
+		if n := len(s.Projects) - statsProjectLimit; n > 0 {
+			_, _ = fmt.Fprintln(w, style(fmt.Sprintf("  %d more projects, %s", n, recap.FormatHours(rest)), ui.StyleMuted))
+		}
+	}
+}
+
+// renderHeatmap writes one row per weekday and one column per week, with
+// month names above the weeks they start in.
+func renderHeatmap(w io.Writer, s *recap.Stats, style func(string, lipgloss.Style) string) {
+	if len(s.Days) == 0 {
+		return
+	}
+	busiest := 0
+	for _, d := range s.Days {
+		busiest = max(busiest, d.Entries)
+	}
+
+	// Weeks start on the Monday on or before the first day.
+	offset := (int(s.Days[0].Date.Weekday()) + 6) % 7
+	start := s.Days[0].Date.AddDate(0, 0, -offset)
+	weeks := (offset + len(s.Days) + 6) / 7
+	entries := make(map[time.Time]int, len(s.Days))
+	for _, d := range s.Days {
+		entries[d.Date] = d.Entries
+	}
+
+	// A month is named above the week its first day falls in. A label
+	// too close to the next one, as for a partial first month, is dropped.
+	type label struct {
+		col  int
+		name string
+	}
+	var labels []label
+	lastMonth := time.Month(0)
+	for week := range weeks {
+		m := start.AddDate(0, 0, week*7+6).Month()
+		if m == lastMonth {
+			continue
+		}
+		lastMonth = m
+		if n := len(labels); n > 0 && labels[n-1].col+4 > week*2 {
+			labels = labels[:n-1]
+		}
+		labels = append(labels, label{week * 2, m.String()[:3]})
+	}
+	var months strings.Builder
+	months.WriteString("     ")
+	for _, l := range labels {
+		months.WriteString(strings.Repeat(" ", 5+l.col-months.Len()))
+		months.WriteString(l.name)
+	}
+	_, _ = fmt.Fprintln(w, style(months.String(), ui.StyleMuted))
+
+	cell := lipgloss.NewStyle().Foreground(ui.ColorObsidian)
+	for weekday := range 7 {
+		var row strings.Builder
+		row.WriteString(style(time.Weekday((weekday + 1) % 7).String()[:3], ui.StyleMuted) + "  ")
+		for week := range weeks {
+			day := start.AddDate(0, 0, week*7+weekday)
+			n, ok := entries[day]
+			switch {
+			case !ok:
+				row.WriteString("  ")
+			case n == 0:
+				row.WriteString(style(heatmapLevels[0], ui.StyleMuted) + " ")
+			default:
+				row.WriteString(style(heatmapLevels[heatmapLevel(n, busiest)], cell) + " ")
+			}
+		}
+		_, _ = fmt.Fprintln(w, strings.TrimRight(row.String(), " "))
+	}
+
+	var legend strings.Builder
+	legend.WriteString("     less ")
+	for i, l := range heatmapLevels {
+		if i == 0 {
+			legend.WriteString(style(l, ui.StyleMuted) + " ")
+		} else {
+			legend.WriteString(style(l, cell) + " ")
+		}
+	}
+	legend.WriteString("more")
+	_, _ = fmt.Fprintln(w, legend.String())
+}
+
+// heatmapLevel maps an entry count to levels 1-4 in quarters of the
+// busiest day.
+func heatmapLevel(n, busiest int) int {
+	return min(max((4*n+busiest-1)/busiest, 1), 4)
+}
+
+func streakDays(s recap.Streak) string {
+	if s.Days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", s.Days)
+}
+
+func countBar(n, most int) string {
+	if most <= 0 {
+		return strings.Repeat("░", hoursBarWidth)
+	}
+	k := (hoursBarWidth*n + most/2) / most
+	return strings.Repeat("█", k) + strings.Repeat("░", hoursBarWidth-k)
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().BoolVar(&statsJSON, "json", false, "Structured JSON output")
+	statsCmd.Flags().StringVar(&statsProject, "project", "", "Only include activity mapped to this project (see projects in config)")
+	statsCmd.Flags().Bool("plain", false, "Plain output without colors")
+}
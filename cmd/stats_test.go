@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charemma/ikno/internal/recap"
+)
+
+func TestRenderStats(t *testing.T) {
+	// Thursday 2026-04-30 to Tuesday 2026-05-12.
+	from := time.Date(2026, 4, 30, 0, 0, 0, 0, time.Local)
+	s := &recap.Stats{From: from, To: from.AddDate(0, 0, 12), Total: 12, Hours: 5 * time.Hour, ActiveDays: 3}
+	for i := range 13 {
+		s.Days = append(s.Days, recap.DayStats{Date: from.AddDate(0, 0, i)})
+	}
+	s.Days[0].Entries, s.Days[4].Entries, s.Days[12].Entries = 8, 1, 3
+	s.Weekdays = [7]int{1, 3, 0, 8, 0, 0, 0}
+	s.HoursOfDay[9], s.HoursOfDay[11] = 10, 2
+	s.Sources = []recap.SourceCount{{Source: "git", Entries: 9}, {Source: "claude", Entries: 3}}
+	s.Projects = []recap.ProjectHours{{Project: "ikno", Duration: 5 * time.Hour}}
+	s.CurrentStreak = recap.Streak{Days: 1}
+	s.LongestStreak = recap.Streak{Days: 2}
+
+	var buf strings.Builder
+	renderStats(&buf, s, true)
+	out := buf.String()
+
+	for _, want := range []string{
+		"12 entries · ~5h00m · 3 of 13 days active · streak 1 day (longest 2 days)",
+		"     May\n",
+		"Mon    ░ ·\n",
+		"Tue    · ▒\n",
+		"Thu  █ ·\n",
+		"Sun  · ·\n",
+		"  Thu  ████████████████████       8",
+		"  10   ░░░░░░░░░░░░░░░░░░░░       0",
+		"  11   ████░░░░░░░░░░░░░░░░       2",
+		"  git     ████████████████████       9   75%",
+		"  ikno    ████████████████████    5h00m",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// anthropicVersion is the Messages API version requested.
+const anthropicVersion = "2023-06-01"
+
+// defaultMaxTokens limits the length of a summary. The Messages API
+// requires a limit; recaps stay far below it.
+const defaultMaxTokens = 8192
+
+// AnthropicClient speaks the native Anthropic Messages API.
+type AnthropicClient struct {
+	BaseURL    string // e.g. https://api.anthropic.com/v1/
+	APIKey     string
+	Model      string
+	MaxTokens  int          // optional; defaults to defaultMaxTokens
+	httpClient *http.Client // optional; if nil, uses the global default
+	retry      *retryPolicy // optional; if nil, uses defaultRetry
+}
+
+type anthropicRequest struct {
+	Model     string        `json:"model"`
+	MaxTokens int           `json:"max_tokens"`
+	System    string        `json:"system,omitempty"`
+	Messages  []chatMessage `json:"messages"`
+	Stream    bool          `json:"stream"`
+}
+
+// anthropicEvent is the data of a streaming event. Only text deltas and
+// errors are of interest; message_start, ping and the other events are
+// skipped.
+type anthropicEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// StreamCompletion sends a Messages request and streams the response to w.
+// Failed requests are retried, see streamWithRetry.
+func (c *AnthropicClient) StreamCompletion(ctx context.Context, systemPrompt, userContent string, w io.Writer) error {
+	if userContent == "" {
+		return fmt.Errorf("no recap content to summarize")
+	}
+
+	client := httpClient
+	if c.httpClient != nil {
+		client = c.httpClient
+	}
+
+	return streamWithRetry(ctx, client, c.retry, func(partial string) (*http.Request, error) {
+		return c.buildRequest(ctx, systemPrompt, userContent, partial)
+	}, parseAnthropicStream, w)
+}
+
+// BuildRequest constructs the HTTP request without sending it.
+func (c *AnthropicClient) BuildRequest(ctx context.Context, systemPrompt, userContent string) (*http.Request, error) {
+	return c.buildRequest(ctx, systemPrompt, userContent, "")
+}
+
+// buildRequest constructs the request. A non-empty partial answer is sent
+// as the start of the assistant turn, which the model continues. The API
+// rejects trailing whitespace there; streamWithRetry never passes any.
+func (c *AnthropicClient) buildRequest(ctx context.Context, systemPrompt, userContent, partial string) (*http.Request, error) {
+	if c.BaseURL == "" {
+		return nil, fmt.Errorf("ai_base_url is not configured (e.g. https://api.anthropic.com/v1/)")
+	}
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("no API key for the Anthropic API (set ai_api_key or the AI_API_KEY env var)")
+	}
+
+	url := strings.TrimRight(c.BaseURL, "/") + "/messages"
+
+	reqBody := anthropicRequest{
+		Model:     c.Model,
+		MaxTokens: c.MaxTokens,
+		System:    systemPrompt,
+		Messages:  []chatMessage{{Role: "user", Content: userContent}},
+		Stream:    true,
+	}
+	if reqBody.MaxTokens <= 0 {
+		reqBody.MaxTokens = defaultMaxTokens
+	}
+	if partial != "" {
+		reqBody.Messages = append(reqBody.Messages, chatMessage{Role: "assistant", Content: partial})
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", c.APIKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	return req, nil
+}
+
+// anthropicErrorStatus maps the error types of the Messages API to their
+// HTTP statuses, to classify errors sent within a stream.
+var anthropicErrorStatus = map[string]int{
+	"invalid_request_error": http.StatusBadRequest,
+	"authentication_error":  http.StatusUnauthorized,
+	"billing_error":         http.StatusPaymentRequired,
+	"permission_error":      http.StatusForbidden,
+	"not_found_error":       http.StatusNotFound,
+	"request_too_large":     http.StatusRequestEntityTooLarge,
+	"rate_limit_error":      http.StatusTooManyRequests,
+	"api_error":             http.StatusInternalServerError,
+	"timeout_error":         http.StatusGatewayTimeout,
+	"overloaded_error":      529,
+}
+
+// parseAnthropicStream writes the text of content_block_delta events to w.
+// An error event, e.g. when the API is overloaded mid-stream, ends the
+// stream with an error, and so does a stream without message_stop.
+func parseAnthropicStream(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+
+		var event anthropicEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			continue
+		}
+
+		switch event.Type {
+		case "content_block_delta":
+			if event.Delta.Type == "text_delta" {
+				_, _ = fmt.Fprint(w, event.Delta.Text)
+			}
+		case "error":
+			return streamError(anthropicErrorStatus[event.Error.Type], event.Error.Type+": "+event.Error.Message)
+		case "message_stop":
+			_, _ = fmt.Fprintln(w)
+			return nil
+		}
+	}
+	return errStreamEnded(scanner.Err())
+}
@@ -0,0 +1,100 @@
+package ai
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAnthropicClient_StreamCompletion(t *testing.T) {
+	var got anthropicRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "sk-ant-test" || r.Header.Get("anthropic-version") != anthropicVersion {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Error("the Messages API takes no Bearer token")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, `event: message_start
+data: {"type":"message_start","message":{"id":"msg_1","role":"assistant","content":[]}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+event: ping
+data: {"type": "ping"}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"## Done"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"\n- shipped login"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":9}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`)
+	}))
+	defer server.Close()
+
+	c := &AnthropicClient{BaseURL: server.URL + "/v1/", APIKey: "sk-ant-test", Model: "claude-test"}
+	var out strings.Builder
+	if err := c.StreamCompletion(t.Context(), "Summarize.", "commit a", &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "## Done\n- shipped login\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+	if got.Model != "claude-test" || got.System != "Summarize." || got.MaxTokens != defaultMaxTokens || !got.Stream {
+		t.Errorf("unexpected request: %+v", got)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].Role != "user" || got.Messages[0].Content != "commit a" {
+		t.Errorf("expected one user message, got %+v", got.Messages)
+	}
+}
+
+func TestAnthropicClient_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") == "invalid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`)
+			return
+		}
+		_, _ = io.WriteString(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"partial\"}}\n\n"+
+			"event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n")
+	}))
+	defer server.Close()
+
+	c := &AnthropicClient{BaseURL: server.URL, APIKey: "invalid", Model: "m", retry: fastRetry}
+	if err := c.StreamCompletion(t.Context(), "p", "c", io.Discard); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected status error, got %v", err)
+	}
+
+	c.APIKey = "sk-ant-test"
+	if err := c.StreamCompletion(t.Context(), "p", "c", io.Discard); err == nil || !strings.Contains(err.Error(), "overloaded_error") {
+		t.Errorf("expected stream error, got %v", err)
+	}
+
+	c.APIKey = ""
+	if _, err := c.BuildRequest(t.Context(), "p", "c"); err == nil {
+		t.Error("expected error for missing API key")
+	}
+}
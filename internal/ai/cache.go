@@ -0,0 +1,151 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SummaryCache stores the notes of single days on disk, so that recaps of
+// a week, month or quarter reuse the days summarized before and only
+// summarize days whose activity changed.
+//
+// Notes are stored as <dir>/<day>/<hash>.md, the hash covering the whole
+// CacheKey. Outdated notes are never read again and can be deleted with
+// the day's directory.
+type SummaryCache struct {
+	dir string
+
+	// Refresh skips reading the cache: every day is summarized again and
+	// the new notes replace the cached ones.
+	Refresh bool
+}
+
+// NewSummaryCache returns a cache stored in dir, which is created on the
+// first write.
+func NewSummaryCache(dir string) *SummaryCache {
+	return &SummaryCache{dir: dir}
+}
+
+// CacheKey identifies the notes of one day. Notes are reused only when
+// every field matches.
+type CacheKey struct {
+	Day         string // e.g. "2026-03-02"
+	Fingerprint string // hash of the day's rendered activity
+	Style       string
+	Language    string
+	Model       string // model name, or the command of the CLI backend
+	Prompt      string // instructions of the final summary
+}
+
+// Fingerprint hashes the rendered activity of a day. Any change of its
+// entries, such as a new commit or an edited note, changes the fingerprint.
+func Fingerprint(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+func (k CacheKey) path(dir string) string {
+	h := sha256.New()
+	for _, field := range []string{k.Day, k.Fingerprint, k.Style, k.Language, k.Model, k.Prompt} {
+		_, _ = fmt.Fprintf(h, "%d:%s\n", len(field), field)
+	}
+	return filepath.Join(dir, k.Day, hex.EncodeToString(h.Sum(nil))[:32]+".md")
+}
+
+// Get returns the cached notes for key.
+func (c *SummaryCache) Get(key CacheKey) (string, bool) {
+	if c.Refresh {
+		return "", false
+	}
+	data, err := os.ReadFile(key.path(c.dir))
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// Put stores notes for key. The file is written to a temporary name first,
+// so that an interrupted run leaves no partial notes behind.
+func (c *SummaryCache) Put(key CacheKey, notes string) error {
+	path := key.path(c.dir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".notes-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if _, err := tmp.WriteString(notes); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// dayPrompt condenses the activity of one day into notes. The instructions
+// of the final summary are included, so that the notes keep what the style
+// needs and are written in its language.
+const dayPrompt = `You are condensing one day of a developer's activity log. A later summary of the whole period sees only the notes of each day and follows these instructions:
+
+---
+%s
+---
+
+Day: %s
+
+Write up to 15 bullets, in the language the instructions ask for:
+- what was done, one concrete thing per bullet, prefixed with the project in brackets, e.g. "[my-app] added OAuth login"
+- numbers worth keeping: hours, session lengths, counts of commits, tasks or failed runs
+- open work, blockers and decisions that are still pending
+
+Leave out commit hashes, file paths, diffs and small talk. If the day contains nothing notable, write one bullet saying so. Output only the bullets.`
+
+// summarizeDays returns notes for each day of chunks, read from the cache
+// or summarized with dayPrompt and stored. base holds the key fields shared
+// by all days. Days larger than budget tokens are truncated first. status
+// is called before each request; the number of days read from the cache is
+// returned with the notes.
+func summarizeDays(ctx context.Context, complete completeFunc, cache *SummaryCache, chunks []Chunk, base CacheKey, budget int, status func(string)) ([]Chunk, int, error) {
+	notes := make([]Chunk, 0, len(chunks))
+	cached := 0
+	for i, c := range chunks {
+		key := base
+		key.Day = c.Key
+		key.Fingerprint = Fingerprint(c.Text)
+		if text, ok := cache.Get(key); ok {
+			notes = append(notes, Chunk{Label: c.Label, Key: c.Key, Text: text})
+			cached++
+			continue
+		}
+
+		status(fmt.Sprintf("Summarizing day %d of %d (%s)...", i+1, len(chunks), c.Label))
+		text := c.Text
+		if budget > 0 {
+			text = truncateToBudget(text, budget)
+		}
+		var out strings.Builder
+		if err := complete(ctx, fmt.Sprintf(dayPrompt, base.Prompt, c.Label), text, &out); err != nil {
+			return nil, cached, fmt.Errorf("failed to summarize %s: %w", c.Label, err)
+		}
+		text = strings.TrimSpace(out.String())
+		if err := cache.Put(key, text); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		notes = append(notes, Chunk{Label: c.Label, Key: c.Key, Text: text})
+	}
+	return notes, cached, nil
+}
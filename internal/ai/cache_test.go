@@ -0,0 +1,80 @@
+package ai
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSummaryCache(t *testing.T) {
+	cache := NewSummaryCache(filepath.Join(t.TempDir(), "summaries"))
+	key := CacheKey{Day: "2026-03-02", Fingerprint: Fingerprint("commit a"), Style: "digest", Language: "english", Model: "m"}
+
+	if _, ok := cache.Get(key); ok {
+		t.Fatal("empty cache returned notes")
+	}
+	if err := cache.Put(key, "- did a"); err != nil {
+		t.Fatal(err)
+	}
+	if notes, ok := cache.Get(key); !ok || notes != "- did a" {
+		t.Errorf("Get = %q, %t", notes, ok)
+	}
+
+	for _, other := range []CacheKey{
+		{Day: key.Day, Fingerprint: Fingerprint("commit b"), Style: "digest", Language: "english", Model: "m"},
+		{Day: key.Day, Fingerprint: key.Fingerprint, Style: "report", Language: "english", Model: "m"},
+		{Day: key.Day, Fingerprint: key.Fingerprint, Style: "digest", Language: "deutsch", Model: "m"},
+		{Day: key.Day, Fingerprint: key.Fingerprint, Style: "digest", Language: "english", Model: "n"},
+	} {
+		if _, ok := cache.Get(other); ok {
+			t.Errorf("notes reused for a different key: %+v", other)
+		}
+	}
+
+	cache.Refresh = true
+	if _, ok := cache.Get(key); ok {
+		t.Error("Refresh should skip reading the cache")
+	}
+}
+
+func TestSummarizeDays(t *testing.T) {
+	cache := NewSummaryCache(t.TempDir())
+	var requests []string
+	complete := func(_ context.Context, prompt, content string, w io.Writer) error {
+		requests = append(requests, content)
+		_, _ = io.WriteString(w, "- notes of "+strings.TrimSpace(content)+"\n")
+		return nil
+	}
+	days := []Chunk{
+		{Label: "2026-03-02 Mon", Key: "2026-03-02", Text: "commit a\n"},
+		{Label: "2026-03-03 Tue", Key: "2026-03-03", Text: "commit b\n"},
+	}
+	base := CacheKey{Style: "digest", Language: "english", Model: "m", Prompt: "Summarize."}
+
+	notes, cached, err := summarizeDays(t.Context(), complete, cache, days, base, 0, func(string) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached != 0 || len(requests) != 2 || notes[1].Text != "- notes of commit b" {
+		t.Fatalf("first run: cached %d, requests %d, notes %+v", cached, len(requests), notes)
+	}
+
+	// A new entry on Tuesday: only Tuesday is summarized again.
+	requests = nil
+	days[1].Text = "commit b\ncommit c\n"
+	notes, cached, err = summarizeDays(t.Context(), complete, cache, days, base, 0, func(string) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached != 1 || len(requests) != 1 || notes[0].Text != "- notes of commit a" || !strings.Contains(notes[1].Text, "commit c") {
+		t.Errorf("second run: cached %d, requests %q, notes %+v", cached, requests, notes)
+	}
+
+	entries, err := os.ReadDir(cache.dir)
+	if err != nil || len(entries) != 2 || entries[0].Name() != "2026-03-02" {
+		t.Errorf("expected one directory per day, got %v (%v)", entries, err)
+	}
+}
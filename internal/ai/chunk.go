@@ -0,0 +1,156 @@
+package ai
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// bytesPerToken is a rough average for English text and code. Tokenizers
+// differ between models; the estimate only needs to keep requests well
+// inside the context window, so budgets should leave headroom.
+const bytesPerToken = 4
+
+// DefaultTokenBudget is the estimated number of input tokens sent in one
+// request unless configured otherwise.
+const DefaultTokenBudget = 100_000
+
+// maxReduceRounds bounds how often notes are condensed again when they
+// still exceed the budget.
+const maxReduceRounds = 4
+
+// EstimateTokens returns a rough token count for text.
+func EstimateTokens(text string) int {
+	return (len(text) + bytesPerToken - 1) / bytesPerToken
+}
+
+// Chunk is a part of the activity that can be summarized on its own, such
+// as one day or one project.
+type Chunk struct {
+	Label string // e.g. "2026-03-02 Mon" or a project name
+	Key   string // identifies a day across runs, e.g. "2026-03-02"; empty for other parts
+	Text  string // rendered activity
+}
+
+// mapPrompt condenses one chunk into notes for the final summary, written
+// in the language of the recap so that the final summary does not mix
+// languages.
+const mapPrompt = `You are condensing one part of a developer's activity log. Your notes replace this part of the log in a later summary, which only sees the notes.
+
+Part: %s (%d of %d)
+
+Write up to 15 bullets in %s:
+- what was done, one concrete thing per bullet, prefixed with the project in brackets, e.g. "[my-app] added OAuth login"
+- numbers worth keeping: hours, session lengths, counts of commits, tasks or failed runs
+- open work, blockers and decisions that are still pending
+
+Leave out commit hashes, file paths, diffs and small talk. If the part contains nothing notable, write one bullet saying so. Output only the bullets.`
+
+// summarizeInChunks condenses chunks into notes that fit within budget
+// tokens: neighbouring chunks are packed up to the budget, each pack is
+// condensed with mapPrompt, and the notes are condensed again while they
+// are still too large. It returns the notes, written in language (English
+// if empty), as input for the final prompt. status is called before each
+// request.
+func summarizeInChunks(ctx context.Context, complete completeFunc, chunks []Chunk, budget int, period, language string, status func(string)) (string, error) {
+	language = cmp.Or(language, "English")
+	for round := 1; ; round++ {
+		packs := packChunks(chunks, budget)
+		notes := make([]Chunk, 0, len(packs))
+		for i, pack := range packs {
+			if round == 1 {
+				status(fmt.Sprintf("Summarizing part %d of %d (%s)...", i+1, len(packs), pack.Label))
+			} else {
+				status(fmt.Sprintf("Condensing notes %d of %d...", i+1, len(packs)))
+			}
+			var out bytes.Buffer
+			if err := complete(ctx, fmt.Sprintf("Period: %s\n\n%s", period, fmt.Sprintf(mapPrompt, pack.Label, i+1, len(packs), language)), pack.Text, &out); err != nil {
+				return "", fmt.Errorf("failed to summarize %s: %w", pack.Label, err)
+			}
+			notes = append(notes, Chunk{Label: pack.Label, Text: strings.TrimSpace(out.String())})
+		}
+
+		combined := renderNotes(notes, period, notesTooLong)
+		if EstimateTokens(combined) <= budget || len(notes) == 1 || round == maxReduceRounds {
+			return truncateToBudget(combined, budget), nil
+		}
+		chunks = notes
+	}
+}
+
+// Introductions of the notes for the final prompt.
+const (
+	notesTooLong = "The activity log was too long for one request. Each part below condenses the full log of the days or projects in its heading. Treat these notes as the activity log."
+	notesPerDay  = "Each part below condenses the full activity log of the day in its heading. Treat these notes as the activity log."
+)
+
+// renderNotes writes the condensed parts as input for the final prompt.
+func renderNotes(notes []Chunk, period, intro string) string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "# Work Recap (condensed)\n\n")
+	_, _ = fmt.Fprintf(&b, "**Period:** %s\n", period)
+	_, _ = fmt.Fprintf(&b, "**Parts:** %d\n\n", len(notes))
+	_, _ = fmt.Fprintf(&b, "%s\n\n", intro)
+	for _, n := range notes {
+		_, _ = fmt.Fprintf(&b, "## Part: %s\n\n%s\n\n", n.Label, n.Text)
+	}
+	return b.String()
+}
+
+// packChunks merges neighbouring chunks while the merged text fits within
+// budget tokens, so that many small days take few requests. Chunks larger
+// than the budget on their own are truncated.
+func packChunks(chunks []Chunk, budget int) []Chunk {
+	var packs []Chunk
+	var labels []string
+	var text strings.Builder
+	flush := func() {
+		if len(labels) == 0 {
+			return
+		}
+		packs = append(packs, Chunk{Label: packLabel(labels), Text: text.String()})
+		labels = nil
+		text.Reset()
+	}
+
+	for _, c := range chunks {
+		body := truncateToBudget(c.Text, budget)
+		if len(labels) > 0 && EstimateTokens(text.String())+EstimateTokens(body) > budget {
+			flush()
+		}
+		labels = append(labels, c.Label)
+		text.WriteString(body)
+		if !strings.HasSuffix(body, "\n") {
+			text.WriteString("\n")
+		}
+	}
+	flush()
+	return packs
+}
+
+// packLabel names a pack of chunks: the label itself, or the first and
+// last label with the count in between.
+func packLabel(labels []string) string {
+	switch len(labels) {
+	case 1:
+		return labels[0]
+	case 2:
+		return labels[0] + ", " + labels[1]
+	}
+	return fmt.Sprintf("%s ... %s (%d parts)", labels[0], labels[len(labels)-1], len(labels))
+}
+
+// truncateToBudget cuts text at the last line break within budget tokens
+// and notes how much was left out.
+func truncateToBudget(text string, budget int) string {
+	if EstimateTokens(text) <= budget {
+		return text
+	}
+	cut := budget * bytesPerToken
+	if i := strings.LastIndexByte(text[:cut], '\n'); i > 0 {
+		cut = i + 1
+	}
+	return text[:cut] + fmt.Sprintf("\n[... %d more tokens left out to fit the token budget ...]\n", EstimateTokens(text[cut:]))
+}
@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	if got := EstimateTokens(""); got != 0 {
+		t.Errorf("EstimateTokens(\"\") = %d", got)
+	}
+	if got := EstimateTokens(strings.Repeat("a", 401)); got != 101 {
+		t.Errorf("EstimateTokens(401 bytes) = %d, want 101", got)
+	}
+}
+
+func TestPackChunks(t *testing.T) {
+	day := func(label string, tokens int) Chunk {
+		return Chunk{Label: label, Text: strings.Repeat(strings.Repeat("x", 39)+"\n", tokens/10)}
+	}
+	chunks := []Chunk{day("Mon", 30), day("Tue", 30), day("Wed", 30), day("Thu", 250), day("Fri", 10)}
+
+	packs := packChunks(chunks, 100)
+
+	var labels []string
+	for _, p := range packs {
+		labels = append(labels, p.Label)
+		if EstimateTokens(p.Text) > 100+20 {
+			t.Errorf("pack %s has %d tokens, over the budget", p.Label, EstimateTokens(p.Text))
+		}
+	}
+	want := []string{"Mon ... Wed (3 parts)", "Thu", "Fri"}
+	if strings.Join(labels, "|") != strings.Join(want, "|") {
+		t.Errorf("packs = %q, want %q", labels, want)
+	}
+	if !strings.Contains(packs[1].Text, "more tokens left out to fit the token budget") {
+		t.Errorf("oversized chunk was not truncated: %d tokens", EstimateTokens(packs[1].Text))
+	}
+}
+
+func TestTruncateToBudget(t *testing.T) {
+	text := "line one\nline two\nline three\n"
+	if got := truncateToBudget(text, 100); got != text {
+		t.Errorf("text within budget changed: %q", got)
+	}
+	got := truncateToBudget(text, 5) // 20 bytes
+	if !strings.HasPrefix(got, "line one\nline two\n\n[... ") {
+		t.Errorf("expected cut at a line break, got %q", got)
+	}
+}
+
+func TestSummarizeInChunks(t *testing.T) {
+	var prompts []string
+	complete := func(ctx context.Context, prompt, content string, w io.Writer) error {
+		prompts = append(prompts, prompt)
+		_, err := fmt.Fprintf(w, "- [ikno] did %d tokens of work\n", EstimateTokens(content))
+		return err
+	}
+	var chunks []Chunk
+	for i := range 10 {
+		chunks = append(chunks, Chunk{Label: fmt.Sprintf("day %d", i+1), Text: strings.Repeat("commit message\n", 40)})
+	}
+	var statuses []string
+
+	notes, err := summarizeInChunks(t.Context(), complete, chunks, 400, "lastmonth", "German", func(s string) { statuses = append(statuses, s) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 150 tokens per day, two days per request.
+	if len(prompts) != 5 {
+		t.Fatalf("expected 5 requests, got %d", len(prompts))
+	}
+	if !strings.Contains(prompts[0], "Period: lastmonth") || !strings.Contains(prompts[0], "Part: day 1, day 2 (1 of 5)") {
+		t.Errorf("unexpected map prompt:\n%s", prompts[0])
+	}
+	if !strings.Contains(prompts[0], "bullets in German") {
+		t.Errorf("map prompt ignores the language:\n%s", prompts[0])
+	}
+	if statuses[4] != "Summarizing part 5 of 5 (day 9, day 10)..." {
+		t.Errorf("unexpected status %q", statuses[4])
+	}
+	for _, want := range []string{"# Work Recap (condensed)", "**Parts:** 5", "## Part: day 3, day 4", "- [ikno] did 300 tokens of work"} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("notes missing %q:\n%s", want, notes)
+		}
+	}
+}
+
+func TestSummarizeInChunks_Error(t *testing.T) {
+	complete := func(ctx context.Context, prompt, content string, w io.Writer) error {
+		return fmt.Errorf("rate limited")
+	}
+	_, err := summarizeInChunks(t.Context(), complete, []Chunk{{Label: "2026-03-02 Mon", Text: "x"}}, 100, "p", "", func(string) {})
+	if err == nil || err.Error() != "failed to summarize 2026-03-02 Mon: rate limited" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -1,17 +1,23 @@
 package ai
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os/exec"
 	"strings"
+	"time"
 )
 
+// cliWaitDelay bounds how long RunCLI waits for the AI command's output
+// pipes to close after it was killed on cancellation.
+const cliWaitDelay = 2 * time.Second
+
 // RunCLI pipes recapContent via stdin to a CLI tool and connects its output to w.
 // The command string is split on whitespace into program + args. The prompt is
-// appended as the last argument.
-func RunCLI(command, prompt, recapContent string, w io.Writer) error {
+// appended as the last argument. The command is killed when ctx is done.
+func RunCLI(ctx context.Context, command, prompt, recapContent string, w io.Writer) error {
 	parts := strings.Fields(command)
 	if len(parts) == 0 {
 		return fmt.Errorf("ai_cli_command is empty")
@@ -25,12 +31,16 @@ func RunCLI(command, prompt, recapContent string, w io.Writer) error {
 	}
 
 	args := append(parts[1:], prompt)
-	cmd := exec.Command(bin, args...)
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.WaitDelay = cliWaitDelay
 	cmd.Stdin = strings.NewReader(recapContent)
 	cmd.Stdout = w
 	cmd.Stderr = w
 
 	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s canceled: %w", bin, ctx.Err())
+		}
 		var exitErr *exec.ExitError
 		if errors.As(err, &exitErr) && exitErr.ExitCode() == 127 {
 			return fmt.Errorf("AI command %q not found in PATH.\n\nCheck that the command is installed and accessible:\n  which %s\n\nOr update the command:\n  ikno config set ai_cli_command \"/full/path/to/%s -p\"", bin, bin, bin)
@@ -1,12 +1,15 @@
 package ai
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestRunCLI_EmptyCommand(t *testing.T) {
-	err := RunCLI("", "prompt", "content", &strings.Builder{})
+	err := RunCLI(t.Context(), "", "prompt", "content", &strings.Builder{})
 	if err == nil {
 		t.Fatal("expected error for empty command")
 	}
@@ -18,7 +21,7 @@ func TestRunCLI_EmptyCommand(t *testing.T) {
 func TestRunCLI_CommandSplitting(t *testing.T) {
 	var buf strings.Builder
 	// Use echo which just prints its arguments, ignoring stdin
-	err := RunCLI("echo hello", "world", "stdin content", &buf)
+	err := RunCLI(t.Context(), "echo hello", "world", "stdin content", &buf)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -32,7 +35,7 @@ func TestRunCLI_CommandSplitting(t *testing.T) {
 func TestRunCLI_StdinPassthrough(t *testing.T) {
 	var buf strings.Builder
 	// sh -c runs the prompt as a shell command; "cat" reads stdin
-	err := RunCLI("sh -c", "cat", "stdin content", &buf)
+	err := RunCLI(t.Context(), "sh -c", "cat", "stdin content", &buf)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -44,8 +47,25 @@ func TestRunCLI_StdinPassthrough(t *testing.T) {
 }
 
 func TestRunCLI_NonexistentCommand(t *testing.T) {
-	err := RunCLI("nonexistent-command-xyz", "prompt", "content", &strings.Builder{})
+	err := RunCLI(t.Context(), "nonexistent-command-xyz", "prompt", "content", &strings.Builder{})
 	if err == nil {
 		t.Fatal("expected error for nonexistent command")
 	}
 }
+
+func TestRunCLI_Canceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := RunCLI(ctx, "sh -c", "sleep 10", "", &strings.Builder{})
+	if err == nil {
+		t.Fatal("expected error for canceled command")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("command was not killed, took %s", elapsed)
+	}
+}
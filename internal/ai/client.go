@@ -50,6 +50,7 @@ type Client struct {
 	APIKey     string
 	Model      string
 	httpClient *http.Client // optional; if nil, uses the global default
+	retry      *retryPolicy // optional; if nil, uses defaultRetry
 }
 
 type chatRequest struct {
@@ -68,41 +69,36 @@ type streamChunk struct {
 		Delta struct {
 			Content string `json:"content"`
 		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
 	} `json:"choices"`
+	Error json.RawMessage `json:"error"`
 }
 
 // StreamCompletion sends a chat completion request and streams the response to w.
+// Failed requests are retried, see streamWithRetry.
 func (c *Client) StreamCompletion(ctx context.Context, systemPrompt, userContent string, w io.Writer) error {
 	if userContent == "" {
 		return fmt.Errorf("no recap content to summarize")
 	}
 
-	req, err := c.BuildRequest(ctx, systemPrompt, userContent)
-	if err != nil {
-		return err
-	}
-
 	client := httpClient
 	if c.httpClient != nil {
 		client = c.httpClient
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API returned %d: %s", resp.StatusCode, string(respBody))
-	}
-
-	return parseSSEStream(resp.Body, w)
+	return streamWithRetry(ctx, client, c.retry, func(partial string) (*http.Request, error) {
+		return c.buildRequest(ctx, systemPrompt, userContent, partial)
+	}, parseSSEStream, w)
 }
 
 // BuildRequest constructs the HTTP request without sending it.
 func (c *Client) BuildRequest(ctx context.Context, systemPrompt, userContent string) (*http.Request, error) {
+	return c.buildRequest(ctx, systemPrompt, userContent, "")
+}
+
+// buildRequest constructs the request; a non-empty partial answer is sent
+// back with resumePrompt to have it continued.
+func (c *Client) buildRequest(ctx context.Context, systemPrompt, userContent, partial string) (*http.Request, error) {
 	if c.BaseURL == "" {
 		return nil, fmt.Errorf("ai_base_url is not configured (set it in ~/.config/ikno/config.yaml or use a provider like Anthropic, OpenAI, or ollama)")
 	}
@@ -117,6 +113,11 @@ func (c *Client) BuildRequest(ctx context.Context, systemPrompt, userContent str
 		},
 		Stream: true,
 	}
+	if partial != "" {
+		reqBody.Messages = append(reqBody.Messages,
+			chatMessage{Role: "assistant", Content: partial},
+			chatMessage{Role: "user", Content: resumePrompt})
+	}
 
 	body, err := json.Marshal(reqBody)
 	if err != nil {
@@ -136,8 +137,12 @@ func (c *Client) BuildRequest(ctx context.Context, systemPrompt, userContent str
 	return req, nil
 }
 
+// parseSSEStream writes the content deltas to w. The stream is complete at
+// "[DONE]" or a finish reason; one that ends before either broke off.
 func parseSSEStream(r io.Reader, w io.Writer) error {
 	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	complete := false
 	for scanner.Scan() {
 		line := scanner.Text()
 
@@ -147,6 +152,7 @@ func parseSSEStream(r io.Reader, w io.Writer) error {
 
 		data := strings.TrimPrefix(line, "data: ")
 		if data == "[DONE]" {
+			complete = true
 			break
 		}
 
@@ -154,12 +160,26 @@ func parseSSEStream(r io.Reader, w io.Writer) error {
 		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
 			continue
 		}
+		if len(chunk.Error) > 0 && string(chunk.Error) != "null" {
+			return streamError(0, errorMessage([]byte(data)))
+		}
 
-		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
-			_, _ = fmt.Fprint(w, chunk.Choices[0].Delta.Content)
+		if len(chunk.Choices) > 0 {
+			if chunk.Choices[0].Delta.Content != "" {
+				_, _ = fmt.Fprint(w, chunk.Choices[0].Delta.Content)
+			}
+			if chunk.Choices[0].FinishReason != nil {
+				complete = true
+			}
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return errStreamEnded(err)
+	}
+	if !complete {
+		return errStreamEnded(nil)
+	}
 
 	_, _ = fmt.Fprintln(w)
-	return scanner.Err()
+	return nil
 }
@@ -0,0 +1,135 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultOllamaURL is where a local Ollama server listens.
+const DefaultOllamaURL = "http://localhost:11434"
+
+// OllamaClient speaks Ollama's native /api/chat endpoint.
+type OllamaClient struct {
+	BaseURL    string // e.g. http://localhost:11434
+	Model      string
+	httpClient *http.Client // optional; if nil, uses the global default
+	retry      *retryPolicy // optional; if nil, uses defaultRetry
+}
+
+type ollamaRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+}
+
+// ollamaChunk is one line of the streamed response.
+type ollamaChunk struct {
+	Message struct {
+		Content string `json:"content"`
+	} `json:"message"`
+	Done  bool   `json:"done"`
+	Error string `json:"error"`
+}
+
+// StreamCompletion sends a chat request and streams the response to w.
+// Failed requests are retried, see streamWithRetry.
+func (c *OllamaClient) StreamCompletion(ctx context.Context, systemPrompt, userContent string, w io.Writer) error {
+	if userContent == "" {
+		return fmt.Errorf("no recap content to summarize")
+	}
+
+	client := httpClient
+	if c.httpClient != nil {
+		client = c.httpClient
+	}
+
+	err := streamWithRetry(ctx, client, c.retry, func(partial string) (*http.Request, error) {
+		return c.buildRequest(ctx, systemPrompt, userContent, partial)
+	}, parseOllamaStream, w)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Kind == KindNetwork && apiErr.Err != nil {
+		apiErr.Message += " (is ollama serve running?)"
+	}
+	return err
+}
+
+// BuildRequest constructs the HTTP request without sending it.
+func (c *OllamaClient) BuildRequest(ctx context.Context, systemPrompt, userContent string) (*http.Request, error) {
+	return c.buildRequest(ctx, systemPrompt, userContent, "")
+}
+
+// buildRequest constructs the request; a non-empty partial answer is sent
+// back with resumePrompt to have it continued.
+func (c *OllamaClient) buildRequest(ctx context.Context, systemPrompt, userContent, partial string) (*http.Request, error) {
+	if c.Model == "" {
+		return nil, fmt.Errorf("ai_model is not configured (set it to a pulled Ollama model, e.g. ikno config set ai_model llama3.2)")
+	}
+
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultOllamaURL
+	}
+	url := strings.TrimRight(baseURL, "/") + "/api/chat"
+
+	reqBody := ollamaRequest{
+		Model: c.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userContent},
+		},
+		Stream: true,
+	}
+	if partial != "" {
+		reqBody.Messages = append(reqBody.Messages,
+			chatMessage{Role: "assistant", Content: partial},
+			chatMessage{Role: "user", Content: resumePrompt})
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+// parseOllamaStream writes the message content of each JSON line to w
+// until the line marked done; a stream that ends before it broke off.
+func parseOllamaStream(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var chunk ollamaChunk
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			continue
+		}
+		if chunk.Error != "" {
+			return streamError(http.StatusInternalServerError, chunk.Error)
+		}
+
+		_, _ = fmt.Fprint(w, chunk.Message.Content)
+		if chunk.Done {
+			_, _ = fmt.Fprintln(w)
+			return nil
+		}
+	}
+	return errStreamEnded(scanner.Err())
+}
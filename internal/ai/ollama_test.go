@@ -0,0 +1,77 @@
+package ai
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOllamaClient_StreamCompletion(t *testing.T) {
+	var got ollamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = io.WriteString(w, `{"model":"llama3.2","message":{"role":"assistant","content":"## Done"},"done":false}
+{"model":"llama3.2","message":{"role":"assistant","content":"\n- shipped login"},"done":false}
+{"model":"llama3.2","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","eval_count":12}
+`)
+	}))
+	defer server.Close()
+
+	c := &OllamaClient{BaseURL: server.URL + "/", Model: "llama3.2"}
+	var out strings.Builder
+	if err := c.StreamCompletion(t.Context(), "Summarize.", "commit a", &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "## Done\n- shipped login\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+	if got.Model != "llama3.2" || !got.Stream || len(got.Messages) != 2 || got.Messages[0].Role != "system" || got.Messages[1].Content != "commit a" {
+		t.Errorf("unexpected request: %+v", got)
+	}
+}
+
+func TestOllamaClient_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Model == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error":"model \"missing\" not found, try pulling it first"}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"message":{"content":"partial"},"done":false}
+{"error":"out of memory"}
+`)
+	}))
+	defer server.Close()
+
+	c := &OllamaClient{BaseURL: server.URL, Model: "missing", retry: fastRetry}
+	if err := c.StreamCompletion(t.Context(), "p", "c", io.Discard); err == nil || !strings.Contains(err.Error(), "try pulling it first") {
+		t.Errorf("expected status error, got %v", err)
+	}
+
+	c.Model = "llama3.2"
+	if err := c.StreamCompletion(t.Context(), "p", "c", io.Discard); err == nil || !strings.Contains(err.Error(), "out of memory") {
+		t.Errorf("expected stream error, got %v", err)
+	}
+
+	c.Model = ""
+	if _, err := c.BuildRequest(t.Context(), "p", "c"); err == nil {
+		t.Error("expected error for missing model")
+	}
+
+	req, err := (&OllamaClient{Model: "llama3.2"}).BuildRequest(t.Context(), "p", "c")
+	if err != nil || req.URL.String() != DefaultOllamaURL+"/api/chat" {
+		t.Errorf("expected the default URL, got %v, %v", req, err)
+	}
+}
@@ -0,0 +1,351 @@
+package ai
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/ui"
+	"github.com/mattn/go-isatty"
+)
+
+// ErrorKind classifies why an AI request failed.
+type ErrorKind string
+
+const (
+	KindAuth          ErrorKind = "auth"           // missing or invalid API key, no access to the model
+	KindQuota         ErrorKind = "quota"          // credits or quota used up
+	KindRateLimit     ErrorKind = "rate_limit"     // too many requests
+	KindOverloaded    ErrorKind = "overloaded"     // provider overloaded or unavailable
+	KindContextLength ErrorKind = "context_length" // input too long for the model
+	KindNetwork       ErrorKind = "network"        // connection failed or the stream broke off
+	KindTimeout       ErrorKind = "timeout"        // no response within ai_http_timeout
+	KindRequest       ErrorKind = "request"        // other rejected requests
+	KindServer        ErrorKind = "server"         // other server errors
+)
+
+// kindInfo describes a kind in error messages and says what helps.
+var kindInfo = map[ErrorKind]struct{ text, hint string }{
+	KindAuth:          {"authentication failed", "check ai_api_key or the AI_API_KEY env var"},
+	KindQuota:         {"quota or credits exhausted", "check the plan and billing of your provider account"},
+	KindRateLimit:     {"rate limited", "try again later"},
+	KindOverloaded:    {"the API is overloaded", "try again later"},
+	KindContextLength: {"the input is too long for the model", "lower ai_token_budget so that long recaps are summarized in parts"},
+	KindNetwork:       {"network error", "check the connection and ai_base_url"},
+	KindTimeout:       {"no response in time", "raise ai_http_timeout for slow endpoints"},
+	KindRequest:       {"request rejected", "check ai_model and ai_base_url"},
+	KindServer:        {"server error", "try again later"},
+}
+
+// APIError is a failed request to an AI API, classified so that the CLI
+// can say what went wrong and what helps.
+type APIError struct {
+	Kind       ErrorKind
+	Status     int           // HTTP status; 0 for network errors and errors in the stream
+	Message    string        // the provider's message
+	RetryAfter time.Duration // requested by the provider with Retry-After
+	Attempts   int           // requests made, including retries
+	Err        error         // underlying network error, if any
+}
+
+func (e *APIError) Error() string {
+	info := kindInfo[e.Kind]
+	var b strings.Builder
+	b.WriteString(cmp.Or(info.text, string(e.Kind)))
+	if e.Status != 0 {
+		_, _ = fmt.Fprintf(&b, " (%d)", e.Status)
+	}
+	if e.Message != "" {
+		_, _ = fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.Attempts > 1 {
+		_, _ = fmt.Fprintf(&b, ", gave up after %d attempts", e.Attempts)
+	}
+	if info.hint != "" {
+		_, _ = fmt.Fprintf(&b, " -- %s", info.hint)
+	}
+	return b.String()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the same request may succeed later.
+func (e *APIError) Retryable() bool {
+	switch e.Kind {
+	case KindRateLimit, KindOverloaded, KindNetwork, KindServer:
+		return true
+	}
+	return false
+}
+
+// classify derives the kind from the HTTP status and the error text.
+// Quota and context errors share statuses with other errors (429 and 400),
+// so the text is checked first.
+func classify(status int, text string) ErrorKind {
+	t := strings.ToLower(text)
+	switch {
+	case containsAny(t, "context length", "context_length", "context window", "maximum context", "prompt is too long", "input is too long", "too many tokens") || status == http.StatusRequestEntityTooLarge:
+		return KindContextLength
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return KindAuth
+	case status == http.StatusPaymentRequired || containsAny(t, "insufficient_quota", "exceeded your current quota", "credit balance", "billing"):
+		return KindQuota
+	case status == http.StatusTooManyRequests:
+		return KindRateLimit
+	case status == 529 || status == http.StatusServiceUnavailable || strings.Contains(t, "overloaded"):
+		return KindOverloaded
+	case status >= 500:
+		return KindServer
+	}
+	return KindRequest
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// responseError classifies a response with a status other than 200.
+func responseError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	return &APIError{
+		Kind:       classify(resp.StatusCode, string(body)),
+		Status:     resp.StatusCode,
+		Message:    errorMessage(body),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// streamError classifies an error reported inside a response stream.
+// status is the HTTP status the error would have had, 0 if unknown.
+func streamError(status int, message string) *APIError {
+	return &APIError{Kind: classify(status, message), Message: message}
+}
+
+// networkError classifies a failed connection. Cancellation is returned
+// as is, it is no API failure.
+func networkError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	kind := KindNetwork
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		kind = KindTimeout
+	}
+	return &APIError{Kind: kind, Message: err.Error(), Err: err}
+}
+
+// errStreamEnded reports a response stream that broke off before the
+// provider marked it complete.
+func errStreamEnded(err error) *APIError {
+	if err != nil {
+		return &APIError{Kind: KindNetwork, Message: "the response stream broke off: " + err.Error(), Err: err}
+	}
+	return &APIError{Kind: KindNetwork, Message: "the response stream ended before the answer was complete"}
+}
+
+// errorMessage extracts the message from the error bodies of OpenAI,
+// Anthropic and Ollama, falling back to the shortened body.
+func errorMessage(body []byte) string {
+	var parsed struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+	}
+	if json.Unmarshal(body, &parsed) == nil {
+		var nested struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		}
+		var plain string
+		switch {
+		case json.Unmarshal(parsed.Error, &nested) == nil && nested.Message != "":
+			if nested.Type != "" && !strings.Contains(nested.Message, nested.Type) {
+				return nested.Type + ": " + nested.Message
+			}
+			return nested.Message
+		case json.Unmarshal(parsed.Error, &plain) == nil && plain != "":
+			return plain
+		case parsed.Message != "":
+			return parsed.Message
+		}
+	}
+	msg := strings.TrimSpace(string(body))
+	if len(msg) > 300 {
+		msg = msg[:300] + "..."
+	}
+	return msg
+}
+
+// parseRetryAfter reads a Retry-After header in seconds or as HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+		return time.Duration(secs * float64(time.Second))
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// retryPolicy bounds how often and how long failed requests are retried.
+type retryPolicy struct {
+	attempts      int           // requests in total, including the first
+	baseDelay     time.Duration // wait before the first retry, doubled for each further one
+	maxDelay      time.Duration // longest wait without Retry-After
+	maxRetryAfter time.Duration // a longer Retry-After gives up instead of waiting
+}
+
+// defaultRetry rides out rate limits and overloads of a few seconds up to
+// about a minute: waits of 1s, 2s and 4s, or what Retry-After asks for.
+var defaultRetry = &retryPolicy{
+	attempts:      4,
+	baseDelay:     time.Second,
+	maxDelay:      30 * time.Second,
+	maxRetryAfter: 2 * time.Minute,
+}
+
+// delay returns the wait before retry number n (1 for the first retry):
+// Retry-After if the provider sent one, otherwise exponential backoff with
+// jitter in its upper half, so that clients do not retry in lockstep.
+func (p *retryPolicy) delay(n int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	d := min(p.baseDelay<<(n-1), p.maxDelay)
+	if d <= 1 {
+		return d
+	}
+	return d/2 + rand.N(d/2)
+}
+
+// resumePrompt asks backends without assistant prefill to continue an
+// answer that broke off.
+const resumePrompt = "Your previous answer was cut off. Continue exactly where it stopped, without repeating anything and without any preamble."
+
+// streamWithRetry sends the request from build and streams the response
+// through parse to w. Rate limits, overloads, server and network errors
+// are retried with backoff. When a stream breaks off, the next request
+// gets the text received so far as partial, so the backend can continue
+// the answer instead of starting over.
+func streamWithRetry(ctx context.Context, client *http.Client, policy *retryPolicy, build func(partial string) (*http.Request, error), parse func(io.Reader, io.Writer) error, w io.Writer) error {
+	p := cmp.Or(policy, defaultRetry)
+	var received strings.Builder
+	out := &spaceHoldingWriter{w: io.MultiWriter(w, &received)}
+
+	for attempt := 1; ; attempt++ {
+		err := streamOnce(client, build, parse, received.String(), out)
+		if err == nil {
+			return out.flush()
+		}
+		// The held whitespace was never written; a resumed answer brings
+		// its own.
+		out.held = nil
+		// A cancelled request fails with whatever the read was doing; that
+		// is no reason to retry.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			return err
+		}
+		apiErr.Attempts = attempt
+		if !apiErr.Retryable() || attempt >= p.attempts || apiErr.RetryAfter > p.maxRetryAfter {
+			return apiErr
+		}
+
+		wait := p.delay(attempt, apiErr.RetryAfter)
+		noticeRetry(fmt.Sprintf("%s, retrying in %s (attempt %d of %d)", kindInfo[apiErr.Kind].text, wait.Round(100*time.Millisecond), attempt+1, p.attempts))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func streamOnce(client *http.Client, build func(partial string) (*http.Request, error), parse func(io.Reader, io.Writer) error, partial string, w io.Writer) error {
+	req, err := build(partial)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return networkError(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return parse(resp.Body, w)
+}
+
+// spaceHoldingWriter holds back trailing whitespace until more text
+// follows, so the text written so far never ends in whitespace. A resumed
+// answer then continues exactly what was written: Anthropic rejects
+// trailing whitespace in a prefill, and trimming it there would lose or
+// double the whitespace at the join.
+type spaceHoldingWriter struct {
+	w    io.Writer
+	held []byte
+}
+
+func (h *spaceHoldingWriter) Write(p []byte) (int, error) {
+	text := bytes.TrimRight(p, " \t\r\n")
+	if len(text) == 0 {
+		h.held = append(h.held, p...)
+		return len(p), nil
+	}
+	if _, err := h.w.Write(append(h.held, text...)); err != nil {
+		return 0, err
+	}
+	h.held = append(h.held[:0:0], p[len(text):]...)
+	return len(p), nil
+}
+
+// flush writes the held whitespace once the answer is complete.
+func (h *spaceHoldingWriter) flush() error {
+	if len(h.held) == 0 {
+		return nil
+	}
+	_, err := h.w.Write(h.held)
+	h.held = nil
+	return err
+}
+
+// noticeRetry reports a retry to the user; tests replace it.
+var noticeRetry = retryNotice
+
+// retryNotice tells the user about a retry on stderr, on its own line
+// above any spinner.
+func retryNotice(msg string) {
+	if isatty.IsTerminal(os.Stderr.Fd()) {
+		_, _ = fmt.Fprintf(os.Stderr, "\r\033[K%s\n", ui.StyleMuted.Render(msg))
+		return
+	}
+	_, _ = fmt.Fprintln(os.Stderr, msg)
+}
@@ -0,0 +1,304 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fastRetry keeps the retries of tests within milliseconds.
+var fastRetry = &retryPolicy{
+	attempts:      3,
+	baseDelay:     time.Millisecond,
+	maxDelay:      time.Millisecond,
+	maxRetryAfter: time.Second,
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		status int
+		text   string
+		want   ErrorKind
+	}{
+		{http.StatusUnauthorized, `{"error":{"message":"Incorrect API key provided"}}`, KindAuth},
+		{http.StatusForbidden, "", KindAuth},
+		{http.StatusTooManyRequests, `{"error":{"code":"insufficient_quota","message":"You exceeded your current quota"}}`, KindQuota},
+		{http.StatusBadRequest, `{"error":{"type":"invalid_request_error","message":"Your credit balance is too low"}}`, KindQuota},
+		{http.StatusPaymentRequired, "", KindQuota},
+		{http.StatusTooManyRequests, `{"error":{"message":"Rate limit reached"}}`, KindRateLimit},
+		{529, `{"error":{"type":"overloaded_error","message":"Overloaded"}}`, KindOverloaded},
+		{http.StatusServiceUnavailable, "", KindOverloaded},
+		{http.StatusBadRequest, `{"error":{"code":"context_length_exceeded","message":"This model's maximum context length is 8192 tokens"}}`, KindContextLength},
+		{http.StatusBadRequest, "prompt is too long: 210000 tokens > 200000 maximum", KindContextLength},
+		{http.StatusRequestEntityTooLarge, "", KindContextLength},
+		{http.StatusInternalServerError, "", KindServer},
+		{http.StatusNotFound, `{"error":"model not found"}`, KindRequest},
+	}
+	for _, tt := range tests {
+		if got := classify(tt.status, tt.text); got != tt.want {
+			t.Errorf("classify(%d, %q) = %s, want %s", tt.status, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := map[string]string{
+		`{"error":{"message":"Incorrect API key provided","type":"invalid_request_error"}}`:      "invalid_request_error: Incorrect API key provided",
+		`{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`: "authentication_error: invalid x-api-key",
+		`{"error":"model \"x\" not found"}`:                                                      `model "x" not found`,
+		`{"message":"Bad gateway"}`:                                                              "Bad gateway",
+		"upstream connect error\n":                                                               "upstream connect error",
+	}
+	for body, want := range tests {
+		if got := errorMessage([]byte(body)); got != want {
+			t.Errorf("errorMessage(%q) = %q, want %q", body, got, want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 4, 14, 9, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"7":                             7 * time.Second,
+		"1.5":                           1500 * time.Millisecond,
+		"Tue, 14 Apr 2026 09:00:30 GMT": 30 * time.Second,
+		"Tue, 14 Apr 2026 08:59:00 GMT": 0,
+		"soon":                          0,
+	}
+	for value, want := range tests {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := &retryPolicy{attempts: 5, baseDelay: time.Second, maxDelay: 3 * time.Second}
+	for n, limit := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 3 * time.Second, 4: 3 * time.Second} {
+		for range 20 {
+			if d := p.delay(n, 0); d < limit/2 || d >= limit {
+				t.Errorf("delay(%d) = %v, want within [%v, %v)", n, d, limit/2, limit)
+			}
+		}
+	}
+	if d := p.delay(1, 10*time.Second); d != 10*time.Second {
+		t.Errorf("expected Retry-After to be honored, got %v", d)
+	}
+}
+
+// sseDone is a complete OpenAI-compatible stream answering text.
+func sseDone(text string) string {
+	return "data: {\"choices\":[{\"delta\":{\"content\":\"" + text + "\"}}]}\n\ndata: [DONE]\n\n"
+}
+
+func TestStreamCompletion_RetriesRateLimit(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0.01")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = io.WriteString(w, `{"error":{"message":"Rate limit reached"}}`)
+			return
+		}
+		_, _ = io.WriteString(w, sseDone("recap"))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, APIKey: "x", Model: "m", retry: fastRetry}
+	var out strings.Builder
+	if err := c.StreamCompletion(t.Context(), "p", "c", &out); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 || out.String() != "recap\n" {
+		t.Errorf("expected a retry and the recap, got %d requests and %q", requests, out.String())
+	}
+}
+
+func TestStreamCompletion_NoRetryOnAuth(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = io.WriteString(w, `{"error":{"message":"Incorrect API key provided"}}`)
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, APIKey: "x", Model: "m", retry: fastRetry}
+	err := c.StreamCompletion(t.Context(), "p", "c", io.Discard)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != KindAuth || apiErr.Status != http.StatusUnauthorized {
+		t.Fatalf("expected an auth error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected no retry, got %d requests", requests)
+	}
+	if !strings.Contains(err.Error(), "Incorrect API key provided") || !strings.Contains(err.Error(), "AI_API_KEY") {
+		t.Errorf("expected the message and a hint, got %q", err)
+	}
+}
+
+func TestStreamCompletion_GivesUp(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(529)
+		_, _ = io.WriteString(w, `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`)
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, APIKey: "x", Model: "m", retry: fastRetry}
+	err := c.StreamCompletion(t.Context(), "p", "c", io.Discard)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != KindOverloaded || apiErr.Attempts != fastRetry.attempts {
+		t.Fatalf("expected an overloaded error after all attempts, got %v", err)
+	}
+	if requests != fastRetry.attempts || !strings.Contains(err.Error(), "gave up after 3 attempts") {
+		t.Errorf("expected %d requests and the attempts in the error, got %d: %q", fastRetry.attempts, requests, err)
+	}
+}
+
+func TestStreamCompletion_RetryAfterTooLong(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, APIKey: "x", Model: "m", retry: fastRetry}
+	err := c.StreamCompletion(t.Context(), "p", "c", io.Discard)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != KindRateLimit || apiErr.RetryAfter != time.Hour {
+		t.Fatalf("expected a rate limit error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected no retry for an hour-long Retry-After, got %d requests", requests)
+	}
+}
+
+func TestStreamCompletion_ResumesBrokenStream(t *testing.T) {
+	var second chatRequest
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			// The stream ends without [DONE], as when the connection drops.
+			_, _ = io.WriteString(w, "data: {\"choices\":[{\"delta\":{\"content\":\"## Done\\n\"}}]}\n\n")
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&second)
+		_, _ = io.WriteString(w, sseDone("\\n- shipped login"))
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, APIKey: "x", Model: "m", retry: fastRetry}
+	var out strings.Builder
+	if err := c.StreamCompletion(t.Context(), "p", "c", &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "## Done\n- shipped login\n" {
+		t.Errorf("expected the answer to continue, got %q", out.String())
+	}
+	// The trailing newline was held back, so the partial answer is exactly
+	// what was written.
+	msgs := second.Messages
+	if len(msgs) != 4 || msgs[2].Role != "assistant" || msgs[2].Content != "## Done" || msgs[3].Content != resumePrompt {
+		t.Errorf("expected the partial answer in the resumed request, got %+v", msgs)
+	}
+}
+
+func TestAnthropicClient_ResumesWithPrefill(t *testing.T) {
+	var second anthropicRequest
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			_, _ = io.WriteString(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"## Done\\n\"}}\n\n")
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&second)
+		_, _ = io.WriteString(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"\\n- shipped login\"}}\n\n"+
+			"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
+	}))
+	defer server.Close()
+
+	c := &AnthropicClient{BaseURL: server.URL, APIKey: "sk-ant-test", Model: "m", retry: fastRetry}
+	var out strings.Builder
+	if err := c.StreamCompletion(t.Context(), "p", "c", &out); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := second.Messages
+	if len(msgs) != 2 || msgs[1].Role != "assistant" || msgs[1].Content != "## Done" {
+		t.Errorf("expected the written text as prefill, got %+v", msgs)
+	}
+	if out.String() != "## Done\n- shipped login\n" {
+		t.Errorf("expected a clean join, got %q", out.String())
+	}
+}
+
+func TestStreamCompletion_CancelledMidStream(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = io.WriteString(w, "data: {\"choices\":[{\"delta\":{\"content\":\"## Done\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	var notices []string
+	noticeRetry = func(msg string) { notices = append(notices, msg) }
+	defer func() { noticeRetry = retryNotice }()
+
+	// Cancel as soon as the first text arrives, like Ctrl-C mid-answer.
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	w := writerFunc(func(p []byte) (int, error) {
+		cancel()
+		return len(p), nil
+	})
+
+	c := &Client{BaseURL: server.URL, APIKey: "x", Model: "m", retry: &retryPolicy{attempts: 3, baseDelay: time.Minute, maxDelay: time.Minute}}
+	err := c.StreamCompletion(ctx, "p", "c", w)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) || requests != 1 || len(notices) != 0 {
+		t.Errorf("expected no retry, got %d requests, notices %q, error %v", requests, notices, err)
+	}
+}
+
+// writerFunc adapts a function to io.Writer.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+func TestSpaceHoldingWriter(t *testing.T) {
+	var out strings.Builder
+	h := &spaceHoldingWriter{w: &out}
+	for _, s := range []string{"## Done\n", "\n", "- a ", "b\n"} {
+		_, _ = io.WriteString(h, s)
+	}
+	if out.String() != "## Done\n\n- a b" {
+		t.Errorf("before flush: %q", out.String())
+	}
+	if err := h.flush(); err != nil || out.String() != "## Done\n\n- a b\n" {
+		t.Errorf("after flush: %q, %v", out.String(), err)
+	}
+}
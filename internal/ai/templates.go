@@ -9,17 +9,19 @@ import (
 type Style string
 
 const (
-	StyleBrief  Style = "brief"  // Done/Next/Blocker, max 12 lines
-	StyleDigest Style = "digest" // thematic overview, all sources, default
-	StyleReport Style = "report" // polished formal report, deliveries
-	StyleRetro  Style = "retro"  // retrospective: good/bad/learnings
-	StyleStats  Style = "stats"  // work statistics with category breakdown and ASCII bar charts
-	StyleStatus Style = "status" // progress/blocker/next, progress-focused
+	StyleBrief   Style = "brief"   // Done/Next/Blocker, max 12 lines
+	StyleCompare Style = "compare" // what changed versus a previous period, needs --compare
+	StyleDigest  Style = "digest"  // thematic overview, all sources, default
+	StyleReport  Style = "report"  // polished formal report, deliveries
+	StyleRetro   Style = "retro"   // retrospective: good/bad/learnings
+	StyleStats   Style = "stats"   // work statistics with category breakdown and ASCII bar charts
+	StyleStatus  Style = "status"  // progress/blocker/next, progress-focused
 )
 
 // validStyles is the exhaustive list of built-in style identifiers.
 var validStyles = []Style{
 	StyleBrief,
+	StyleCompare,
 	StyleDigest,
 	StyleReport,
 	StyleRetro,
@@ -41,6 +43,7 @@ var styleInfos = []StyleInfo{
 	{StyleReport, "Polished formal report, deliveries only (8-12 lines)"},
 	{StyleRetro, "Retrospective: good/bad/time/learnings (15-25 lines)"},
 	{StyleStats, "Work statistics with category breakdown and ASCII bar charts"},
+	{StyleCompare, "What changed versus a previous period, with --compare (10-20 lines)"},
 }
 
 // StyleInfoList returns all built-in styles with their descriptions.
@@ -72,11 +75,25 @@ func AllowedSources(style Style) []string {
 	return nil
 }
 
+// DefaultFilter returns the entry filter a style applies unless the user
+// passes --where (see package filter). An empty string keeps all entries.
+func DefaultFilter(style Style) string {
+	allowed := AllowedSources(style)
+	if len(allowed) == 0 {
+		return ""
+	}
+	return "source in (" + strings.Join(allowed, ", ") + ")"
+}
+
 // DefaultTimespec returns the default time specification for a style.
-// Most styles default to "today"; brief defaults to "yesterday".
+// Most styles default to "today"; brief defaults to "yesterday" and compare
+// to "thisweek".
 func DefaultTimespec(style Style) string {
-	if style == StyleBrief {
+	switch style {
+	case StyleBrief:
 		return "yesterday"
+	case StyleCompare:
+		return "thisweek"
 	}
 	return "today"
 }
@@ -94,6 +111,8 @@ func Prompt(style Style) string {
 	switch style {
 	case StyleBrief:
 		return promptBrief
+	case StyleCompare:
+		return promptCompare
 	case StyleReport:
 		return promptReport
 	case StyleRetro:
@@ -112,6 +131,7 @@ const promptBrief = `Summarize the developer's activity log for a daily standup.
 ## How to read the input
 
 Each line: DATE SOURCE: CONTENT
+Entries are grouped under "Work Item" headings. A work item links commits, AI sessions, notes, CI runs and tracked time about the same project, branch or issue -- report it as one piece of work, not once per source.
 
 claude -- AI session: [project] snippet -- N turns, M min. Skip if < 3 turns or < 5 min.
 git -- commit message. Always include.
@@ -144,17 +164,29 @@ const promptDigest = `Summarize the developer's activity log as a thematic techn
 ## How to read the input
 
 Each line: DATE SOURCE: CONTENT
+Entries are grouped under "Work Item" headings. A work item links commits, AI sessions, notes, CI runs and tracked time about the same project, branch or issue -- report it as one piece of work, not once per source.
 
-obsidian -- a file was modified; no content available. Read the path:
+obsidian -- a note was created, modified, renamed or deleted, often with the sections added or removed. Read the path:
   - 1 Projects/<name>/ = active project work
   - 2 Areas/<topic>/ = ongoing responsibility
   - 3 Resources/<topic>/ = research (e.g. K8s/, Go/, Nix/)
-  - Journal/ = daily journal -- skip, no signal
+  - Journal/ = daily journal -- skip bare "(modified)" lines, no signal
+  - Lines without a "(created/modified/...)" suffix are bullets and tasks from the day's daily note -- high-signal, treat like a work log
 
 claude -- an AI session. Format: [project] snippet -- N turns, M min
   - Under 3 turns or under 5 min = likely aborted, skip
   - Duration in minutes = effort proxy
 
+org, logseq -- outline items: closed/DONE tasks, journal lines and clocked time ("clocked 1h30m"). Clocked time = effort proxy.
+todotxt -- "done: <task>" completed in the period, "open: <task> (due DATE)" due in it.
+taskwarrior -- "[project] completed|started|annotated: <task>". Summarize completions per project.
+git-bug -- "[bug-id] opened|commented on|closed: <title>". Issues tracked in the repo.
+editor -- "[project] edited <file> (N saves, HH:MM-HH:MM)": files touched in an editor. Evidence of work not yet committed; group by project, skip if git already covers it.
+ci -- "[repo] <workflow> #N on <branch>: passed|failed|cancelled after <duration> -- failing: <jobs>". A run of failures on one branch followed by a pass is a story ("fixed the flaky integration pipeline after 9 red runs"); count the red runs instead of listing them.
+containers -- kubectl changes ("applied deployment/api in namespace prod"), Helm releases ("deployed chart X to namespace Y") and Docker/Podman events. Deployments and rollbacks are high-signal; group container starts and pulls into one line.
+jsonl, csv -- records from exported logs (deploys, on-call pages, CI runs); the content is what the user mapped. Use the location and metadata for context.
+timewarrior, watson, activitywatch, toggl, clockify -- tracked time: [project] description -- duration. Hard numbers for where the time went; activitywatch reads "app: window title -- duration" instead.
+
 git -- a commit message. High-signal, always include.
 
 ## Output format
@@ -181,8 +213,9 @@ const promptReport = `Write a polished formal report from the developer's activi
 ## How to read the input
 
 Each line: DATE SOURCE: CONTENT
+Entries are grouped under "Work Item" headings. A work item links commits, AI sessions, notes, CI runs and tracked time about the same project, branch or issue -- report it as one piece of work, not once per source.
 
-obsidian -- file modified. Decode path for context.
+obsidian -- note created/modified/renamed/deleted, sometimes with changed sections. Decode path for context.
 claude -- AI session: [project] snippet -- N turns, M min. Low weight if < 3 turns or < 5 min.
 git -- commit message. Translate to outcome language. Merged/shipped work only.
 
@@ -213,10 +246,20 @@ const promptStatus = `Write a progress-focused status update from the developer'
 ## How to read the input
 
 Each line: DATE SOURCE: CONTENT
+Entries are grouped under "Work Item" headings. A work item links commits, AI sessions, notes, CI runs and tracked time about the same project, branch or issue -- report it as one piece of work, not once per source.
 
-obsidian -- file modified. Use the path to infer topic. No content available.
+obsidian -- note created/modified/renamed/deleted. Use the path and any changed sections to infer topic.
 claude -- AI session: [project] snippet -- N turns, M min. Skip short sessions (< 3 turns or < 5 min).
 git -- commit message. Always relevant. Group by repo.
+markdown, obsidian (task mode) -- "done: <task>" is a checkbox task completed in the period; "open: <task> (due DATE)" is an open task due in it. Obsidian prefixes the note path.
+  Done tasks are real progress. Open tasks are the best source for Next Steps.
+org, logseq, todotxt -- DONE/closed items are progress; TODO items and due tasks feed Next Steps.
+taskwarrior -- "[project] completed|started|annotated: <task>". Count completions per project ("completed 7 tasks in infra") instead of listing each.
+git-bug -- "[bug-id] opened|commented on|closed|...: <title>". Closed bugs are progress, opened ones feed Blockers or Next Steps.
+editor -- "[project] edited <file>": files edited without a commit yet are in-progress work.
+ci -- "[repo] <workflow> #N on <branch>: passed|failed ...". Red runs that turned green are progress worth naming with the count; a branch still failing is a Blocker.
+containers -- Helm deploys and kubectl changes are progress; failed releases, rollbacks and crashing containers feed Blockers.
+jsonl, csv -- records from exported logs (deploys, pages, CI runs). Deploys are progress, pages and failures feed Blockers.
 
 ## Output format
 
@@ -246,10 +289,12 @@ const promptRetro = `Write a sprint retrospective from the developer's activity
 ## How to read the input
 
 Each line: DATE SOURCE: CONTENT
+Entries are grouped under "Work Item" headings. A work item links commits, AI sessions, notes, CI runs and tracked time about the same project, branch or issue -- report it as one piece of work, not once per source.
 
-obsidian -- file modified. Use the path to infer topic. No content available.
+obsidian -- note created/modified/renamed/deleted. Use the path and any changed sections to infer topic.
 claude -- AI session: [project] snippet -- N turns, M min. Skip short sessions (< 3 turns or < 5 min).
 git -- commit message. Always relevant. Group by repo.
+timewarrior, watson, activitywatch, toggl, clockify -- tracked time: [project] description -- duration (activitywatch: app: window title -- duration). Prefer these over proxies for time distribution.
 
 ## Output format
 
@@ -280,9 +325,11 @@ Rules:
 const promptStats = `Analyze the activity log and produce a work statistics report.
 Write EVERYTHING in {language} -- all headings, all bullets, all text. No exceptions.
 
+The input ends with an "Estimated Working Time" section: hours per project and day, estimated from activity timestamps. Base all percentages on these hours, not on entry or commit counts. A project with few commits but long AI or editor sessions took more time than its commit count suggests.
+
 Use this exact format (no tables, no pipe characters):
 
-# Period -- N activities
+# Period -- N activities, ~Xh
 
 ## Categories
 
@@ -292,6 +339,10 @@ Category Name        ████████████░░░░░░░
 Another Category     ██████░░░░░░░░░░░░░░  28%  (N)
   Short description
 
+## Time by Project
+
+project-name         ████████████░░░░░░░░  12h40m
+
 ## Work Types
 
 Thinking             ████████████░░░░░░░░  55%
@@ -308,7 +359,43 @@ Rules:
 - Each line must fit in 72 chars -- no wrapping, no tables
 - Category name left, then bar, then percentage
 - Description indented 2 spaces on next line, max 50 chars
-- Percentages add up to 100%
+- Percentages add up to 100% and are shares of the estimated hours
+- Time by Project lists the estimated hours per project, longest first, bars scaled to the longest
 - Use concrete project names
 - No preamble, no markdown tables, no pipe chars, no emojis
 - Keep total output under 30 lines`
+
+const promptCompare = `Compare two periods of the developer's activity log: what changed versus the previous period.
+
+## How to read the input
+
+The input starts with a "Period Comparison" section: totals, new and dropped projects, and tables of estimated hours per project and activity counts per source and category for both periods. Categories are conventional commit types (feat, fix, docs, ...) for commits and kinds of activity (ai, notes, tasks, tracked, ...) for everything else.
+After it come the full activity logs of the current and the previous period. Each line: DATE SOURCE: CONTENT. Entries are grouped under "Work Item" headings.
+
+Use the tables for numbers and the activity logs for what the work was. Hours are estimates from activity timestamps -- base statements about time on hours, not counts.
+
+## Output format
+
+Write EVERYTHING in {language} -- all headings, all bullets, all text. No exceptions.
+
+### Overview
+1-2 sentences: how the current period differs from the previous one overall (more or less time, shifted focus).
+
+### Shifts
+2-5 bullets. Projects or kinds of work that grew or shrank noticeably, with the hours or counts.
+Format: "[project] <what changed> (<previous> -> <current>)"
+
+### New and dropped
+0-4 bullets. Projects that started or stopped, and what was done in them.
+If nothing started or stopped, omit this section.
+
+### Carried over
+0-3 bullets. Work visible in both periods that is still ongoing.
+If none, omit this section.
+
+Rules:
+- Only name changes the data shows -- no guesses about reasons
+- Ignore small changes (a few entries, under an hour) unless nothing else changed
+- No commit hashes or file paths
+- ALWAYS prefix each bullet with the repo/project name in brackets, e.g. "[my-app] fixed auth bug" or "[infra] updated config". Extract project names from the git repo paths and claude session project names in the input data. The reader works on multiple projects and must see at a glance which repo each item belongs to. Never write a bullet without a project prefix.
+- Language: {language}`
@@ -17,6 +17,7 @@ func TestIsValidStyle(t *testing.T) {
 		{"retro", true},
 		{"stats", true},
 		{"status", true},
+		{"compare", true},
 		{"unknown", false},
 		{"", false},
 		{"BRIEF", false}, // case-sensitive
@@ -30,10 +31,10 @@ func TestIsValidStyle(t *testing.T) {
 
 func TestValidStyleNames(t *testing.T) {
 	names := ValidStyleNames()
-	if len(names) != 6 {
-		t.Fatalf("expected 6 style names, got %d", len(names))
+	if len(names) != 7 {
+		t.Fatalf("expected 7 style names, got %d", len(names))
 	}
-	want := map[string]bool{"brief": true, "digest": true, "report": true, "retro": true, "stats": true, "status": true}
+	want := map[string]bool{"brief": true, "compare": true, "digest": true, "report": true, "retro": true, "stats": true, "status": true}
 	for _, name := range names {
 		if !want[name] {
 			t.Errorf("unexpected style name %q", name)
@@ -51,6 +52,7 @@ func TestDefaultTimespec(t *testing.T) {
 		{StyleReport, "today"},
 		{StyleRetro, "today"},
 		{StyleStatus, "today"},
+		{StyleCompare, "thisweek"},
 	}
 	for _, tt := range tests {
 		if got := DefaultTimespec(tt.style); got != tt.want {
@@ -135,6 +137,15 @@ func TestAllowedSources(t *testing.T) {
 	}
 }
 
+func TestDefaultFilter(t *testing.T) {
+	if got, want := DefaultFilter(StyleBrief), "source in (git, claude)"; got != want {
+		t.Errorf("DefaultFilter(brief) = %q, want %q", got, want)
+	}
+	if got := DefaultFilter(StyleDigest); got != "" {
+		t.Errorf("DefaultFilter(digest) = %q, want empty", got)
+	}
+}
+
 func TestPromptNotEmpty(t *testing.T) {
 	for _, style := range validStyles {
 		p := Prompt(style)
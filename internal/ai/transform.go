@@ -46,6 +46,100 @@ type TransformConfig struct {
 	EntryCount    int    // shown in the footer line
 	Style         string // shown in the status line
 	Language      string // shown in the status line
+
+	// TokenBudget is the estimated number of input tokens per request; 0
+	// sends any input at once. Larger input is split with Split and
+	// summarized in parts first.
+	TokenBudget int
+	// Split returns the activity in parts that can be summarized on their
+	// own, such as days or projects. Nil sends large input at once.
+	Split func() ([]Chunk, error)
+	// Appendix is added to the input after any splitting, e.g. the
+	// estimated hours.
+	Appendix string
+	// Cache holds the notes of single days. When set and Split returns
+	// more than one day, the days are summarized one by one, reusing
+	// cached notes, and the notes are summarized with the prompt.
+	Cache *SummaryCache
+	// OnResult is called with the generated summary before it is printed,
+	// e.g. to archive it.
+	OnResult func(Result)
+}
+
+// Result is a generated summary with what produced it.
+type Result struct {
+	Text   string
+	Prompt string // resolved prompt, without the period
+	Model  string // model name, or the command of the CLI backend
+}
+
+// completeFunc sends a prompt and content to the AI backend and writes the
+// response to w.
+type completeFunc func(ctx context.Context, prompt, content string, w io.Writer) error
+
+// Backends selectable with ai_backend.
+const (
+	BackendCLI       = "cli"       // pipes the input into a CLI tool
+	BackendAPI       = "api"       // OpenAI-compatible /chat/completions
+	BackendAnthropic = "anthropic" // native Anthropic Messages API
+	BackendOllama    = "ollama"    // native Ollama /api/chat
+)
+
+// Backends lists the valid values of ai_backend.
+var Backends = []string{BackendCLI, BackendAPI, BackendAnthropic, BackendOllama}
+
+// newCompleter returns the completeFunc of the configured backend. The API
+// key resolves as apiKeyOverride > AI_API_KEY env > config.
+func newCompleter(cfg TransformConfig, apiKeyOverride string) completeFunc {
+	if cfg.AIBackend == BackendCLI {
+		return func(ctx context.Context, prompt, content string, w io.Writer) error {
+			return RunCLI(ctx, cfg.AICLICommand, prompt, content, w)
+		}
+	}
+
+	apiKey := cfg.AIAPIKey
+	if envKey := os.Getenv("AI_API_KEY"); envKey != "" {
+		apiKey = envKey
+	}
+	if apiKeyOverride != "" {
+		apiKey = apiKeyOverride
+	}
+	hc := newHTTPClientWithTimeout(cfg.AIHTTPTimeout)
+
+	switch cfg.AIBackend {
+	case BackendAnthropic:
+		client := &AnthropicClient{BaseURL: cfg.AIBaseURL, APIKey: apiKey, Model: cfg.AIModel, httpClient: hc}
+		return client.StreamCompletion
+	case BackendOllama:
+		client := &OllamaClient{BaseURL: cfg.AIBaseURL, Model: cfg.AIModel, httpClient: hc}
+		return client.StreamCompletion
+	}
+	client := &Client{
+		BaseURL:    cfg.AIBaseURL,
+		APIKey:     apiKey,
+		Model:      cfg.AIModel,
+		httpClient: hc,
+	}
+	return client.StreamCompletion
+}
+
+// modelName identifies the model for the summary cache: the model of the
+// API backend or the command of the CLI backend.
+func modelName(cfg TransformConfig) string {
+	if cfg.AIBackend == BackendCLI {
+		return cfg.AICLICommand
+	}
+	return cfg.AIModel
+}
+
+// allDays reports whether every chunk is a day that can be cached.
+func allDays(chunks []Chunk) bool {
+	for _, c := range chunks {
+		if c.Key == "" {
+			return false
+		}
+	}
+	return true
 }
 
 // Transform sends rendered recap text through an AI backend for summarization.
@@ -53,6 +147,11 @@ type TransformConfig struct {
 // (apiKeyOverride > AI_API_KEY env > config), then dispatches to CLI or API.
 // The AI response is buffered and glamour-rendered when stdout is a terminal.
 // Status messages are written to stderr so they disappear when stdout is piped.
+//
+// Input over cfg.TokenBudget is summarized map-reduce style: the parts
+// from cfg.Split are condensed into notes, and the notes are summarized
+// with the prompt. With cfg.Cache, recaps of several days are always
+// composed from the notes of single days, see SummaryCache.
 func Transform(ctx context.Context, w io.Writer, renderedText string, period string, cfg TransformConfig, promptOverride, apiKeyOverride string) error {
 	// Resolve prompt: override > config > default
 	prompt := defaultPrompt
@@ -63,9 +162,63 @@ func Transform(ctx context.Context, w io.Writer, renderedText string, period str
 		prompt = promptOverride
 	}
 
+	instructions := prompt
+
 	// Inject time range context
 	prompt = fmt.Sprintf("Period: %s\n\n%s", period, prompt)
 
+	complete := newCompleter(cfg, apiKeyOverride)
+
+	input := renderedText
+	tokens := EstimateTokens(renderedText)
+	overBudget := cfg.TokenBudget > 0 && tokens > cfg.TokenBudget
+	if overBudget && cfg.Split == nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: the input is about %d tokens, over the token budget of %d; sending it at once\n", tokens, cfg.TokenBudget)
+	}
+
+	var chunks []Chunk
+	if cfg.Split != nil && (overBudget || cfg.Cache != nil) {
+		var err error
+		if chunks, err = cfg.Split(); err != nil {
+			return fmt.Errorf("failed to split recap: %w", err)
+		}
+	}
+
+	stop := func() {}
+	status := func(msg string) {
+		stop()
+		stop = ui.StartSpinner(msg)
+	}
+	switch {
+	case cfg.Cache != nil && len(chunks) > 1 && allDays(chunks):
+		base := CacheKey{Style: cfg.Style, Language: cfg.Language, Model: modelName(cfg), Prompt: instructions}
+		notes, cached, err := summarizeDays(ctx, complete, cfg.Cache, chunks, base, cfg.TokenBudget, status)
+		stop()
+		if err != nil {
+			return err
+		}
+		if cached > 0 {
+			_, _ = fmt.Fprintln(os.Stderr, ui.StyleMuted.Render(fmt.Sprintf("Reused %d of %d day summaries from the cache", cached, len(chunks))))
+		}
+		input = renderNotes(notes, period, notesPerDay)
+		if cfg.TokenBudget > 0 && EstimateTokens(input) > cfg.TokenBudget {
+			input, err = summarizeInChunks(ctx, complete, notes, cfg.TokenBudget, period, cfg.Language, status)
+			stop()
+			if err != nil {
+				return err
+			}
+		}
+	case overBudget && chunks != nil:
+		budgetInfo := ui.StyleMuted.Render(fmt.Sprintf("(~%d tokens, budget %d)", tokens, cfg.TokenBudget))
+		notes, err := summarizeInChunks(ctx, complete, chunks, cfg.TokenBudget, period, cfg.Language, func(msg string) { status(msg + " " + budgetInfo) })
+		stop()
+		if err != nil {
+			return err
+		}
+		input = notes
+	}
+	input += cfg.Appendix
+
 	// Spinner on stderr -- invisible when piped, clears when done
 	spinnerMsg := "Generating summary..."
 	if cfg.Style != "" || cfg.Language != "" {
@@ -77,33 +230,16 @@ func Transform(ctx context.Context, w io.Writer, renderedText string, period str
 	// Buffer AI output so we can glamour-render it
 	var aiOut bytes.Buffer
 
-	var err error
-	if cfg.AIBackend == "cli" {
-		err = RunCLI(cfg.AICLICommand, prompt, renderedText, &aiOut)
-	} else {
-		// Resolve API key: override > env > config
-		apiKey := cfg.AIAPIKey
-		if envKey := os.Getenv("AI_API_KEY"); envKey != "" {
-			apiKey = envKey
-		}
-		if apiKeyOverride != "" {
-			apiKey = apiKeyOverride
-		}
-
-		client := &Client{
-			BaseURL:    cfg.AIBaseURL,
-			APIKey:     apiKey,
-			Model:      cfg.AIModel,
-			httpClient: newHTTPClientWithTimeout(cfg.AIHTTPTimeout),
-		}
-		err = client.StreamCompletion(ctx, prompt, renderedText, &aiOut)
-	}
+	err := complete(ctx, prompt, input, &aiOut)
 
 	stopSpinner()
 
 	if err != nil {
 		return err
 	}
+	if cfg.OnResult != nil {
+		cfg.OnResult(Result{Text: aiOut.String(), Prompt: instructions, Model: modelName(cfg)})
+	}
 
 	sep := strings.Repeat("─", 65)
 	_, _ = fmt.Fprintln(w)
@@ -1,15 +1,21 @@
 package config
 
 import (
+	"cmp"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/charemma/ikno/internal/ai"
 	"github.com/charemma/ikno/internal/git"
 	"github.com/charemma/ikno/internal/paths"
+	"github.com/charemma/ikno/internal/projects"
+	"github.com/charemma/ikno/internal/recap"
 	"github.com/charemma/ikno/internal/timerange"
+	"github.com/charemma/ikno/internal/timesheet"
 	"gopkg.in/yaml.v3"
 )
 
@@ -33,6 +39,11 @@ func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// MarshalYAML implements yaml.Marshaler for Duration.
+func (d Duration) MarshalYAML() (any, error) {
+	return time.Duration(d).String(), nil
+}
+
 // ToDuration converts Duration to time.Duration.
 func (d Duration) ToDuration() time.Duration {
 	return time.Duration(d)
@@ -48,27 +59,62 @@ type Config struct {
 	AIModel        string   `yaml:"ai_model"`                 // model name for AI summaries
 	AIAPIKey       string   `yaml:"ai_api_key"`               // API key (prefer env var AI_API_KEY)
 	AIPrompt       string   `yaml:"ai_prompt"`                // custom prompt for AI summaries
-	AIBackend      string   `yaml:"ai_backend"`               // "api" or "cli"
+	AIBackend      string   `yaml:"ai_backend"`               // "cli", "api", "anthropic" or "ollama"
 	AICLICommand   string   `yaml:"ai_cli_command"`           // CLI tool for ai_backend: cli
-	AIDefaultStyle string   `yaml:"ai_default_style"`         // default output style: brief, digest, status, report, retro
+	AIDefaultStyle string   `yaml:"ai_default_style"`         // default output style: brief, digest, status, report, retro, stats
 	AILanguage     string   `yaml:"ai_language"`              // output language passed to AI (e.g. "deutsch", "english")
 	AIHTTPTimeout  Duration `yaml:"ai_http_timeout"`          // HTTP timeout for API calls (default: 60s)
+	AITokenBudget  int      `yaml:"ai_token_budget"`          // estimated input tokens per AI request, larger recaps are summarized in parts (default: 100000, 0 disables)
+	AIChunkBy      string   `yaml:"ai_chunk_by"`              // how large recaps are split: "day" (default) or "project"
+	AISummaryCache bool     `yaml:"ai_summary_cache"`         // compose recaps of several days from cached day summaries (default: true)
+
+	SourceTimeout  Duration            `yaml:"source_timeout"`            // time limit per source when collecting (default: 60s, 0s disables)
+	SourceTimeouts map[string]Duration `yaml:"source_timeouts,omitempty"` // per source type, overrides source_timeout
+
+	Projects  []projects.Project `yaml:"projects,omitempty"`  // canonical projects and how entries map to them
+	Hours     HoursConfig        `yaml:"hours,omitempty"`     // working time estimation (ikno hours)
+	Timesheet TimesheetConfig    `yaml:"timesheet,omitempty"` // ikno export timesheet
+}
+
+// TimesheetConfig holds rounding rules and import fields for timesheet
+// exports. Increment and minimum are pointers so "0s" can turn them off.
+type TimesheetConfig struct {
+	Increment *Duration `yaml:"increment,omitempty"` // round to multiples of this (default: 15m)
+	Minimum   *Duration `yaml:"minimum,omitempty"`   // shortest row (default: 15m)
+	Rounding  string    `yaml:"rounding,omitempty"`  // up (default), nearest or down
+	Task      string    `yaml:"task,omitempty"`      // Harvest task name (default: Development)
 }
 
+// HoursConfig tunes how working time is estimated from activity.
+type HoursConfig struct {
+	IdleGap Duration            `yaml:"idle_gap,omitempty"` // longest pause within one session (default: 30m)
+	LeadIn  map[string]Duration `yaml:"lead_in,omitempty"`  // time before a session's first entry, per source type or "default"
+}
+
+// Defaults of the API backends, which point at Anthropic.
+const (
+	defaultAIBaseURL = "https://api.anthropic.com/v1/"
+	defaultAIModel   = "claude-sonnet-4-20250514"
+)
+
 // DefaultConfig returns the default configuration.
 // Attempts to read author email from git config and auto-detects timezone.
 func DefaultConfig() *Config {
 	return &Config{
-		WeekStart:     "monday",
-		AuthorEmail:   "",         // will be set from git config if available
-		AuthorAliases: []string{}, // empty by default
-		Timezone:      detectTimezone(),
-		AIBaseURL:     "https://api.anthropic.com/v1/",
-		AIModel:       "claude-sonnet-4-20250514",
-		AIBackend:     "cli",
-		AICLICommand:  "claude -p",
-		AILanguage:    "english",                  // default output language for AI summaries
-		AIHTTPTimeout: Duration(60 * time.Second), // default: 60s
+		WeekStart:      "monday",
+		AuthorEmail:    "",         // will be set from git config if available
+		AuthorAliases:  []string{}, // empty by default
+		Timezone:       detectTimezone(),
+		AIBaseURL:      defaultAIBaseURL,
+		AIModel:        defaultAIModel,
+		AIBackend:      "cli",
+		AICLICommand:   "claude -p",
+		AILanguage:     "english",                  // default output language for AI summaries
+		AIHTTPTimeout:  Duration(60 * time.Second), // default: 60s
+		AITokenBudget:  100_000,
+		AIChunkBy:      "day",
+		AISummaryCache: true,
+		SourceTimeout:  Duration(60 * time.Second), // default: 60s
 	}
 }
 
@@ -122,11 +168,47 @@ func Load() (*Config, error) {
 	// Validate ai_backend
 	if cfg.AIBackend != "" {
 		ab := strings.ToLower(cfg.AIBackend)
-		if ab != "api" && ab != "cli" {
-			return nil, fmt.Errorf("invalid ai_backend: %s (must be 'api' or 'cli')", cfg.AIBackend)
+		if !slices.Contains(ai.Backends, ab) {
+			return nil, fmt.Errorf("invalid ai_backend: %s (must be one of %s)", cfg.AIBackend, strings.Join(ai.Backends, ", "))
 		}
 		cfg.AIBackend = ab
 	}
+	// Ollama runs locally and has no default model, so the Anthropic
+	// defaults do not apply to it.
+	if cfg.AIBackend == "ollama" {
+		if cfg.AIBaseURL == defaultAIBaseURL {
+			cfg.AIBaseURL = ai.DefaultOllamaURL
+		}
+		if cfg.AIModel == defaultAIModel {
+			cfg.AIModel = ""
+		}
+	}
+
+	if cfg.AITokenBudget < 0 {
+		return nil, fmt.Errorf("invalid ai_token_budget: %d (must not be negative)", cfg.AITokenBudget)
+	}
+	cb := strings.ToLower(cmp.Or(cfg.AIChunkBy, "day"))
+	if cb != "day" && cb != "project" {
+		return nil, fmt.Errorf("invalid ai_chunk_by: %s (must be 'day' or 'project')", cfg.AIChunkBy)
+	}
+	cfg.AIChunkBy = cb
+
+	if err := projects.Validate(cfg.Projects); err != nil {
+		return nil, fmt.Errorf("invalid projects: %w", err)
+	}
+
+	if cfg.SourceTimeout < 0 {
+		return nil, fmt.Errorf("invalid source_timeout: %s (must not be negative)", cfg.SourceTimeout.ToDuration())
+	}
+	for sourceType, d := range cfg.SourceTimeouts {
+		if d < 0 {
+			return nil, fmt.Errorf("invalid source_timeouts.%s: %s (must not be negative)", sourceType, d.ToDuration())
+		}
+	}
+
+	if err := cfg.GetTimesheetRounding().Validate(); err != nil {
+		return nil, fmt.Errorf("invalid timesheet settings: %w", err)
+	}
 
 	// If author_email is not set, try to get from git config
 	if cfg.AuthorEmail == "" {
@@ -173,7 +255,7 @@ week_start: monday
 # timezone: Europe/Athens
 
 # AI summary settings (ikno recap uses AI by default)
-# Supports any OpenAI-compatible API endpoint.
+# ai_backend: api supports any OpenAI-compatible API endpoint.
 #
 # Providers:
 #   Anthropic:  https://api.anthropic.com/v1/
@@ -187,7 +269,11 @@ week_start: monday
 # ai_prompt: "Summarize my workday."  # override default summary prompt
 #
 # AI backend: "cli" (default) pipes recap data into a CLI tool,
-# "api" calls an OpenAI-compatible API directly.
+# "api" calls an OpenAI-compatible API directly,
+# "anthropic" calls the native Anthropic Messages API (ai_base_url
+# https://api.anthropic.com/v1/, needs an API key), and
+# "ollama" calls a local Ollama server (ai_base_url defaults to
+# http://localhost:11434, ai_model must name a pulled model).
 # ai_backend: cli
 # ai_cli_command: claude -p            # CLI tool for ai_backend: cli
 #
@@ -196,10 +282,59 @@ week_start: monday
 # Format: 30s, 1m, 5m, etc.
 # ai_http_timeout: 60s
 #
+# Estimated input tokens per request (default: 100000, 0 sends everything
+# at once). Larger recaps, such as a quarter with diffs, are split by day
+# or project, each part is summarized, and the style summarizes the parts.
+# Lower it for models with a small context window.
+# ai_token_budget: 100000
+# ai_chunk_by: day                     # day or project
+#
+# Recaps of several days are composed from summaries of single days, which
+# are cached under cache/summaries in this directory. Only days whose
+# activity changed are summarized again. Needs ai_chunk_by: day.
+# ai_summary_cache: true
+#
 # Output language for AI summaries (default: english)
 # Use full language names: deutsch, english, greek, etc.
 # Overridable with --lang flag on each recap command.
 # ai_language: english
+
+# Time limit for reading one source (default: 60s, 0s waits forever).
+# A source that takes longer, such as a git repo on a slow network mount,
+# is skipped with a warning and the recap continues without it.
+# source_timeout: 60s
+# source_timeouts:
+#   obsidian: 5m                         # per source type
+
+# Projects map what each source calls a project to one canonical name.
+# An entry belongs to the first project with a matching rule. Used for
+# "ikno recap --project <name>" and to group work across sources.
+# projects:
+#   - name: acme-shop
+#     client: ACME Corp
+#     billable: true
+#     paths: [~/code/acme-*]             # repos, Claude CWDs, files below (globs ok)
+#     remotes: [github.com/acme/*]       # git remotes as host/path
+#     folders: ["1 Projects/ACME"]       # note folders
+#     tags: [acme]                       # note and task tags
+#     names: [ACME, shop]                # tracker project names (Toggl, Timewarrior, ...)
+
+# Working time estimation (ikno hours, stats style). Activity less than
+# idle_gap apart counts as one session; lead_in is the time assumed before
+# a session's first entry, per source type.
+# hours:
+#   idle_gap: 30m
+#   lead_in:
+#     git: 20m                           # default: 20m
+#     default: 10m                       # sources without their own duration
+
+# Timesheet export (ikno export timesheet). Estimated hours per day and
+# project are rounded to the increment, never below the minimum.
+# timesheet:
+#   increment: 15m                       # 0s keeps exact minutes
+#   minimum: 15m
+#   rounding: up                         # up, nearest or down
+#   task: Development                    # Harvest task name
 `
 
 // Save writes the configuration to $IKNO_HOME/config.yaml or ~/.config/ikno/config.yaml.
@@ -262,3 +397,52 @@ func (c *Config) GetTimerangeConfig() *timerange.Config {
 		WeekStart: weekStart,
 	}
 }
+
+// GetTimesheetRounding returns the timesheet rounding rules, starting from
+// the built-in defaults.
+func (c *Config) GetTimesheetRounding() timesheet.Rounding {
+	r := timesheet.DefaultRounding()
+	if c.Timesheet.Increment != nil {
+		r.Increment = c.Timesheet.Increment.ToDuration()
+	}
+	if c.Timesheet.Minimum != nil {
+		r.Minimum = c.Timesheet.Minimum.ToDuration()
+	}
+	if c.Timesheet.Rounding != "" {
+		r.Mode = strings.ToLower(c.Timesheet.Rounding)
+	}
+	return r
+}
+
+// GetBuildOptions returns the recap collection settings: project mapping
+// and per-source time limits.
+func (c *Config) GetBuildOptions() recap.BuildOptions {
+	opts := recap.BuildOptions{
+		Projects: c.Projects,
+		Timeout:  c.SourceTimeout.ToDuration(),
+	}
+	if len(c.SourceTimeouts) > 0 {
+		opts.Timeouts = make(map[string]time.Duration, len(c.SourceTimeouts))
+		for sourceType, d := range c.SourceTimeouts {
+			opts.Timeouts[sourceType] = d.ToDuration()
+		}
+	}
+	return opts
+}
+
+// GetHoursOptions converts the hours settings to recap.HoursOptions,
+// starting from the built-in defaults.
+func (c *Config) GetHoursOptions() recap.HoursOptions {
+	opts := recap.DefaultHoursOptions()
+	if c.Hours.IdleGap > 0 {
+		opts.IdleGap = c.Hours.IdleGap.ToDuration()
+	}
+	for source, d := range c.Hours.LeadIn {
+		if source == "default" {
+			opts.DefaultLeadIn = d.ToDuration()
+		} else {
+			opts.LeadIn[source] = d.ToDuration()
+		}
+	}
+	return opts
+}
@@ -3,8 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/charemma/ikno/internal/ai"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -285,3 +288,155 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestLoad_WithProjects(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("IKNO_HOME", tmpDir)
+
+	configContent := `week_start: monday
+projects:
+  - name: acme-shop
+    client: ACME Corp
+    billable: true
+    paths: [~/code/acme-*]
+    remotes: [github.com/acme/*]
+    tags: [acme]
+  - name: ikno
+    paths: [~/code/ikno]
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Projects) != 2 {
+		t.Fatalf("expected 2 projects, got %d", len(cfg.Projects))
+	}
+	acme := cfg.Projects[0]
+	if acme.Name != "acme-shop" || acme.Client != "ACME Corp" || !acme.Billable || acme.Remotes[0] != "github.com/acme/*" {
+		t.Errorf("unexpected project: %+v", acme)
+	}
+
+	duplicate := configContent + "  - name: ACME-Shop\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte(duplicate), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Error("expected error for duplicate project names")
+	}
+}
+
+func TestLoad_WithSourceTimeouts(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("IKNO_HOME", tmpDir)
+
+	configContent := `week_start: monday
+source_timeout: 30s
+source_timeouts:
+  obsidian: 5m
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	opts := cfg.GetBuildOptions()
+	if opts.Timeout != 30*time.Second {
+		t.Errorf("expected timeout 30s, got %s", opts.Timeout)
+	}
+	if opts.Timeouts["obsidian"] != 5*time.Minute {
+		t.Errorf("expected obsidian timeout 5m, got %s", opts.Timeouts["obsidian"])
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte("source_timeout: -1s\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Error("expected error for negative source_timeout")
+	}
+}
+
+func TestLoad_WithTokenBudget(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("IKNO_HOME", tmpDir)
+
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("week_start: monday\n")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.AITokenBudget != 100_000 || cfg.AIChunkBy != "day" || !cfg.AISummaryCache {
+		t.Errorf("expected defaults 100000/day/cache, got %d/%s/%t", cfg.AITokenBudget, cfg.AIChunkBy, cfg.AISummaryCache)
+	}
+
+	write("ai_token_budget: 0\nai_chunk_by: Project\nai_summary_cache: false\n")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.AITokenBudget != 0 || cfg.AIChunkBy != "project" || cfg.AISummaryCache {
+		t.Errorf("expected 0/project/no cache, got %d/%s/%t", cfg.AITokenBudget, cfg.AIChunkBy, cfg.AISummaryCache)
+	}
+
+	for _, bad := range []string{"ai_token_budget: -1\n", "ai_chunk_by: week\n"} {
+		write(bad)
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestLoad_AIBackends(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("IKNO_HOME", tmpDir)
+
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("ai_backend: Anthropic\n")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.AIBackend != "anthropic" || cfg.AIBaseURL != defaultAIBaseURL || cfg.AIModel != defaultAIModel {
+		t.Errorf("unexpected anthropic config: %s %s %s", cfg.AIBackend, cfg.AIBaseURL, cfg.AIModel)
+	}
+
+	// The Anthropic defaults, also when saved back to the file, do not apply to Ollama.
+	write("ai_backend: ollama\nai_base_url: " + defaultAIBaseURL + "\nai_model: " + defaultAIModel + "\n")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.AIBaseURL != ai.DefaultOllamaURL || cfg.AIModel != "" {
+		t.Errorf("expected Ollama defaults, got %s %q", cfg.AIBaseURL, cfg.AIModel)
+	}
+
+	write("ai_backend: ollama\nai_base_url: http://gpu-box:11434\nai_model: llama3.2\n")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.AIBaseURL != "http://gpu-box:11434" || cfg.AIModel != "llama3.2" {
+		t.Errorf("explicit settings were replaced: %s %s", cfg.AIBaseURL, cfg.AIModel)
+	}
+
+	write("ai_backend: openai\n")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "cli, api, anthropic, ollama") {
+		t.Errorf("expected invalid backend error, got %v", err)
+	}
+}
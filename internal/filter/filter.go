@@ -0,0 +1,296 @@
+// Package filter selects recap entries with small boolean expressions such
+// as `source=git and repo~ikno and not content~"^chore"`.
+//
+// An expression compares entry fields with values:
+//
+//	field = value      equal, ignoring case
+//	field != value     not equal
+//	field ~ regex      regular expression match, ignoring case
+//	field !~ regex     no match
+//	field in (a, b)    equal to one of the values
+//
+// Comparisons combine with and, or, not and parentheses; and binds tighter
+// than or. Values are bare words or quoted with " or '. Fields with several
+// values (path, project, tag) match when any value does; != and !~ match
+// when none does.
+package filter
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charemma/ikno/internal/projects"
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// Fields lists the named fields with their meaning. A metadata key is
+// named with the "meta." prefix, as in meta.cwd.
+var Fields = []struct{ Name, Help string }{
+	{"source", "source type: git, claude, obsidian, ..."},
+	{"location", "source location: repository, vault, directory"},
+	{"path", "location, working directory, project root and file of the entry"},
+	{"repo", "repository name: git, ci and git-bug location, Claude cwd, editor project root"},
+	{"project", "mapped project, tracker project and repository name"},
+	{"content", "entry text (alias: text)"},
+	{"tag", "tags of notes and tasks (alias: tags)"},
+	{"branch", "git branch of commits and sessions"},
+}
+
+// Expr matches entries.
+type Expr interface {
+	Match(e sources.Entry) bool
+	String() string
+}
+
+// Apply returns the entries matching expr. A nil expr keeps all entries.
+func Apply(entries []sources.Entry, expr Expr) []sources.Entry {
+	if expr == nil {
+		return entries
+	}
+	filtered := entries[:0]
+	for _, e := range entries {
+		if expr.Match(e) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// And matches when all non-nil exprs match. It returns nil when there are
+// none, so optional filters can be combined without checks.
+func And(exprs ...Expr) Expr {
+	var ops []Expr
+	for _, e := range exprs {
+		if e != nil {
+			ops = append(ops, e)
+		}
+	}
+	switch len(ops) {
+	case 0:
+		return nil
+	case 1:
+		return ops[0]
+	}
+	return and(ops)
+}
+
+// Not negates expr.
+func Not(expr Expr) Expr {
+	return not{expr}
+}
+
+// In matches entries whose field equals one of values, ignoring case.
+func In(field string, values ...string) Expr {
+	return compare{field: normalizeField(field), op: opIn, values: values}
+}
+
+// Matches matches entries whose field matches the regular expression,
+// ignoring case.
+func Matches(field, pattern string) (Expr, error) {
+	re, err := compileValue(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return compare{field: normalizeField(field), op: opMatch, values: []string{pattern}, re: re}, nil
+}
+
+type and []Expr
+
+func (a and) Match(e sources.Entry) bool {
+	for _, x := range a {
+		if !x.Match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a and) String() string { return join(a, " and ") }
+
+type or []Expr
+
+func (o or) Match(e sources.Entry) bool {
+	for _, x := range o {
+		if x.Match(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o or) String() string { return join(o, " or ") }
+
+type not struct{ x Expr }
+
+func (n not) Match(e sources.Entry) bool { return !n.x.Match(e) }
+
+func (n not) String() string {
+	switch n.x.(type) {
+	case and, or:
+		return "not (" + n.x.String() + ")"
+	}
+	return "not " + n.x.String()
+}
+
+func join(exprs []Expr, sep string) string {
+	parts := make([]string, len(exprs))
+	for i, x := range exprs {
+		parts[i] = x.String()
+		if _, ok := x.(or); ok {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+const (
+	opEq       = "="
+	opNotEq    = "!="
+	opMatch    = "~"
+	opNotMatch = "!~"
+	opIn       = "in"
+)
+
+type compare struct {
+	field  string
+	op     string
+	values []string
+	re     *regexp.Regexp
+}
+
+func (c compare) Match(e sources.Entry) bool {
+	have := fieldValues(e, c.field)
+	switch c.op {
+	case opEq, opIn:
+		return anyEqual(have, c.values)
+	case opNotEq:
+		return !anyEqual(have, c.values)
+	case opMatch:
+		return anyMatch(have, c.re)
+	case opNotMatch:
+		return !anyMatch(have, c.re)
+	}
+	return false
+}
+
+func (c compare) String() string {
+	if c.op == opIn {
+		quoted := make([]string, len(c.values))
+		for i, v := range c.values {
+			quoted[i] = quote(v)
+		}
+		return c.field + " in (" + strings.Join(quoted, ", ") + ")"
+	}
+	return c.field + c.op + quote(c.values[0])
+}
+
+func quote(v string) string {
+	if v != "" && !strings.ContainsAny(v, " \t\"'(),=!~") {
+		return v
+	}
+	return `"` + strings.ReplaceAll(v, `"`, `\"`) + `"`
+}
+
+func anyEqual(have, want []string) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if strings.EqualFold(h, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func anyMatch(have []string, re *regexp.Regexp) bool {
+	for _, h := range have {
+		if re.MatchString(h) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileValue(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+func normalizeField(field string) string {
+	field = strings.ToLower(field)
+	switch field {
+	case "text":
+		return "content"
+	case "tags":
+		return "tag"
+	}
+	return field
+}
+
+// knownField reports whether a normalized field name is one of Fields or a
+// metadata key with the "meta." prefix.
+func knownField(field string) bool {
+	if key, ok := strings.CutPrefix(field, "meta."); ok {
+		return key != ""
+	}
+	return slices.ContainsFunc(Fields, func(f struct{ Name, Help string }) bool { return f.Name == field })
+}
+
+// fieldValues returns the values of a field for an entry; empty values are
+// left out.
+func fieldValues(e sources.Entry, field string) []string {
+	var values []string
+	add := func(vs ...string) {
+		for _, v := range vs {
+			if v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	switch field {
+	case "source":
+		add(e.Source)
+	case "location":
+		add(e.Location)
+	case "path":
+		add(e.Location, e.Metadata["cwd"], e.Metadata["project_root"], e.Metadata["file"], e.Metadata["path"])
+	case "repo":
+		add(repo(e))
+	case "project":
+		add(e.Metadata[projects.KeyProject], e.Metadata["project"], e.Metadata["project_name"], repo(e))
+	case "content":
+		add(e.Content)
+	case "tag":
+		for t := range strings.SplitSeq(e.Metadata["tags"], ",") {
+			add(strings.TrimSpace(t))
+		}
+	case "branch":
+		add(e.Metadata["branch"], e.Metadata["git_branch"])
+	default:
+		add(e.Metadata[strings.TrimPrefix(field, "meta.")])
+	}
+	return values
+}
+
+// repo returns the name of the repository an entry belongs to, or "".
+func repo(e sources.Entry) string {
+	var dir string
+	switch e.Source {
+	case "git", "ci", "git-bug":
+		dir = e.Location
+	case "claude":
+		dir = e.Metadata["cwd"]
+	case "editor":
+		dir = e.Metadata["project_root"]
+	}
+	if dir == "" {
+		return ""
+	}
+	return filepath.Base(dir)
+}
@@ -0,0 +1,140 @@
+package filter
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charemma/ikno/internal/projects"
+	"github.com/charemma/ikno/internal/sources"
+)
+
+var testEntries = []sources.Entry{
+	{Source: "git", Location: "/code/ikno", Content: "feat: add filters", Metadata: map[string]string{"branch": "feat/filter"}},
+	{Source: "git", Location: "/code/ikno", Content: "chore: bump deps"},
+	{Source: "git", Location: "/code/dotfiles", Content: "fix zsh prompt"},
+	{Source: "claude", Location: "/home/u/.claude", Content: "[ikno] filter DSL", Metadata: map[string]string{"cwd": "/code/ikno", "git_branch": "feat/filter"}},
+	{Source: "obsidian", Location: "/vault", Content: "Meeting notes", Metadata: map[string]string{"path": "Work/ACME/meeting.md", "tags": "work, acme/shop"}},
+	{Source: "timewarrior", Location: "/home/u/.timewarrior", Content: "Acme support", Metadata: map[string]string{"project": "Acme", projects.KeyProject: "acme-shop"}},
+}
+
+func TestParse_Match(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []int // indexes into testEntries
+	}{
+		{`source=git`, []int{0, 1, 2}},
+		{`source = GIT`, []int{0, 1, 2}},
+		{`source!=git`, []int{3, 4, 5}},
+		{`source=git and repo~ikno and not content~"^chore"`, []int{0}},
+		{`repo=ikno`, []int{0, 1, 3}},
+		{`source in (claude, obsidian)`, []int{3, 4}},
+		{`source=obsidian or content~'zsh'`, []int{2, 4}},
+		{`source=git and (content~feat or content~fix)`, []int{0, 2}},
+		{`not (source=git or source=claude)`, []int{4, 5}},
+		{`path~^Work/ACME`, []int{4}},
+		{`path~/code/ikno`, []int{0, 1, 3}},
+		{`tag=acme/shop`, []int{4}},
+		{`tags~^work$`, []int{4}},
+		{`project=acme-shop`, []int{5}},
+		{`project=acme`, []int{5}},
+		{`project=ikno`, []int{0, 1, 3}},
+		{`branch=feat/filter`, []int{0, 3}},
+		{`meta.cwd~ikno`, []int{3}},
+		{`text~"bump deps"`, []int{1}},
+		{`content!~"[a-z]"`, nil},
+		{`source=git or source=claude and content~DSL`, []int{0, 1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			var got []int
+			for i, e := range testEntries {
+				if expr.Match(e) {
+					got = append(got, i)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("matched %v, want %v (parsed as %s)", got, tt.want, expr)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`source`, "expected =, !=, ~, !~ or in"},
+		{`source=`, "expected a value"},
+		{`source=git and`, "expected a field name"},
+		{`(source=git`, "missing )"},
+		{`content~"unterminated`, "unterminated string"},
+		{`content~"("`, "invalid regular expression"},
+		{`source in git`, "expected ( after in"},
+		{`source=git) and`, `unexpected ")`},
+		{`=git`, "expected a field name"},
+		{`sorce=git`, `unknown field "sorce"`},
+		{`cwd~ikno`, "use meta.cwd"},
+		{`meta.=x`, "expected a metadata key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Parse(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse(%q) error = %v, want %q", tt.expr, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	expr, err := Parse("  ")
+	if err != nil || expr != nil {
+		t.Fatalf("expected nil expr, got %v, %v", expr, err)
+	}
+	if got := Apply(append([]sources.Entry(nil), testEntries...), expr); len(got) != len(testEntries) {
+		t.Errorf("nil filter dropped entries: %d of %d", len(got), len(testEntries))
+	}
+}
+
+func TestString_RoundTrip(t *testing.T) {
+	for _, in := range []string{
+		`source=git and (content~feat or content~fix)`,
+		`not content~"^chore: "`,
+		`source in (git, claude) and repo!=dotfiles`,
+		`not (source=git or source=claude) and content~x`,
+	} {
+		expr, err := Parse(in)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", in, err)
+		}
+		again, err := Parse(expr.String())
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", expr.String(), err)
+		}
+		if expr.String() != again.String() || expr.String() != in {
+			t.Errorf("String() not stable: %q vs %q", expr, again)
+		}
+	}
+}
+
+func TestAnd_Convenience(t *testing.T) {
+	grep, err := Matches("content", "filter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expr := And(nil, In("source", "git", "claude"), Not(In("repo", "dotfiles")), grep)
+	got := Apply(append([]sources.Entry(nil), testEntries...), expr)
+	if len(got) != 2 || got[0].Content != "feat: add filters" || got[1].Source != "claude" {
+		t.Errorf("unexpected entries: %+v", got)
+	}
+	if And(nil, nil) != nil {
+		t.Error("And of nils should be nil")
+	}
+}
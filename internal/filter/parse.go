@@ -0,0 +1,248 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Parse parses a filter expression. An empty expression yields a nil Expr,
+// which keeps all entries.
+func Parse(input string) (Expr, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+	p := &parser{input: input}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.rest())
+	}
+	return expr, nil
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid filter at position %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) rest() string {
+	r := p.input[p.pos:]
+	if len(r) > 20 {
+		r = r[:20] + "..."
+	}
+	return r
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// keyword consumes kw when it is the next word.
+func (p *parser) keyword(kw string) bool {
+	p.skipSpace()
+	end := p.pos + len(kw)
+	if end > len(p.input) || !strings.EqualFold(p.input[p.pos:end], kw) {
+		return false
+	}
+	if end < len(p.input) && isWordChar(p.input[end]) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+// symbol consumes s when it comes next.
+func (p *parser) symbol(s string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.input[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []Expr{left}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, right)
+	}
+	if len(exprs) == 1 {
+		return left, nil
+	}
+	return or(exprs), nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []Expr{left}
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, right)
+	}
+	if len(exprs) == 1 {
+		return left, nil
+	}
+	return and(exprs), nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.keyword("not") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(x), nil
+	}
+	if p.symbol("(") {
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.symbol(")") {
+			return nil, p.errorf("missing )")
+		}
+		return x, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (Expr, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) && isWordChar(p.input[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		if p.pos == len(p.input) {
+			return nil, p.errorf("expected a field name, got end of input")
+		}
+		return nil, p.errorf("expected a field name, got %q", p.rest())
+	}
+	field := normalizeField(p.input[start:p.pos])
+	if !knownField(field) {
+		p.pos = start
+		if field == "meta." {
+			return nil, p.errorf("expected a metadata key after meta.")
+		}
+		return nil, p.errorf("unknown field %q, use meta.%s for a metadata key", field, field)
+	}
+
+	if p.keyword("in") {
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return In(field, values...), nil
+	}
+
+	var op string
+	for _, candidate := range []string{opNotEq, opNotMatch, opEq, opMatch} {
+		if p.symbol(candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return nil, p.errorf("expected =, !=, ~, !~ or in after %q", field)
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	c := compare{field: field, op: op, values: []string{value}}
+	if op == opMatch || op == opNotMatch {
+		if c.re, err = compileValue(value); err != nil {
+			return nil, p.errorf("%v", err)
+		}
+	}
+	return c, nil
+}
+
+// parseList parses "(a, b, ...)".
+func (p *parser) parseList() ([]string, error) {
+	if !p.symbol("(") {
+		return nil, p.errorf("expected ( after in")
+	}
+	var values []string
+	for {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		if p.symbol(")") {
+			return values, nil
+		}
+		if !p.symbol(",") {
+			return nil, p.errorf("expected , or ) in list")
+		}
+	}
+}
+
+// parseValue parses a quoted string or a bare word. Bare words end at
+// whitespace, parentheses and commas.
+func (p *parser) parseValue() (string, error) {
+	p.skipSpace()
+	if p.pos == len(p.input) {
+		return "", p.errorf("expected a value, got end of input")
+	}
+	if q := p.input[p.pos]; q == '"' || q == '\'' {
+		var b strings.Builder
+		for i := p.pos + 1; i < len(p.input); i++ {
+			c := p.input[i]
+			switch {
+			case c == '\\' && i+1 < len(p.input) && (p.input[i+1] == q || p.input[i+1] == '\\'):
+				i++
+				b.WriteByte(p.input[i])
+			case c == q:
+				p.pos = i + 1
+				return b.String(), nil
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", p.errorf("unterminated string")
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if unicode.IsSpace(rune(c)) || c == '(' || c == ')' || c == ',' {
+			break
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf("expected a value, got %q", p.rest())
+	}
+	return p.input[start:p.pos], nil
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '.' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,36 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Remote identifies a repository on a code hosting service.
+type Remote struct {
+	Host string // e.g. "github.com"
+	Path string // owner/repo, or group/subgroup/project on GitLab
+}
+
+// Name returns the last path component, used as the repository name.
+func (r Remote) Name() string {
+	return r.Path[strings.LastIndex(r.Path, "/")+1:]
+}
+
+// ParseRemote parses a git remote URL in HTTPS, ssh:// or scp-like
+// (git@host:owner/repo.git) form.
+func ParseRemote(raw string) (Remote, error) {
+	raw = strings.TrimSpace(raw)
+	var host, path string
+	if u, err := url.Parse(raw); err == nil && u.Scheme != "" && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if user, rest, ok := strings.Cut(raw, ":"); ok && !strings.Contains(user, "/") {
+		host = user[strings.LastIndex(user, "@")+1:]
+		path = rest
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return Remote{}, fmt.Errorf("unsupported remote URL %q", raw)
+	}
+	return Remote{Host: host, Path: path}, nil
+}
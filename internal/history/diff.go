@@ -0,0 +1,119 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of a diff line.
+type Op byte
+
+const (
+	Equal  Op = ' '
+	Delete Op = '-'
+	Insert Op = '+'
+)
+
+// Line is one line of a diff.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Hunk is a run of changed lines with the unchanged lines around them.
+type Hunk struct {
+	OldStart, OldLines int // 1-based, as in unified diffs
+	NewStart, NewLines int
+	Lines              []Line
+}
+
+// Header returns the "@@ -a,b +c,d @@" line of the hunk.
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+}
+
+// DiffLines compares two texts line by line, using the longest common
+// subsequence. Recaps are short, so the quadratic table is no concern.
+func DiffLines(old, new string) []Line {
+	a, b := splitLines(old), splitLines(new)
+	// lcs[i][j] is the length of the common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []Line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, Line{Equal, a[i]})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			lines = append(lines, Line{Insert, b[j]})
+			j++
+		default:
+			lines = append(lines, Line{Delete, a[i]})
+			i++
+		}
+	}
+	return lines
+}
+
+// Hunks groups a diff into hunks with context unchanged lines around each
+// change. Changes closer than twice the context share a hunk.
+func Hunks(lines []Line, context int) []Hunk {
+	// oldAt[k] and newAt[k] are the line numbers lines[k] has, or would
+	// have, in the old and new text.
+	oldAt := make([]int, len(lines)+1)
+	newAt := make([]int, len(lines)+1)
+	oldAt[0], newAt[0] = 1, 1
+	var changes []int
+	for k, l := range lines {
+		oldAt[k+1], newAt[k+1] = oldAt[k], newAt[k]
+		if l.Op != Insert {
+			oldAt[k+1]++
+		}
+		if l.Op != Delete {
+			newAt[k+1]++
+		}
+		if l.Op != Equal {
+			changes = append(changes, k)
+		}
+	}
+
+	var hunks []Hunk
+	for c := 0; c < len(changes); {
+		first, last := changes[c], changes[c]
+		for c++; c < len(changes) && changes[c]-last <= 2*context; c++ {
+			last = changes[c]
+		}
+		from, to := max(first-context, 0), min(last+context+1, len(lines))
+		hunks = append(hunks, Hunk{
+			OldStart: oldAt[from],
+			OldLines: oldAt[to] - oldAt[from],
+			NewStart: newAt[from],
+			NewLines: newAt[to] - newAt[from],
+			Lines:    lines[from:to],
+		})
+	}
+	return hunks
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimRight(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
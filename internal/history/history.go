@@ -0,0 +1,209 @@
+// Package history archives generated AI recaps, so that they can be read
+// again and compared without another generation.
+package history
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Record is one generated recap with what produced it.
+type Record struct {
+	ID         string    `yaml:"id"`
+	Created    time.Time `yaml:"created"`
+	Timespec   string    `yaml:"timespec"`
+	From       string    `yaml:"from"` // first day, 2006-01-02
+	To         string    `yaml:"to"`   // last day, 2006-01-02
+	Compare    string    `yaml:"compare,omitempty"`
+	Project    string    `yaml:"project,omitempty"`
+	Style      string    `yaml:"style,omitempty"`
+	Language   string    `yaml:"language,omitempty"`
+	Model      string    `yaml:"model,omitempty"` // model name, or the command of the CLI backend
+	Entries    int       `yaml:"entries"`
+	PromptHash string    `yaml:"prompt_hash"`
+	Text       string    `yaml:"-"`
+}
+
+// SamePeriod reports whether r and other cover the same days.
+func (r *Record) SamePeriod(other *Record) bool {
+	return r.From == other.From && r.To == other.To && r.Compare == other.Compare && r.Project == other.Project
+}
+
+// HashPrompt returns a short hash of the prompt a recap was generated with,
+// to tell recaps of a changed prompt apart.
+func HashPrompt(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// ErrNotFound is returned when no record matches a reference.
+var ErrNotFound = errors.New("recap not found")
+
+// Archive stores records as Markdown files with YAML frontmatter, one file
+// per recap named after its ID.
+type Archive struct {
+	dir string
+}
+
+// NewArchive returns an archive stored in dir, which is created on the
+// first save.
+func NewArchive(dir string) *Archive {
+	return &Archive{dir: dir}
+}
+
+// Save writes r and sets its ID, derived from the creation time, if empty.
+func (a *Archive) Save(r *Record) error {
+	if err := os.MkdirAll(a.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	if r.Created.IsZero() {
+		r.Created = time.Now()
+	}
+	if r.ID == "" {
+		base := r.Created.Format("20060102-150405")
+		r.ID = base
+		for n := 2; a.exists(r.ID); n++ {
+			r.ID = fmt.Sprintf("%s-%d", base, n)
+		}
+	}
+
+	meta, err := yaml.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode recap: %w", err)
+	}
+	var b bytes.Buffer
+	b.WriteString("---\n")
+	b.Write(meta)
+	b.WriteString("---\n\n")
+	b.WriteString(strings.TrimSpace(r.Text))
+	b.WriteString("\n")
+	if err := os.WriteFile(a.path(r.ID), b.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write recap: %w", err)
+	}
+	return nil
+}
+
+// List returns all records, newest first.
+func (a *Archive) List() ([]*Record, error) {
+	files, err := filepath.Glob(filepath.Join(a.dir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	records := make([]*Record, 0, len(files))
+	for _, f := range files {
+		r, err := readRecord(f)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	slices.SortFunc(records, func(x, y *Record) int {
+		return cmp.Or(y.Created.Compare(x.Created), strings.Compare(y.ID, x.ID))
+	})
+	return records, nil
+}
+
+// Get returns the record ref refers to: an ID, a unique prefix of one, or
+// "latest" for the newest record.
+func (a *Archive) Get(ref string) (*Record, error) {
+	if ref != "latest" && a.exists(ref) {
+		return readRecord(a.path(ref))
+	}
+	records, err := a.List()
+	if err != nil {
+		return nil, err
+	}
+	if ref == "latest" {
+		if len(records) == 0 {
+			return nil, fmt.Errorf("%w: the history is empty", ErrNotFound)
+		}
+		return records[0], nil
+	}
+
+	var matches []*Record
+	for _, r := range records {
+		if strings.HasPrefix(r.ID, ref) {
+			matches = append(matches, r)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, ref)
+	case 1:
+		return matches[0], nil
+	}
+	return nil, fmt.Errorf("%s matches %d recaps, use a longer prefix", ref, len(matches))
+}
+
+// Previous returns the newest record created before r that covers the
+// same period, such as an earlier generation of the same weekly report.
+func (a *Archive) Previous(r *Record) (*Record, error) {
+	records, err := a.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range records {
+		if other.ID != r.ID && other.Created.Before(r.Created) && other.SamePeriod(r) {
+			return other, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no earlier recap of %s to %s", ErrNotFound, r.From, r.To)
+}
+
+// Remove deletes the record with the given ID.
+func (a *Archive) Remove(id string) error {
+	if err := os.Remove(a.path(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return fmt.Errorf("failed to remove recap: %w", err)
+	}
+	return nil
+}
+
+func (a *Archive) path(id string) string {
+	return filepath.Join(a.dir, id+".md")
+}
+
+func (a *Archive) exists(id string) bool {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return false
+	}
+	_, err := os.Stat(a.path(id))
+	return err == nil
+}
+
+// readRecord parses a record file: frontmatter between "---" lines, then
+// the recap text.
+func readRecord(path string) (*Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recap: %w", err)
+	}
+	rest, ok := strings.CutPrefix(string(data), "---\n")
+	if !ok {
+		return nil, fmt.Errorf("failed to parse %s: missing frontmatter", filepath.Base(path))
+	}
+	meta, text, ok := strings.Cut(rest, "\n---\n")
+	if !ok {
+		return nil, fmt.Errorf("failed to parse %s: unterminated frontmatter", filepath.Base(path))
+	}
+	r := &Record{}
+	if err := yaml.Unmarshal([]byte(meta), r); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	r.ID = strings.TrimSuffix(filepath.Base(path), ".md")
+	r.Text = strings.TrimSpace(text)
+	return r, nil
+}
@@ -0,0 +1,119 @@
+package history
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArchive(t *testing.T) {
+	archive := NewArchive(t.TempDir())
+	created := time.Date(2026, 4, 17, 16, 30, 0, 0, time.UTC)
+	week := func(offset time.Duration, text string) *Record {
+		return &Record{
+			Created:    created.Add(offset),
+			Timespec:   "thisweek",
+			From:       "2026-04-13",
+			To:         "2026-04-19",
+			Style:      "report",
+			Language:   "english",
+			Model:      "claude -p",
+			Entries:    42,
+			PromptHash: HashPrompt("Write a report."),
+			Text:       text,
+		}
+	}
+
+	first := week(0, "## Done\n\n- shipped login\n")
+	again := week(0, "## Done\n\n- shipped login\n- fixed checkout\n")
+	standup := &Record{Created: created.Add(time.Hour), Timespec: "yesterday", From: "2026-04-16", To: "2026-04-16", Style: "brief", Text: "- reviewed PRs"}
+	for _, r := range []*Record{first, again, standup} {
+		if err := archive.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if first.ID != "20260417-163000" || again.ID != "20260417-163000-2" {
+		t.Fatalf("unexpected IDs %s, %s", first.ID, again.ID)
+	}
+
+	records, err := archive.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0].ID != standup.ID || records[1].ID != again.ID {
+		t.Fatalf("expected newest first, got %v", ids(records))
+	}
+	if records[1].Text != strings.TrimSpace(again.Text) || records[1].Entries != 42 || records[1].Model != "claude -p" || !records[1].Created.Equal(created) {
+		t.Errorf("record did not round-trip: %+v", records[1])
+	}
+
+	if r, err := archive.Get("latest"); err != nil || r.ID != standup.ID {
+		t.Errorf("Get(latest) = %v, %v", r, err)
+	}
+	if r, err := archive.Get("20260417-163000-"); err != nil || r.ID != again.ID {
+		t.Errorf("Get(prefix) = %v, %v", r, err)
+	}
+	if _, err := archive.Get("20260417"); err == nil || !strings.Contains(err.Error(), "matches 3 recaps") {
+		t.Errorf("ambiguous prefix: %v", err)
+	}
+	if _, err := archive.Get("2025"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown prefix: %v", err)
+	}
+
+	// The regenerated report, a minute later, is compared with the first.
+	later, _ := archive.Get(again.ID)
+	later.Created = later.Created.Add(time.Minute)
+	if prev, err := archive.Previous(later); err != nil || prev.ID != first.ID {
+		t.Errorf("Previous = %v, %v", prev, err)
+	}
+	if _, err := archive.Previous(standup); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Previous of a single recap: %v", err)
+	}
+
+	if err := archive.Remove(first.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Remove(first.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("removing twice: %v", err)
+	}
+	if records, _ := archive.List(); len(records) != 2 {
+		t.Errorf("expected 2 records after removal, got %v", ids(records))
+	}
+}
+
+func ids(records []*Record) []string {
+	var out []string
+	for _, r := range records {
+		out = append(out, r.ID)
+	}
+	return out
+}
+
+func TestDiff(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	new := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+
+	lines := DiffLines(old, new)
+	var ops strings.Builder
+	for _, l := range lines {
+		ops.WriteByte(byte(l.Op))
+	}
+	if ops.String() != " -+         +" {
+		t.Fatalf("ops = %q", ops.String())
+	}
+
+	hunks := Hunks(lines, 2)
+	if len(hunks) != 2 {
+		t.Fatalf("expected 2 hunks, got %+v", hunks)
+	}
+	if hunks[0].Header() != "@@ -1,4 +1,4 @@" || hunks[1].Header() != "@@ -10,2 +10,3 @@" {
+		t.Errorf("headers %q, %q", hunks[0].Header(), hunks[1].Header())
+	}
+	if len(Hunks(DiffLines(old, old), 3)) != 0 {
+		t.Error("identical texts should have no hunks")
+	}
+	if len(Hunks(lines, 5)) != 1 {
+		t.Error("close changes should share a hunk")
+	}
+}
@@ -0,0 +1,328 @@
+// Package projects maps the many ways sources name a project (repository
+// paths, git remotes, Claude working directories, note folders, tags and
+// tracker project names) to one canonical project configured by the user.
+package projects
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/charemma/ikno/internal/git"
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// Entry metadata keys set by Resolver.Tag. They are kept apart from the
+// sources' own "project" and "client" keys, which hold source-specific names.
+const (
+	KeyProject  = "mapped_project"
+	KeyClient   = "mapped_client"
+	KeyBillable = "mapped_billable"
+)
+
+// Project is a canonical project and the rules that map entries to it. An
+// entry belongs to the first project in config order with a matching rule.
+type Project struct {
+	Name     string `yaml:"name"`
+	Client   string `yaml:"client,omitempty"`
+	Billable bool   `yaml:"billable,omitempty"`
+
+	// Paths are directories or glob patterns ("~/code/acme-*"). They match
+	// repository paths, Claude working directories, editor project roots
+	// and note files at or below them.
+	Paths []string `yaml:"paths,omitempty"`
+	// Remotes are git remotes as host/path ("github.com/acme/*"); full
+	// remote URLs are accepted too.
+	Remotes []string `yaml:"remotes,omitempty"`
+	// Folders are note folders relative to a vault or notes directory
+	// ("1 Projects/acme"), matched anywhere in a note's path.
+	Folders []string `yaml:"folders,omitempty"`
+	// Tags match note and task tags, including nested tags ("acme" matches
+	// "acme/billing").
+	Tags []string `yaml:"tags,omitempty"`
+	// Names are project names used by sources themselves, such as a
+	// Timewarrior or Toggl project, compared case-insensitively.
+	Names []string `yaml:"names,omitempty"`
+}
+
+// Validate checks that every project has a unique name and valid patterns.
+func Validate(projects []Project) error {
+	seen := make(map[string]bool)
+	for i, p := range projects {
+		if strings.TrimSpace(p.Name) == "" {
+			return fmt.Errorf("project %d has no name", i+1)
+		}
+		key := strings.ToLower(p.Name)
+		if seen[key] {
+			return fmt.Errorf("project %q is defined twice", p.Name)
+		}
+		seen[key] = true
+
+		for _, pattern := range p.Paths {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return fmt.Errorf("project %q: invalid path pattern %q: %w", p.Name, pattern, err)
+			}
+		}
+		for _, pattern := range p.Remotes {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("project %q: invalid remote pattern %q: %w", p.Name, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Find returns the project with the given name, compared case-insensitively.
+func Find(projects []Project, name string) (*Project, bool) {
+	for i := range projects {
+		if strings.EqualFold(projects[i].Name, name) {
+			return &projects[i], true
+		}
+	}
+	return nil, false
+}
+
+// Names returns the names of the projects in config order.
+func Names(projects []Project) []string {
+	names := make([]string, len(projects))
+	for i, p := range projects {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Resolver matches entries against the configured projects.
+type Resolver struct {
+	projects []Project
+	home     string
+	remotes  map[string]string // checkout dir -> normalized remote, "" if none
+
+	remoteOf func(ctx context.Context, dir string) (string, error) // reads the origin URL of a checkout
+}
+
+// NewResolver creates a resolver for the given projects.
+func NewResolver(projects []Project) *Resolver {
+	home, _ := os.UserHomeDir()
+	return &Resolver{
+		projects: projects,
+		home:     home,
+		remotes:  make(map[string]string),
+		remoteOf: gitRemote,
+	}
+}
+
+// Tag sets the mapped project, client and billable flag on every entry that
+// matches a project. Entries without a match are left alone.
+func (r *Resolver) Tag(ctx context.Context, entries []sources.Entry) {
+	if len(r.projects) == 0 {
+		return
+	}
+	for i := range entries {
+		p, ok := r.Match(ctx, entries[i])
+		if !ok {
+			continue
+		}
+		if entries[i].Metadata == nil {
+			entries[i].Metadata = make(map[string]string)
+		}
+		entries[i].Metadata[KeyProject] = p.Name
+		if p.Client != "" {
+			entries[i].Metadata[KeyClient] = p.Client
+		}
+		if p.Billable {
+			entries[i].Metadata[KeyBillable] = "true"
+		}
+	}
+}
+
+// Match returns the first project with a rule matching the entry.
+func (r *Resolver) Match(ctx context.Context, e sources.Entry) (*Project, bool) {
+	paths := entryPaths(e)
+	var remote string
+	var remoteRead bool
+
+	for i := range r.projects {
+		p := &r.projects[i]
+		if r.matchPaths(p, paths) || matchFolders(p, paths, e.Metadata["path"]) || matchTags(p, e.Metadata["tags"]) || matchNames(p, e) {
+			return p, true
+		}
+		if len(p.Remotes) == 0 {
+			continue
+		}
+		if !remoteRead {
+			remote, remoteRead = r.remote(ctx, e), true
+		}
+		if matchRemotes(p, remote) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// entryPaths returns the absolute paths an entry is about: its location and
+// any working directory, project root or file recorded in metadata.
+func entryPaths(e sources.Entry) []string {
+	var paths []string
+	add := func(p string) {
+		if filepath.IsAbs(p) {
+			paths = append(paths, filepath.Clean(p))
+		}
+	}
+	add(e.Metadata["cwd"])
+	add(e.Metadata["project_root"])
+	add(e.Metadata["file"])
+	if rel := e.Metadata["path"]; rel != "" && !filepath.IsAbs(rel) && filepath.IsAbs(e.Location) {
+		// Vault-relative note paths (obsidian, logseq).
+		add(filepath.Join(e.Location, filepath.FromSlash(rel)))
+	}
+	add(e.Location)
+	return paths
+}
+
+func (r *Resolver) matchPaths(p *Project, paths []string) bool {
+	for _, pattern := range p.Paths {
+		pattern = r.expandHome(pattern)
+		for _, candidate := range paths {
+			if matchPath(pattern, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchPath reports whether p is the pattern directory or below it. Glob
+// patterns are matched against p and each of its parents.
+func matchPath(pattern, p string) bool {
+	pattern = filepath.Clean(pattern)
+	if !strings.ContainsAny(pattern, `*?[`) {
+		return p == pattern || strings.HasPrefix(p, pattern+string(filepath.Separator))
+	}
+	for dir := p; ; dir = filepath.Dir(dir) {
+		if ok, _ := filepath.Match(pattern, dir); ok {
+			return true
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return false
+		}
+	}
+}
+
+func (r *Resolver) expandHome(p string) string {
+	if r.home != "" && (p == "~" || strings.HasPrefix(p, "~/")) {
+		return filepath.Join(r.home, p[1:])
+	}
+	return p
+}
+
+func matchFolders(p *Project, paths []string, rel string) bool {
+	if len(p.Folders) == 0 {
+		return false
+	}
+	candidates := []string{"/" + filepath.ToSlash(rel)}
+	for _, abs := range paths {
+		candidates = append(candidates, filepath.ToSlash(abs))
+	}
+	for _, folder := range p.Folders {
+		folder = "/" + strings.Trim(filepath.ToSlash(folder), "/") + "/"
+		for _, c := range candidates {
+			if strings.Contains(c, folder) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchTags(p *Project, tags string) bool {
+	if tags == "" {
+		return false
+	}
+	for tag := range strings.SplitSeq(tags, ",") {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		for _, want := range p.Tags {
+			want = strings.ToLower(strings.TrimPrefix(want, "#"))
+			if tag == want || strings.HasPrefix(tag, want+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchNames(p *Project, e sources.Entry) bool {
+	for _, key := range []string{"project", "project_name"} {
+		name := e.Metadata[key]
+		if name == "" || strings.ContainsAny(name, `/\`) {
+			continue
+		}
+		if strings.EqualFold(name, p.Name) {
+			return true
+		}
+		for _, alias := range p.Names {
+			if strings.EqualFold(name, alias) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchRemotes(p *Project, remote string) bool {
+	if remote == "" {
+		return false
+	}
+	for _, pattern := range p.Remotes {
+		if ok, _ := path.Match(normalizeRemote(pattern), remote); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// remote returns the normalized origin remote of the checkout an entry
+// belongs to, or "" when it has none. Results are cached per directory.
+func (r *Resolver) remote(ctx context.Context, e sources.Entry) string {
+	var dir string
+	switch e.Source {
+	case "git", "ci", "git-bug":
+		dir = e.Location
+	case "claude":
+		dir = e.Metadata["cwd"]
+	case "editor":
+		dir = e.Metadata["project_root"]
+	}
+	if dir == "" {
+		return ""
+	}
+	if remote, ok := r.remotes[dir]; ok {
+		return remote
+	}
+	var remote string
+	if url, err := r.remoteOf(ctx, dir); err == nil {
+		remote = normalizeRemote(url)
+	}
+	r.remotes[dir] = remote
+	return remote
+}
+
+// normalizeRemote turns a remote URL into lowercase host/path. Values that
+// are not URLs, such as patterns, are only trimmed.
+func normalizeRemote(raw string) string {
+	if remote, err := git.ParseRemote(raw); err == nil {
+		return strings.ToLower(remote.Host + "/" + remote.Path)
+	}
+	return strings.ToLower(strings.TrimSuffix(strings.Trim(strings.TrimSpace(raw), "/"), ".git"))
+}
+
+func gitRemote(ctx context.Context, dir string) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
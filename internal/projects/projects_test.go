@@ -0,0 +1,79 @@
+package projects
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+func TestResolver_Tag(t *testing.T) {
+	configured := []Project{
+		{Name: "acme-shop", Client: "ACME Corp", Billable: true,
+			Paths: []string{"~/code/acme-*"}, Remotes: []string{"github.com/acme/*"},
+			Folders: []string{"1 Projects/ACME"}, Tags: []string{"acme"}, Names: []string{"ACME"}},
+		{Name: "ikno", Paths: []string{"/home/u/code/ikno"}},
+	}
+
+	entries := []sources.Entry{
+		{Source: "git", Location: "/home/u/code/acme-api"},
+		{Source: "git", Location: "/srv/checkouts/shop"},
+		{Source: "claude", Location: "/home/u/.claude", Metadata: map[string]string{"cwd": "/home/u/code/ikno/internal/recap", "project_name": "ikno"}},
+		{Source: "obsidian", Location: "/home/u/vault", Metadata: map[string]string{"path": "1 Projects/ACME/Kickoff.md"}},
+		{Source: "obsidian", Location: "/home/u/vault", Metadata: map[string]string{"path": "Inbox.md", "tags": "daily,acme/billing"}},
+		{Source: "timewarrior", Location: "/home/u/.timewarrior", Metadata: map[string]string{"project": "acme"}},
+		{Source: "markdown", Location: "/home/u/notes/acme.md"},
+		{Source: "git", Location: "/home/u/code/ikno-fork"},
+	}
+
+	r := NewResolver(configured)
+	r.home = "/home/u"
+	var lookups int
+	r.remoteOf = func(_ context.Context, dir string) (string, error) {
+		lookups++
+		if dir == "/srv/checkouts/shop" {
+			return "git@github.com:ACME/shop.git", nil
+		}
+		return "", errors.New("no remote")
+	}
+	r.Tag(t.Context(), entries)
+
+	want := []string{"acme-shop", "acme-shop", "ikno", "acme-shop", "acme-shop", "acme-shop", "", ""}
+	for i, e := range entries {
+		if got := e.Metadata[KeyProject]; got != want[i] {
+			t.Errorf("entry %d (%s %s): project = %q, want %q", i, e.Source, e.Location, got, want[i])
+		}
+	}
+	if entries[0].Metadata[KeyClient] != "ACME Corp" || entries[0].Metadata[KeyBillable] != "true" {
+		t.Errorf("client and billable not set: %+v", entries[0].Metadata)
+	}
+	if _, ok := entries[2].Metadata[KeyBillable]; ok {
+		t.Error("non-billable projects should not set the billable flag")
+	}
+	// The Claude session is checked against the acme-shop remotes before it
+	// matches ikno by path; acme-api matched by path without a lookup.
+	if lookups != 3 {
+		t.Errorf("expected 3 remote lookups, got %d", lookups)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		projects []Project
+		wantErr  bool
+	}{
+		{"ok", []Project{{Name: "a", Paths: []string{"~/code/a-*"}}, {Name: "b"}}, false},
+		{"missing name", []Project{{Paths: []string{"/x"}}}, true},
+		{"duplicate", []Project{{Name: "a"}, {Name: "A"}}, true},
+		{"bad glob", []Project{{Name: "a", Paths: []string{"/code/[a"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.projects); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,11 +1,15 @@
 package recap
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"sort"
 	"sync"
+	"time"
 
+	"github.com/charemma/ikno/internal/filter"
+	"github.com/charemma/ikno/internal/projects"
 	"github.com/charemma/ikno/internal/sources"
 	"github.com/charemma/ikno/internal/sources/git"
 	"github.com/charemma/ikno/internal/timerange"
@@ -17,66 +21,169 @@ type SourceFactory func(sources.Config) (sources.Source, error)
 
 // BuildOptions controls optional behaviour during recap collection.
 type BuildOptions struct {
-	EnrichDiffs bool // fetch full diffs for git sources
+	EnrichDiffs bool                     // fetch full diffs for git sources
+	Projects    []projects.Project       // tag entries with their canonical project
+	Timeout     time.Duration            // per-source time limit; 0 waits forever
+	Timeouts    map[string]time.Duration // per source type, overrides Timeout
+	Filter      filter.Expr              // keep only matching entries; nil keeps all
+}
+
+// timeoutFor returns the time limit for one source type.
+func (o BuildOptions) timeoutFor(sourceType string) time.Duration {
+	if d, ok := o.Timeouts[sourceType]; ok {
+		return d
+	}
+	return o.Timeout
+}
+
+// Stages at which a source can fail.
+const (
+	StageCreate  = "create"  // the source could not be set up from its config
+	StageCollect = "collect" // reading entries failed; none are included
+	StageEnrich  = "enrich"  // diffs are missing; entries are included
+	StageTag     = "tag"     // git remotes could not be read; entries are included, perhaps unmapped
+)
+
+// SourceFailure records a source that could not be read completely.
+type SourceFailure struct {
+	Type     string
+	Location string
+	Stage    string
+	Err      error
+	TimedOut bool // the source hit its time limit
+}
+
+func (f SourceFailure) Error() string {
+	switch f.Stage {
+	case StageCreate:
+		return fmt.Sprintf("%v at %s", f.Err, f.Location)
+	case StageEnrich:
+		return fmt.Sprintf("failed to enrich diffs for %s: %v", f.Location, f.Err)
+	case StageTag:
+		return fmt.Sprintf("failed to map projects for %s: %v", f.Location, f.Err)
+	default:
+		return fmt.Sprintf("failed to get entries from %s %s: %v", f.Type, f.Location, f.Err)
+	}
+}
+
+func (f SourceFailure) Unwrap() error {
+	return f.Err
+}
+
+// SourceReport describes how reading one source went. Sources are read on
+// every run; the AI summary cache holds whole days, not the entries of a
+// source, so a report has no cache state.
+type SourceReport struct {
+	Type     string
+	Location string
+	Entries  int           // entries read, before any filtering
+	Duration time.Duration // time spent reading, including diffs
+	Failure  *SourceFailure
+}
+
+// OK reports whether the source was read completely.
+func (r SourceReport) OK() bool {
+	return r.Failure == nil
 }
 
 // BuildRecap collects entries from all configured sources for the given time
 // range. When opts.EnrichDiffs is true, git sources are enriched with diffs.
-// Warnings about individual source failures are written to warn.
-func BuildRecap(sourceConfigs []sources.Config, tr *timerange.TimeRange, timespec string, opts BuildOptions, factory SourceFactory, warn io.Writer) (*RecapResult, error) {
+// Entries matching one of opts.Projects are tagged with its name, client and
+// billable flag (see projects.Resolver). opts.Filter is applied after
+// tagging, so it can select mapped projects.
+//
+// Each source runs with its own time limit (see BuildOptions.Timeout),
+// which also covers reading git remotes for the project mapping. Every
+// source gets a SourceReport in RecapResult.Sources, in config order; one
+// that fails or times out carries its failure there, and the other sources
+// are still reported. Only when ctx itself is canceled does BuildRecap
+// return an error.
+func BuildRecap(ctx context.Context, sourceConfigs []sources.Config, tr *timerange.TimeRange, timespec string, opts BuildOptions, factory SourceFactory) (*RecapResult, error) {
 	// Collect entries from all sources concurrently.
 	type sourceResult struct {
-		source  sources.Source
 		entries []sources.Entry
+		report  SourceReport
 	}
 
 	results := make([]sourceResult, len(sourceConfigs))
 	var wg sync.WaitGroup
-	var mu sync.Mutex
 
 	for i, cfg := range sourceConfigs {
 		wg.Add(1)
 		go func(idx int, cfg sources.Config) {
 			defer wg.Done()
+			r := &results[idx]
+			r.report = SourceReport{Type: cfg.Type, Location: cfg.Path}
+			start := time.Now()
+			defer func() { r.report.Duration = time.Since(start) }()
 
 			source, err := factory(cfg)
 			if err != nil {
-				mu.Lock()
-				_, _ = fmt.Fprintf(warn, "Warning: %v at %s\n", err, cfg.Path)
-				mu.Unlock()
+				r.report.Failure = &SourceFailure{Type: cfg.Type, Location: cfg.Path, Stage: StageCreate, Err: err}
 				return
 			}
+			r.report.Location = source.Location()
+
+			sctx := ctx
+			timeout := opts.timeoutFor(cfg.Type)
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				sctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			fail := func(stage string, err error) {
+				f := &SourceFailure{Type: cfg.Type, Location: source.Location(), Stage: stage, Err: err}
+				// Killed commands report "signal: killed"; the context
+				// tells why.
+				if ctx.Err() == nil && errors.Is(sctx.Err(), context.DeadlineExceeded) {
+					f.TimedOut = true
+					f.Err = fmt.Errorf("timed out after %s: %w", timeout, context.DeadlineExceeded)
+				}
+				r.report.Failure = f
+			}
 
-			entries, err := source.GetEntries(tr.From, tr.To)
+			entries, err := source.GetEntries(sctx, tr.From, tr.To)
 			if err != nil {
-				mu.Lock()
-				_, _ = fmt.Fprintf(warn, "Warning: failed to get entries from %s %s: %v\n", cfg.Type, cfg.Path, err)
-				mu.Unlock()
+				fail(StageCollect, err)
 				return
 			}
 
 			// Enrich git entries with diffs when requested
 			if opts.EnrichDiffs {
 				if gs, ok := source.(*git.GitSource); ok {
-					if err := gs.EnrichWithDiffs(entries); err != nil {
-						mu.Lock()
-						_, _ = fmt.Fprintf(warn, "Warning: failed to enrich diffs for %s: %v\n", gs.Location(), err)
-						mu.Unlock()
+					if err := gs.EnrichWithDiffs(sctx, entries); err != nil {
+						fail(StageEnrich, err)
 					}
 				}
 			}
 
-			results[idx] = sourceResult{source: source, entries: entries}
+			// Remotes are read with git, which can hang on a network
+			// mount like the source itself.
+			projects.NewResolver(opts.Projects).Tag(sctx, entries)
+			if err := sctx.Err(); err != nil && r.report.Failure == nil {
+				fail(StageTag, err)
+			}
+
+			r.entries = entries
+			r.report.Entries = len(entries)
 		}(i, cfg)
 	}
 
 	wg.Wait()
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	var allEntries []sources.Entry
-	for _, r := range results {
+	reports := make([]SourceReport, len(results))
+	for i, r := range results {
 		allEntries = append(allEntries, r.entries...)
+		reports[i] = r.report
 	}
 
+	allEntries = filter.Apply(allEntries, opts.Filter)
+
 	// Sort entries by timestamp (newest first)
 	sort.Slice(allEntries, func(i, j int) bool {
 		return allEntries[i].Timestamp.After(allEntries[j].Timestamp)
@@ -86,5 +193,6 @@ func BuildRecap(sourceConfigs []sources.Config, tr *timerange.TimeRange, timespe
 		TimeRange: tr,
 		Timespec:  timespec,
 		Entries:   allEntries,
+		Sources:   reports,
 	}, nil
 }
@@ -0,0 +1,196 @@
+package recap
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/charemma/ikno/internal/filter"
+	"github.com/charemma/ikno/internal/projects"
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/timerange"
+)
+
+// fakeSource returns its entries, or blocks until the context is done when
+// hang is set.
+type fakeSource struct {
+	typ      string
+	location string
+	entries  []sources.Entry
+	hang     bool
+}
+
+func (f *fakeSource) Type() string     { return f.typ }
+func (f *fakeSource) Location() string { return f.location }
+func (f *fakeSource) Validate() error  { return nil }
+
+func (f *fakeSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	if f.hang {
+		<-ctx.Done()
+		return nil, errors.New("signal: killed")
+	}
+	return f.entries, nil
+}
+
+func fakeFactory(fakes map[string]*fakeSource) SourceFactory {
+	return func(cfg sources.Config) (sources.Source, error) {
+		if f, ok := fakes[cfg.Path]; ok {
+			return f, nil
+		}
+		return nil, errors.New("unknown source type: " + cfg.Type)
+	}
+}
+
+func TestBuildRecap_Failures(t *testing.T) {
+	now := time.Now()
+	tr := &timerange.TimeRange{From: now.Add(-time.Hour), To: now}
+	fakes := map[string]*fakeSource{
+		"/code/ikno":  {typ: "git", location: "/code/ikno", entries: []sources.Entry{{Timestamp: now, Source: "git", Location: "/code/ikno", Content: "feat: context"}}},
+		"/mnt/remote": {typ: "git", location: "/mnt/remote", hang: true},
+	}
+	configs := []sources.Config{
+		{Type: "git", Path: "/code/ikno"},
+		{Type: "git", Path: "/mnt/remote"},
+		{Type: "jira", Path: "/nowhere"},
+	}
+
+	start := time.Now()
+	result, err := BuildRecap(t.Context(), configs, tr, "today", BuildOptions{Timeout: 50 * time.Millisecond}, fakeFactory(fakes))
+	if err != nil {
+		t.Fatalf("BuildRecap: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("hung source was not stopped, took %s", elapsed)
+	}
+
+	if len(result.Entries) != 1 || result.Entries[0].Content != "feat: context" {
+		t.Errorf("expected the entry of the working source, got %+v", result.Entries)
+	}
+	if len(result.Sources) != 3 {
+		t.Fatalf("expected 3 source reports, got %+v", result.Sources)
+	}
+	if ok := result.Sources[0]; !ok.OK() || ok.Location != "/code/ikno" || ok.Entries != 1 {
+		t.Errorf("unexpected report for working source: %+v", ok)
+	}
+	if hung := result.Sources[1]; hung.OK() || hung.Duration < 50*time.Millisecond {
+		t.Errorf("unexpected report for hung source: %+v", hung)
+	}
+
+	failures := result.Failures()
+	if len(failures) != 2 {
+		t.Fatalf("expected 2 failures, got %+v", failures)
+	}
+
+	timedOut := failures[0]
+	if timedOut.Location != "/mnt/remote" || timedOut.Stage != StageCollect || !timedOut.TimedOut {
+		t.Errorf("unexpected failure for hung source: %+v", timedOut)
+	}
+	if !errors.Is(timedOut, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", timedOut.Err)
+	}
+	if want := "failed to get entries from git /mnt/remote: timed out after 50ms: context deadline exceeded"; timedOut.Error() != want {
+		t.Errorf("Error() = %q, want %q", timedOut.Error(), want)
+	}
+
+	create := failures[1]
+	if create.Type != "jira" || create.Stage != StageCreate || create.TimedOut {
+		t.Errorf("unexpected failure for unknown source: %+v", create)
+	}
+}
+
+func TestBuildRecap_PerTypeTimeout(t *testing.T) {
+	now := time.Now()
+	tr := &timerange.TimeRange{From: now.Add(-time.Hour), To: now}
+	fakes := map[string]*fakeSource{
+		"/vault": {typ: "obsidian", location: "/vault", hang: true},
+	}
+	configs := []sources.Config{{Type: "obsidian", Path: "/vault"}}
+	opts := BuildOptions{Timeout: time.Hour, Timeouts: map[string]time.Duration{"obsidian": 20 * time.Millisecond}}
+
+	result, err := BuildRecap(t.Context(), configs, tr, "today", opts, fakeFactory(fakes))
+	if err != nil {
+		t.Fatalf("BuildRecap: %v", err)
+	}
+	if failures := result.Failures(); len(failures) != 1 || !failures[0].TimedOut {
+		t.Errorf("expected the obsidian source to time out, got %+v", failures)
+	}
+}
+
+func TestBuildRecap_RemoteLookupTimeout(t *testing.T) {
+	// A git that never answers, as on a hung network mount.
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "git"), []byte("#!/bin/sh\nexec sleep 10\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	now := time.Now()
+	tr := &timerange.TimeRange{From: now.Add(-time.Hour), To: now}
+	fakes := map[string]*fakeSource{
+		"/mnt/remote/api": {typ: "git", location: "/mnt/remote/api", entries: []sources.Entry{{Timestamp: now, Source: "git", Location: "/mnt/remote/api", Content: "fix: retries"}}},
+	}
+	configs := []sources.Config{{Type: "git", Path: "/mnt/remote/api"}}
+	opts := BuildOptions{Timeout: 50 * time.Millisecond, Projects: []projects.Project{{Name: "acme", Remotes: []string{"github.com/acme/*"}}}}
+
+	start := time.Now()
+	result, err := BuildRecap(t.Context(), configs, tr, "today", opts, fakeFactory(fakes))
+	if err != nil {
+		t.Fatalf("BuildRecap: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("remote lookup was not stopped, took %s", elapsed)
+	}
+	if len(result.Entries) != 1 {
+		t.Errorf("expected the entry without a project, got %+v", result.Entries)
+	}
+	if failures := result.Failures(); len(failures) != 1 || failures[0].Stage != StageTag || !failures[0].TimedOut {
+		t.Errorf("expected a timed out project mapping, got %+v", failures)
+	}
+}
+
+func TestBuildRecap_Canceled(t *testing.T) {
+	now := time.Now()
+	tr := &timerange.TimeRange{From: now.Add(-time.Hour), To: now}
+	fakes := map[string]*fakeSource{
+		"/mnt/remote": {typ: "git", location: "/mnt/remote", hang: true},
+	}
+	configs := []sources.Config{{Type: "git", Path: "/mnt/remote"}}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err := BuildRecap(ctx, configs, tr, "today", BuildOptions{}, fakeFactory(fakes))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+}
+
+func TestBuildRecap_Filter(t *testing.T) {
+	now := time.Now()
+	tr := &timerange.TimeRange{From: now.Add(-time.Hour), To: now}
+	fakes := map[string]*fakeSource{
+		"/code/ikno": {typ: "git", location: "/code/ikno", entries: []sources.Entry{
+			{Timestamp: now, Source: "git", Location: "/code/ikno", Content: "feat: filters"},
+			{Timestamp: now, Source: "git", Location: "/code/ikno", Content: "chore: bump deps"},
+		}},
+	}
+	configs := []sources.Config{{Type: "git", Path: "/code/ikno"}}
+	where, err := filter.Parse(`not content~"^chore"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := BuildRecap(t.Context(), configs, tr, "today", BuildOptions{Filter: where}, fakeFactory(fakes))
+	if err != nil {
+		t.Fatalf("BuildRecap: %v", err)
+	}
+	if len(result.Entries) != 1 || result.Entries[0].Content != "feat: filters" {
+		t.Errorf("expected only the feature commit, got %+v", result.Entries)
+	}
+	if result.Sources[0].Entries != 2 {
+		t.Errorf("source report should count entries before filtering, got %d", result.Sources[0].Entries)
+	}
+}
@@ -0,0 +1,321 @@
+package recap
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// Comparison is what changed between two periods.
+type Comparison struct {
+	Current  *RecapResult
+	Previous *RecapResult
+
+	Total      Delta
+	Sources    []Delta // by source type
+	Projects   []Delta // by project, with hours
+	Categories []Delta // by category, see Category
+
+	NewProjects     []string // active in the current period only
+	DroppedProjects []string // active in the previous period only
+}
+
+// Delta compares the activity of one source, project or category in both
+// periods. Hours are set for projects and the total only.
+type Delta struct {
+	Name          string
+	Current       int // entries
+	Previous      int
+	CurrentHours  time.Duration
+	PreviousHours time.Duration
+}
+
+// Change returns the difference in entries.
+func (d Delta) Change() int { return d.Current - d.Previous }
+
+// HoursChange returns the difference in estimated hours.
+func (d Delta) HoursChange() time.Duration { return d.CurrentHours - d.PreviousHours }
+
+// Compare computes the deltas between two recaps. Hours come from the
+// results' estimates (see EstimateHours); results without one count no
+// hours.
+func Compare(current, previous *RecapResult) *Comparison {
+	c := &Comparison{
+		Current:  current,
+		Previous: previous,
+		Total: Delta{
+			Name:          "Total",
+			Current:       len(current.Entries),
+			Previous:      len(previous.Entries),
+			CurrentHours:  totalHours(current),
+			PreviousHours: totalHours(previous),
+		},
+	}
+
+	c.Sources = countDeltas(current.Entries, previous.Entries, func(e sources.Entry) string { return e.Source })
+	c.Categories = countDeltas(current.Entries, previous.Entries, Category)
+
+	byProject := make(map[string]*Delta)
+	project := func(name string) *Delta {
+		d, ok := byProject[name]
+		if !ok {
+			d = &Delta{Name: name}
+			byProject[name] = d
+		}
+		return d
+	}
+	if current.Hours != nil {
+		for _, p := range current.Hours.Projects {
+			d := project(p.Project)
+			d.Current, d.CurrentHours = p.Entries, p.Duration
+		}
+	}
+	if previous.Hours != nil {
+		for _, p := range previous.Hours.Projects {
+			d := project(p.Project)
+			d.Previous, d.PreviousHours = p.Entries, p.Duration
+		}
+	}
+	for _, d := range byProject {
+		c.Projects = append(c.Projects, *d)
+		if d.Name == UnassignedProject {
+			continue
+		}
+		switch {
+		case d.Previous == 0 && d.Current > 0:
+			c.NewProjects = append(c.NewProjects, d.Name)
+		case d.Current == 0 && d.Previous > 0:
+			c.DroppedProjects = append(c.DroppedProjects, d.Name)
+		}
+	}
+	slices.SortFunc(c.Projects, func(a, b Delta) int {
+		return cmp.Or(cmp.Compare(b.CurrentHours, a.CurrentHours), cmp.Compare(b.PreviousHours, a.PreviousHours), cmp.Compare(a.Name, b.Name))
+	})
+	slices.Sort(c.NewProjects)
+	slices.Sort(c.DroppedProjects)
+	return c
+}
+
+func totalHours(r *RecapResult) time.Duration {
+	if r.Hours == nil {
+		return 0
+	}
+	return r.Hours.Total
+}
+
+// countDeltas counts the entries of both periods by key, most active in the
+// current period first.
+func countDeltas(current, previous []sources.Entry, key func(sources.Entry) string) []Delta {
+	byKey := make(map[string]*Delta)
+	count := func(entries []sources.Entry, isCurrent bool) {
+		for _, e := range entries {
+			k := key(e)
+			d, ok := byKey[k]
+			if !ok {
+				d = &Delta{Name: k}
+				byKey[k] = d
+			}
+			if isCurrent {
+				d.Current++
+			} else {
+				d.Previous++
+			}
+		}
+	}
+	count(current, true)
+	count(previous, false)
+
+	deltas := make([]Delta, 0, len(byKey))
+	for _, k := range slices.Sorted(maps.Keys(byKey)) {
+		deltas = append(deltas, *byKey[k])
+	}
+	slices.SortStableFunc(deltas, func(a, b Delta) int {
+		return cmp.Or(cmp.Compare(b.Current, a.Current), cmp.Compare(b.Previous, a.Previous))
+	})
+	return deltas
+}
+
+// commitTypePattern matches the type of a conventional commit subject such
+// as "feat(recap): ..." or "fix!: ...".
+var commitTypePattern = regexp.MustCompile(`^([a-zA-Z]+)(\([^)]*\))?!?:`)
+
+// commitTypes are the conventional commit types used as categories.
+var commitTypes = map[string]bool{
+	"feat": true, "fix": true, "docs": true, "refactor": true, "test": true, "perf": true,
+	"chore": true, "build": true, "ci": true, "style": true, "revert": true,
+}
+
+// Category returns the kind of work an entry stands for: the conventional
+// commit type of a commit ("feat", "fix", "docs", ...; "commit" for other
+// messages) or the kind of source for everything else ("notes", "tasks",
+// "ai", "tracked", ...).
+func Category(e sources.Entry) string {
+	switch e.Source {
+	case "git":
+		if m := commitTypePattern.FindStringSubmatch(e.Content); m != nil && commitTypes[strings.ToLower(m[1])] {
+			return strings.ToLower(m[1])
+		}
+		return "commit"
+	case "claude":
+		return "ai"
+	case "obsidian", "markdown", "org", "logseq":
+		return "notes"
+	case "todotxt", "taskwarrior", "git-bug":
+		return "tasks"
+	case "timewarrior", "watson", "toggl", "clockify", "activitywatch":
+		return "tracked"
+	case "editor":
+		return "editing"
+	case "ci":
+		return "ci runs"
+	case "containers":
+		return "deploys"
+	}
+	return e.Source
+}
+
+// RenderCompareForAI writes the comparison as AI input: the delta tables,
+// followed by the recap of each period as rendered by RenderForAI.
+func RenderCompareForAI(w io.Writer, c *Comparison) error {
+	_, _ = fmt.Fprintf(w, "# Period Comparison\n\n")
+	_, _ = fmt.Fprintf(w, "**Current period:** %s\n", periodLabel(c.Current))
+	_, _ = fmt.Fprintf(w, "**Previous period:** %s\n\n", periodLabel(c.Previous))
+	_, _ = fmt.Fprintf(w, "Counts are activities (commits, sessions, notes, ...). Hours are estimated from activity timestamps. Base statements about time on hours, not counts.\n\n")
+
+	_, _ = fmt.Fprintf(w, "**Total:** %d activities (previous %d, %s), %s (previous %s, %s)\n\n",
+		c.Total.Current, c.Total.Previous, FormatChange(c.Total.Change()),
+		FormatHours(c.Total.CurrentHours), FormatHours(c.Total.PreviousHours), FormatHoursChange(c.Total.HoursChange()))
+	if len(c.NewProjects) > 0 {
+		_, _ = fmt.Fprintf(w, "**New projects:** %s\n", strings.Join(c.NewProjects, ", "))
+	}
+	if len(c.DroppedProjects) > 0 {
+		_, _ = fmt.Fprintf(w, "**Dropped projects:** %s\n", strings.Join(c.DroppedProjects, ", "))
+	}
+	_, _ = fmt.Fprintf(w, "\n")
+
+	_, _ = fmt.Fprintf(w, "## Projects\n\n| Project | Hours | Previous | Change | Activities | Previous |\n|---|---|---|---|---|---|\n")
+	for _, d := range c.Projects {
+		_, _ = fmt.Fprintf(w, "| %s | %s | %s | %s | %d | %d |\n", d.Name, FormatHours(d.CurrentHours), FormatHours(d.PreviousHours), FormatHoursChange(d.HoursChange()), d.Current, d.Previous)
+	}
+	for _, section := range []struct {
+		title  string
+		deltas []Delta
+	}{{"Sources", c.Sources}, {"Categories", c.Categories}} {
+		_, _ = fmt.Fprintf(w, "\n## %s\n\n| Name | Activities | Previous | Change |\n|---|---|---|---|\n", section.title)
+		for _, d := range section.deltas {
+			_, _ = fmt.Fprintf(w, "| %s | %d | %d | %s |\n", d.Name, d.Current, d.Previous, FormatChange(d.Change()))
+		}
+	}
+	_, _ = fmt.Fprintf(w, "\n---\n\n")
+
+	for _, period := range []struct {
+		title  string
+		result *RecapResult
+	}{{"Current Period", c.Current}, {"Previous Period", c.Previous}} {
+		_, _ = fmt.Fprintf(w, "# %s: %s\n\n", period.title, periodLabel(period.result))
+		if len(period.result.Entries) == 0 {
+			_, _ = fmt.Fprintf(w, "No activity.\n\n")
+			continue
+		}
+		if err := RenderForAI(w, period.result); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(w, "---\n\n")
+	}
+	return nil
+}
+
+// RenderCompareJSON writes the comparison as structured JSON. Hours are in
+// whole minutes. The activities of both periods are left out; use
+// RenderJSON for those.
+func RenderCompareJSON(w io.Writer, c *Comparison) error {
+	type JSONPeriod struct {
+		Timespec string `json:"timespec"`
+		From     string `json:"from"`
+		To       string `json:"to"`
+	}
+	type JSONDelta struct {
+		Name            string `json:"name"`
+		Current         int    `json:"current"`
+		Previous        int    `json:"previous"`
+		Change          int    `json:"change"`
+		CurrentMinutes  *int   `json:"current_minutes,omitempty"`
+		PreviousMinutes *int   `json:"previous_minutes,omitempty"`
+	}
+	type JSONComparison struct {
+		Current         JSONPeriod  `json:"current"`
+		Previous        JSONPeriod  `json:"previous"`
+		Total           JSONDelta   `json:"total"`
+		Sources         []JSONDelta `json:"sources"`
+		Projects        []JSONDelta `json:"projects"`
+		Categories      []JSONDelta `json:"categories"`
+		NewProjects     []string    `json:"new_projects"`
+		DroppedProjects []string    `json:"dropped_projects"`
+	}
+
+	period := func(r *RecapResult) JSONPeriod {
+		return JSONPeriod{Timespec: r.Timespec, From: r.TimeRange.From.Format("2006-01-02"), To: r.TimeRange.To.Format("2006-01-02")}
+	}
+	delta := func(d Delta, hours bool) JSONDelta {
+		jd := JSONDelta{Name: d.Name, Current: d.Current, Previous: d.Previous, Change: d.Change()}
+		if hours {
+			cur, prev := minutes(d.CurrentHours), minutes(d.PreviousHours)
+			jd.CurrentMinutes, jd.PreviousMinutes = &cur, &prev
+		}
+		return jd
+	}
+	deltas := func(ds []Delta, hours bool) []JSONDelta {
+		out := make([]JSONDelta, len(ds))
+		for i, d := range ds {
+			out[i] = delta(d, hours)
+		}
+		return out
+	}
+
+	report := JSONComparison{
+		Current:         period(c.Current),
+		Previous:        period(c.Previous),
+		Total:           delta(c.Total, true),
+		Sources:         deltas(c.Sources, false),
+		Projects:        deltas(c.Projects, true),
+		Categories:      deltas(c.Categories, false),
+		NewProjects:     append([]string{}, c.NewProjects...),
+		DroppedProjects: append([]string{}, c.DroppedProjects...),
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func periodLabel(r *RecapResult) string {
+	return fmt.Sprintf("%s (%s to %s)", r.Timespec, r.TimeRange.From.Format("2006-01-02"), r.TimeRange.To.Format("2006-01-02"))
+}
+
+// FormatChange renders a count difference with its sign: "+3", "-2", "0".
+func FormatChange(n int) string {
+	if n > 0 {
+		return fmt.Sprintf("+%d", n)
+	}
+	return fmt.Sprint(n)
+}
+
+// FormatHoursChange renders a duration difference with its sign, as
+// "+1h30m", "-45m" or "0m".
+func FormatHoursChange(d time.Duration) string {
+	switch {
+	case minutes(d) > 0:
+		return "+" + FormatHours(d)
+	case minutes(d) < 0:
+		return "-" + FormatHours(-d)
+	}
+	return FormatHours(0)
+}
@@ -0,0 +1,135 @@
+package recap
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/timerange"
+)
+
+func TestCompare(t *testing.T) {
+	week := time.Date(2026, 4, 13, 0, 0, 0, 0, time.Local)
+	commit := func(days, hour int, repo, msg string) sources.Entry {
+		return sources.Entry{Timestamp: week.AddDate(0, 0, days).Add(time.Duration(hour) * time.Hour), Source: "git", Location: "/code/" + repo, Content: msg}
+	}
+	result := func(timespec string, from time.Time, entries ...sources.Entry) *RecapResult {
+		r := &RecapResult{Timespec: timespec, TimeRange: &timerange.TimeRange{From: from, To: from.AddDate(0, 0, 7)}, Entries: entries}
+		r.Hours = EstimateHours(entries, DefaultHoursOptions())
+		return r
+	}
+
+	current := result("thisweek", week,
+		commit(0, 9, "ikno", "feat: compare periods"),
+		commit(0, 10, "ikno", "fix(recap): sort deltas"),
+		commit(1, 9, "shop", "feat: checkout"),
+		sources.Entry{Timestamp: week.Add(15 * time.Hour), Source: "obsidian", Location: "/vault", Content: "Planning"},
+	)
+	previous := result("lastweek", week.AddDate(0, 0, -7),
+		commit(-7, 9, "ikno", "chore: bump deps"),
+		commit(-6, 9, "dotfiles", "update zshrc"),
+	)
+
+	c := Compare(current, previous)
+
+	if c.Total.Current != 4 || c.Total.Previous != 2 || c.Total.Change() != 2 {
+		t.Errorf("unexpected total: %+v", c.Total)
+	}
+	if c.Total.CurrentHours != current.Hours.Total || c.Total.PreviousHours != previous.Hours.Total {
+		t.Errorf("total hours not taken from the estimates: %+v", c.Total)
+	}
+	if !slices.Equal(c.NewProjects, []string{"shop"}) || !slices.Equal(c.DroppedProjects, []string{"dotfiles"}) {
+		t.Errorf("new %v, dropped %v", c.NewProjects, c.DroppedProjects)
+	}
+
+	byName := func(ds []Delta) map[string]Delta {
+		m := make(map[string]Delta)
+		for _, d := range ds {
+			m[d.Name] = d
+		}
+		return m
+	}
+	if ikno := byName(c.Projects)["ikno"]; ikno.Current != 2 || ikno.Previous != 1 || ikno.HoursChange() <= 0 {
+		t.Errorf("unexpected ikno delta: %+v", ikno)
+	}
+	if c.Projects[0].Name != "ikno" {
+		t.Errorf("projects should be sorted by current hours, got %+v", c.Projects)
+	}
+	if git := byName(c.Sources)["git"]; git.Current != 3 || git.Previous != 2 {
+		t.Errorf("unexpected git delta: %+v", git)
+	}
+	categories := byName(c.Categories)
+	for name, want := range map[string][2]int{"feat": {2, 0}, "fix": {1, 0}, "chore": {0, 1}, "commit": {0, 1}, "notes": {1, 0}} {
+		if d := categories[name]; d.Current != want[0] || d.Previous != want[1] {
+			t.Errorf("category %s = %d/%d, want %d/%d", name, d.Current, d.Previous, want[0], want[1])
+		}
+	}
+	if c.Categories[0].Name != "feat" {
+		t.Errorf("categories should be sorted by current count, got %+v", c.Categories)
+	}
+
+	var buf strings.Builder
+	if err := RenderCompareForAI(&buf, c); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"**New projects:** shop", "**Dropped projects:** dotfiles", "| feat | 2 | 0 | +2 |", "# Current Period: thisweek", "# Previous Period: lastweek", "chore: bump deps"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("AI input missing %q", want)
+		}
+	}
+
+	buf.Reset()
+	if err := RenderCompareJSON(&buf, c); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Total struct {
+			Change         int  `json:"change"`
+			CurrentMinutes *int `json:"current_minutes"`
+		} `json:"total"`
+		Sources []struct {
+			CurrentMinutes *int `json:"current_minutes"`
+		} `json:"sources"`
+		DroppedProjects []string `json:"dropped_projects"`
+	}
+	if err := json.Unmarshal([]byte(buf.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Total.Change != 2 || got.Total.CurrentMinutes == nil || got.Sources[0].CurrentMinutes != nil || len(got.DroppedProjects) != 1 {
+		t.Errorf("unexpected JSON: %s", buf.String())
+	}
+}
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		entry sources.Entry
+		want  string
+	}{
+		{sources.Entry{Source: "git", Content: "feat(recap): compare"}, "feat"},
+		{sources.Entry{Source: "git", Content: "Fix!: breaking fix"}, "fix"},
+		{sources.Entry{Source: "git", Content: "wip: stuff"}, "commit"},
+		{sources.Entry{Source: "git", Content: "Merge branch 'main'"}, "commit"},
+		{sources.Entry{Source: "claude"}, "ai"},
+		{sources.Entry{Source: "logseq"}, "notes"},
+		{sources.Entry{Source: "taskwarrior"}, "tasks"},
+		{sources.Entry{Source: "toggl"}, "tracked"},
+		{sources.Entry{Source: "jsonl"}, "jsonl"},
+	}
+	for _, tt := range tests {
+		if got := Category(tt.entry); got != tt.want {
+			t.Errorf("Category(%s %q) = %q, want %q", tt.entry.Source, tt.entry.Content, got, tt.want)
+		}
+	}
+}
+
+func TestFormatChange(t *testing.T) {
+	if got := FormatChange(3) + " " + FormatChange(-2) + " " + FormatChange(0); got != "+3 -2 0" {
+		t.Errorf("FormatChange = %q", got)
+	}
+	if got := FormatHoursChange(90*time.Minute) + " " + FormatHoursChange(-45*time.Minute) + " " + FormatHoursChange(10*time.Second); got != "+1h30m -45m 0m" {
+		t.Errorf("FormatHoursChange = %q", got)
+	}
+}
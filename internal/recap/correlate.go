@@ -0,0 +1,357 @@
+package recap
+
+import (
+	"cmp"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/projects"
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// proximityWindow is how close in time an entry without any project,
+// branch or issue must be to a linked entry to join its work item.
+const proximityWindow = 30 * time.Minute
+
+// issueKeyPattern matches tracker keys such as PROJ-123.
+var issueKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9]{1,9}-[1-9][0-9]*\b`)
+
+// notIssueKeys are prefixes that look like issue keys but name standards,
+// encodings and versions ("UTF-8", "SHA-256").
+var notIssueKeys = map[string]bool{
+	"AES": true, "ARM": true, "CVE": true, "GPT": true, "HTTP": true, "ISO": true,
+	"MD": true, "PEP": true, "RFC": true, "RSA": true, "SHA": true, "SSL": true,
+	"TLS": true, "UTF": true,
+}
+
+// defaultBranches are too common to link anything.
+var defaultBranches = map[string]bool{"main": true, "master": true, "develop": true, "trunk": true, "HEAD": true}
+
+// WorkItem is a piece of work seen across sources: the commits in a repo,
+// the AI sessions run in it, the notes that mention it and the time
+// tracked against it.
+type WorkItem struct {
+	Title    string
+	Projects []string
+	Branches []string
+	Issues   []string
+	Sources  []string
+	Start    time.Time
+	End      time.Time
+	Entries  []sources.Entry // sorted by time
+
+	indexes []int // positions of Entries in the correlated slice
+}
+
+// Correlate links entries into work items. Entries are linked when they
+// share a project (repository, working directory or project metadata), a
+// feature branch or an issue key. A project name, such as a time tracker's,
+// matches the directory of that name if there is only one. Notes that
+// mention a known project by name join it; entries without any link join
+// the work item active within proximityWindow. Whatever is left is grouped
+// by location. Work items are ordered by their first entry.
+func Correlate(entries []sources.Entry) []WorkItem {
+	byName := projectKeys(entries)
+	keys := make([][]string, len(entries))
+	for i, e := range entries {
+		keys[i] = linkKeys(e, byName)
+	}
+	mentionProjects(entries, keys, byName)
+
+	// Union entries sharing a key.
+	uf := newUnionFind(len(entries))
+	owner := make(map[string]int)
+	for i, ks := range keys {
+		for _, k := range ks {
+			if j, ok := owner[k]; ok {
+				uf.union(i, j)
+			} else {
+				owner[k] = i
+			}
+		}
+	}
+
+	// Attach unlinked entries to the nearest linked entry in time, or
+	// group them by location.
+	linked := make([]int, 0, len(entries))
+	for i := range entries {
+		if len(keys[i]) > 0 {
+			linked = append(linked, i)
+		}
+	}
+	slices.SortStableFunc(linked, func(a, b int) int { return entries[a].Timestamp.Compare(entries[b].Timestamp) })
+	byLocation := make(map[string]int)
+	for i, e := range entries {
+		if len(keys[i]) > 0 {
+			continue
+		}
+		if j, ok := nearest(entries, linked, e.Timestamp); ok {
+			uf.union(i, j)
+			continue
+		}
+		if j, ok := byLocation[e.Source+"\x00"+e.Location]; ok {
+			uf.union(i, j)
+		} else {
+			byLocation[e.Source+"\x00"+e.Location] = i
+		}
+	}
+
+	groups := make(map[int][]int)
+	for i := range entries {
+		root := uf.find(i)
+		groups[root] = append(groups[root], i)
+	}
+
+	items := make([]WorkItem, 0, len(groups))
+	for _, members := range groups {
+		items = append(items, newWorkItem(entries, keys, members))
+	}
+	slices.SortFunc(items, func(a, b WorkItem) int {
+		return cmp.Or(a.Start.Compare(b.Start), cmp.Compare(a.Title, b.Title))
+	})
+	return items
+}
+
+// linkKeys returns the keys an entry can be linked by: "project:<key>",
+// "branch:<name>" and "issue:<KEY>". byName is the result of projectKeys.
+func linkKeys(e sources.Entry, byName map[string]string) []string {
+	var keys []string
+	if key, name := projectOf(e); key != "" {
+		if key == strings.ToLower(name) {
+			key = cmp.Or(byName[key], key)
+		}
+		keys = append(keys, "project:"+key)
+	}
+	branch := branchOf(e)
+	if branch != "" && !defaultBranches[branch] {
+		keys = append(keys, "branch:"+branch)
+	}
+	for _, issue := range issueKeys(e.Content, branch, e.Metadata["title"]) {
+		keys = append(keys, "issue:"+issue)
+	}
+	return keys
+}
+
+// projectOf returns the project of an entry: key identifies it for
+// linking, name is its display name. Both are "" when the source does not
+// know one. Projects taken from a directory are keyed by its full path, so
+// repositories that share a directory name stay apart. A project mapped
+// from config wins and keeps its case; other names are lowercased.
+func projectOf(e sources.Entry) (key, name string) {
+	if mapped := e.Metadata[projects.KeyProject]; mapped != "" {
+		return strings.ToLower(mapped), mapped
+	}
+	var dir string
+	switch e.Source {
+	case "git", "ci", "git-bug":
+		dir = e.Location
+	case "claude":
+		dir = e.Metadata["cwd"]
+		if dir == "" {
+			name = e.Metadata["project_name"]
+		}
+	case "editor":
+		dir = e.Metadata["project_root"]
+	default:
+		// Time trackers, task lists and mapped records carry a project
+		// name; ignore values that are paths.
+		if p := e.Metadata["project"]; !strings.ContainsAny(p, `/\`) {
+			name = p
+		}
+	}
+	if dir != "" {
+		dir = filepath.Clean(dir)
+		name = filepath.Base(dir)
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == "." || name == string(filepath.Separator) {
+		return "", ""
+	}
+	return cmp.Or(dir, name), name
+}
+
+func branchOf(e sources.Entry) string {
+	return cmp.Or(e.Metadata["git_branch"], e.Metadata["branch"])
+}
+
+// issueKeys extracts distinct issue keys from the given texts.
+func issueKeys(texts ...string) []string {
+	var keys []string
+	for _, text := range texts {
+		for _, key := range issueKeyPattern.FindAllString(text, -1) {
+			prefix, _, _ := strings.Cut(key, "-")
+			if !notIssueKeys[prefix] && !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// projectKeys maps lowercased project names to the key of the project
+// they stand for: the directory of that name, else the name itself. A name
+// shared by several directories, such as two repositories called api, maps
+// to "".
+func projectKeys(entries []sources.Entry) map[string]string {
+	byName := make(map[string]string)
+	for _, e := range entries {
+		key, name := projectOf(e)
+		if key == "" {
+			continue
+		}
+		name = strings.ToLower(name)
+		prev, seen := byName[name]
+		switch {
+		case !seen || prev == name:
+			byName[name] = key
+		case key != name && prev != key:
+			byName[name] = ""
+		}
+	}
+	return byName
+}
+
+// mentionProjects links unkeyed entries, typically notes, to the project
+// they mention by name in their content or path. Names that projectKeys
+// could not resolve are not matched.
+func mentionProjects(entries []sources.Entry, keys [][]string, byName map[string]string) {
+	var patterns []*regexp.Regexp
+	var mentionKeys []string
+	for _, name := range slices.Sorted(maps.Keys(byName)) {
+		if byName[name] == "" || len(name) < 3 {
+			continue
+		}
+		mentionKeys = append(mentionKeys, "project:"+byName[name])
+		patterns = append(patterns, regexp.MustCompile(`(?i)(^|[^\pL\pN_-])`+regexp.QuoteMeta(name)+`($|[^\pL\pN_-])`))
+	}
+
+	for i, e := range entries {
+		if len(keys[i]) > 0 {
+			continue
+		}
+		// A note mentioning several projects would merge them; leave it to
+		// time proximity instead.
+		text := e.Content + "\n" + e.Metadata["path"]
+		var mentioned []string
+		for j, re := range patterns {
+			if re.MatchString(text) {
+				mentioned = append(mentioned, mentionKeys[j])
+			}
+		}
+		if len(mentioned) == 1 {
+			keys[i] = mentioned
+		}
+	}
+}
+
+// nearest returns the linked entry closest to t within proximityWindow, the
+// first one of equally close entries. linked is sorted by time, entries of
+// the same time by index.
+func nearest(entries []sources.Entry, linked []int, t time.Time) (int, bool) {
+	// firstAt returns the position of the first linked entry at or after ts.
+	firstAt := func(ts time.Time) int {
+		pos, _ := slices.BinarySearchFunc(linked, ts, func(i int, ts time.Time) int {
+			return entries[i].Timestamp.Compare(ts)
+		})
+		return pos
+	}
+
+	best, bestDist := -1, proximityWindow+1
+	consider := func(i int) {
+		d := entries[i].Timestamp.Sub(t).Abs()
+		if d < bestDist || (d == bestDist && i < best) {
+			best, bestDist = i, d
+		}
+	}
+	pos := firstAt(t)
+	if pos < len(linked) {
+		consider(linked[pos])
+	}
+	if pos > 0 {
+		consider(linked[firstAt(entries[linked[pos-1]].Timestamp)])
+	}
+	return best, best >= 0
+}
+
+func newWorkItem(entries []sources.Entry, keys [][]string, members []int) WorkItem {
+	var item WorkItem
+	projects := make(map[string]int)
+	for _, i := range members {
+		e := entries[i]
+		item.Entries = append(item.Entries, e)
+		item.indexes = append(item.indexes, i)
+		if !slices.Contains(item.Sources, e.Source) {
+			item.Sources = append(item.Sources, e.Source)
+		}
+		if _, name := projectOf(e); name != "" {
+			projects[name]++
+		}
+		for _, k := range keys[i] {
+			kind, value, _ := strings.Cut(k, ":")
+			switch kind {
+			case "branch":
+				if !slices.Contains(item.Branches, value) {
+					item.Branches = append(item.Branches, value)
+				}
+			case "issue":
+				if !slices.Contains(item.Issues, value) {
+					item.Issues = append(item.Issues, value)
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(item.indexes, func(a, b int) int { return entries[a].Timestamp.Compare(entries[b].Timestamp) })
+	for n, i := range item.indexes {
+		item.Entries[n] = entries[i]
+	}
+	item.Start = item.Entries[0].Timestamp
+	item.End = item.Entries[len(item.Entries)-1].Timestamp
+
+	// Most active project first.
+	item.Projects = slices.SortedFunc(maps.Keys(projects), func(a, b string) int {
+		return cmp.Or(cmp.Compare(projects[b], projects[a]), cmp.Compare(a, b))
+	})
+	slices.Sort(item.Sources)
+	slices.Sort(item.Branches)
+	slices.Sort(item.Issues)
+
+	switch {
+	case len(item.Projects) > 0:
+		item.Title = item.Projects[0]
+	case len(item.Issues) > 0:
+		item.Title = item.Issues[0]
+	case len(item.Branches) > 0:
+		item.Title = item.Branches[0]
+	default:
+		item.Title = GroupByRepo(item.Entries)[0].Name
+	}
+	return item
+}
+
+// unionFind is a disjoint-set forest over entry indexes.
+type unionFind []int
+
+func newUnionFind(n int) unionFind {
+	uf := make(unionFind, n)
+	for i := range uf {
+		uf[i] = i
+	}
+	return uf
+}
+
+func (uf unionFind) find(i int) int {
+	for uf[i] != i {
+		uf[i] = uf[uf[i]]
+		i = uf[i]
+	}
+	return i
+}
+
+func (uf unionFind) union(a, b int) {
+	uf[uf.find(a)] = uf.find(b)
+}
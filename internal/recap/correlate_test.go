@@ -0,0 +1,161 @@
+package recap
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+func TestCorrelate(t *testing.T) {
+	day := time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+
+	entries := []sources.Entry{
+		{Timestamp: at(9, 0), Source: "claude", Location: "/home/u/.claude/projects",
+			Content:  "[ikno] add correlation -- 12 turns, 40 min",
+			Metadata: map[string]string{"cwd": "/home/u/code/ikno", "git_branch": "feat/correlate", "project_name": "ikno"}},
+		{Timestamp: at(10, 15), Source: "git", Location: "/home/u/code/ikno",
+			Content: "feat: correlate entries into work items"},
+		{Timestamp: at(11, 0), Source: "obsidian", Location: "/home/u/vault",
+			Content:  "Design notes on ikno correlation",
+			Metadata: map[string]string{"path": "Projects/recap.md"}},
+		// Linked to the ikno repository by the project name.
+		{Timestamp: at(12, 0), Source: "timewarrior", Location: "/home/u/.timewarrior",
+			Content:  "ikno (45m)",
+			Metadata: map[string]string{"project": "ikno"}},
+		// Linked to ikno through the branch only.
+		{Timestamp: at(13, 0), Source: "ci", Location: "/home/u/code/ikno-mirror",
+			Content:  "[ikno-mirror] CI #4 on feat/correlate: passed after 3m",
+			Metadata: map[string]string{"branch": "feat/correlate"}},
+		// Linked to each other through the issue key, not the project.
+		{Timestamp: at(14, 0), Source: "git", Location: "/home/u/code/billing",
+			Content: "fix(PAY-42): round totals per line"},
+		{Timestamp: at(16, 0), Source: "timewarrior", Location: "/home/u/.timewarrior",
+			Content:  "Review PAY-42 with finance (1h)",
+			Metadata: map[string]string{"project": "finance"}},
+		// No link, but within 30 minutes of the billing commit.
+		{Timestamp: at(14, 20), Source: "shell", Location: "/home/u/.zsh_history",
+			Content: "psql -c 'select * from invoices'"},
+		// No link and nothing nearby.
+		{Timestamp: at(20, 0), Source: "obsidian", Location: "/home/u/vault",
+			Content: "Grocery list"},
+		{Timestamp: at(21, 0), Source: "obsidian", Location: "/home/u/vault",
+			Content: "Book notes, UTF-8 and SHA-256 trivia"},
+	}
+
+	items := Correlate(entries)
+
+	type summary struct {
+		title   string
+		count   int
+		sources []string
+	}
+	var got []summary
+	for _, item := range items {
+		got = append(got, summary{item.Title, len(item.Entries), item.Sources})
+	}
+	want := []summary{
+		{"ikno", 5, []string{"ci", "claude", "git", "obsidian", "timewarrior"}},
+		{"billing", 3, []string{"git", "shell", "timewarrior"}},
+		{"vault", 2, []string{"obsidian"}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d work items %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i].title != want[i].title || got[i].count != want[i].count || !slices.Equal(got[i].sources, want[i].sources) {
+			t.Errorf("item %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	ikno := items[0]
+	if !slices.Equal(ikno.Branches, []string{"feat/correlate"}) {
+		t.Errorf("branches = %v", ikno.Branches)
+	}
+	if !ikno.Start.Equal(at(9, 0)) || !ikno.End.Equal(at(13, 0)) {
+		t.Errorf("span = %s - %s", ikno.Start, ikno.End)
+	}
+	if !slices.IsSortedFunc(ikno.Entries, func(a, b sources.Entry) int { return a.Timestamp.Compare(b.Timestamp) }) {
+		t.Error("entries should be sorted by time")
+	}
+
+	billing := items[1]
+	if !slices.Equal(billing.Issues, []string{"PAY-42"}) || !slices.Equal(billing.Projects, []string{"billing", "finance"}) {
+		t.Errorf("billing item = %+v", billing)
+	}
+	if len(items[2].Issues) != 0 {
+		t.Errorf("UTF-8 and SHA-256 are not issue keys: %v", items[2].Issues)
+	}
+}
+
+func TestCorrelate_AmbiguousMention(t *testing.T) {
+	now := time.Date(2026, 4, 7, 12, 0, 0, 0, time.UTC)
+	entries := []sources.Entry{
+		{Timestamp: now, Source: "git", Location: "/code/api", Content: "feat: pagination"},
+		{Timestamp: now, Source: "git", Location: "/code/web", Content: "feat: pagination ui"},
+		{Timestamp: now.Add(3 * time.Hour), Source: "markdown", Location: "/notes", Content: "Sync api and web releases"},
+	}
+
+	items := Correlate(entries)
+	if len(items) != 3 {
+		t.Fatalf("a note mentioning two projects must not merge them, got %d items", len(items))
+	}
+}
+
+func TestCorrelate_SameDirectoryName(t *testing.T) {
+	now := time.Date(2026, 4, 7, 12, 0, 0, 0, time.UTC)
+	entries := []sources.Entry{
+		{Timestamp: now, Source: "git", Location: "/code/work/api", Content: "feat: pagination"},
+		{Timestamp: now.Add(time.Hour), Source: "git", Location: "/code/oss/api", Content: "fix: typo"},
+		{Timestamp: now.Add(2 * time.Hour), Source: "claude", Location: "/home/u/.claude",
+			Content: "[api] review", Metadata: map[string]string{"cwd": "/code/oss/api/"}},
+		// Could be either repository.
+		{Timestamp: now.Add(3 * time.Hour), Source: "timewarrior", Location: "/home/u/.timewarrior",
+			Content: "api (1h)", Metadata: map[string]string{"project": "API"}},
+	}
+
+	items := Correlate(entries)
+	if len(items) != 3 {
+		t.Fatalf("repositories in different directories must not merge, got %d items", len(items))
+	}
+	for _, item := range items {
+		if item.Title != "api" {
+			t.Errorf("title = %q, want the directory name", item.Title)
+		}
+	}
+	if len(items[1].Entries) != 2 || items[1].Entries[0].Location != "/code/oss/api" {
+		t.Errorf("session should join the repository it ran in: %+v", items[1].Entries)
+	}
+}
+
+func TestNearest(t *testing.T) {
+	day := time.Date(2026, 4, 7, 9, 0, 0, 0, time.UTC)
+	at := func(m int) sources.Entry { return sources.Entry{Timestamp: day.Add(time.Duration(m) * time.Minute)} }
+	// Entries 3, 4 and 5 share a time; 1 and 3 are equally far from +20m.
+	entries := []sources.Entry{at(0), at(10), at(100), at(30), at(30), at(30), at(200)}
+	// Linked entries in index order, sorted by time as in Correlate.
+	linked := []int{1, 3, 4, 5, 6}
+	slices.SortStableFunc(linked, func(a, b int) int { return entries[a].Timestamp.Compare(entries[b].Timestamp) })
+
+	tests := []struct {
+		minute int
+		want   int
+		ok     bool
+	}{
+		{0, 1, true},     // 10m to entry 1
+		{20, 1, true},    // 10m to entries 1 and 3: the first wins
+		{25, 3, true},    // the first of the entries at 30
+		{45, 3, true},    // after the last close entry
+		{70, -1, false},  // 40m to 30 and 130m to 200: outside the window
+		{185, 6, true},   // before the last entry
+		{300, -1, false}, // after everything
+	}
+	for _, tt := range tests {
+		got, ok := nearest(entries, linked, day.Add(time.Duration(tt.minute)*time.Minute))
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("nearest at +%dm = %d, %v, want %d, %v", tt.minute, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -66,6 +66,36 @@ func SourceLabel(sourceType string) string {
 		return "Markdown Notes"
 	case "claude":
 		return "Claude Sessions"
+	case "org":
+		return "Org Files"
+	case "logseq":
+		return "Logseq Graph"
+	case "todotxt":
+		return "todo.txt"
+	case "taskwarrior":
+		return "Taskwarrior"
+	case "git-bug":
+		return "git-bug Issues"
+	case "editor":
+		return "Editor History"
+	case "ci":
+		return "CI Runs"
+	case "containers":
+		return "Containers & Kubernetes"
+	case "timewarrior":
+		return "Timewarrior"
+	case "watson":
+		return "Watson"
+	case "activitywatch":
+		return "ActivityWatch"
+	case "toggl":
+		return "Toggl Track"
+	case "clockify":
+		return "Clockify"
+	case "jsonl":
+		return "JSONL Records"
+	case "csv":
+		return "CSV Records"
 	default:
 		if sourceType == "" {
 			return ""
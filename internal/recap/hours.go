@@ -0,0 +1,269 @@
+package recap
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/sources/timetrack"
+)
+
+// UnassignedProject is the project of entries that no project, branch,
+// issue or nearby activity ties to a project.
+const UnassignedProject = "(unassigned)"
+
+// HoursOptions controls how activity is turned into working time.
+type HoursOptions struct {
+	// IdleGap is the longest pause between two entries that still counts
+	// as one working session.
+	IdleGap time.Duration
+	// LeadIn is the time assumed to precede the first entry of a session,
+	// per source type: a commit is the end of work, not its start.
+	LeadIn map[string]time.Duration
+	// DefaultLeadIn applies to source types missing from LeadIn.
+	DefaultLeadIn time.Duration
+}
+
+// DefaultHoursOptions returns the built-in estimation settings. Sources that
+// record their own duration (AI sessions, editor bursts, time trackers) need
+// no lead-in.
+func DefaultHoursOptions() HoursOptions {
+	return HoursOptions{
+		IdleGap: 30 * time.Minute,
+		LeadIn: map[string]time.Duration{
+			"git":           20 * time.Minute,
+			"claude":        0,
+			"editor":        0,
+			"ci":            0,
+			"timewarrior":   0,
+			"watson":        0,
+			"activitywatch": 0,
+			"toggl":         0,
+			"clockify":      0,
+		},
+		DefaultLeadIn: 10 * time.Minute,
+	}
+}
+
+func (o HoursOptions) leadIn(source string) time.Duration {
+	if d, ok := o.LeadIn[source]; ok {
+		return d
+	}
+	return o.DefaultLeadIn
+}
+
+// HoursEstimate is the estimated active working time of a period.
+type HoursEstimate struct {
+	Total    time.Duration
+	Days     []DayHours     // oldest first
+	Projects []ProjectHours // over the whole period, longest first
+}
+
+// DayHours is the estimated working time of one calendar day. Total counts
+// time spent on several projects at once only once, so it can be less than
+// the sum of the projects.
+type DayHours struct {
+	Date     time.Time // local midnight
+	Total    time.Duration
+	Projects []ProjectHours // longest first
+}
+
+// ProjectHours is the estimated working time on a project.
+type ProjectHours struct {
+	Project  string
+	Duration time.Duration
+	Sessions int
+	Entries  int
+	Start    time.Time // start of the first session, including lead-in
+	End      time.Time // end of the last session
+
+	// Activity holds the entries behind a project-day, oldest first. It is
+	// empty for period totals.
+	Activity []sources.Entry
+}
+
+// span is the stretch of time an entry stands for.
+type span struct {
+	start, end time.Time
+	leadIn     time.Duration
+	entry      sources.Entry
+}
+
+// EstimateHours estimates active working time per day and project from
+// entry timestamps. Entries of a project are grouped into sessions: an entry
+// within IdleGap of the previous one extends the session, otherwise a new
+// session starts with the lead-in of its first entry's source. Entries with
+// a recorded duration cover it; open tasks, dated by when they are due, do
+// not count. Projects come from the project mapping and
+// the work item correlation (see Correlate).
+func EstimateHours(entries []sources.Entry, opts HoursOptions) *HoursEstimate {
+	type dayProject struct {
+		day     time.Time
+		project string
+	}
+	spans := make(map[dayProject][]span)
+	days := make(map[time.Time][]span)
+
+	forEachProject(entries, func(project string, e sources.Entry) {
+		if !workedAt(e) {
+			return
+		}
+		s := entrySpan(e, opts)
+		s.entry = e
+		y, m, d := e.Timestamp.Local().Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+		key := dayProject{day, project}
+		spans[key] = append(spans[key], s)
+		days[day] = append(days[day], s)
+	})
+
+	estimate := &HoursEstimate{}
+	totals := make(map[string]*ProjectHours)
+	byDay := make(map[time.Time]*DayHours)
+	for key, ss := range spans {
+		ph := sessionize(ss, opts.IdleGap)
+		ph.Project = key.project
+		for _, s := range ss {
+			ph.Activity = append(ph.Activity, s.entry)
+		}
+		slices.SortStableFunc(ph.Activity, func(a, b sources.Entry) int { return a.Timestamp.Compare(b.Timestamp) })
+
+		dh, ok := byDay[key.day]
+		if !ok {
+			dh = &DayHours{Date: key.day}
+			byDay[key.day] = dh
+		}
+		dh.Projects = append(dh.Projects, ph)
+
+		t, ok := totals[key.project]
+		if !ok {
+			t = &ProjectHours{Project: key.project, Start: ph.Start, End: ph.End}
+			totals[key.project] = t
+		}
+		t.Duration += ph.Duration
+		t.Sessions += ph.Sessions
+		t.Entries += ph.Entries
+		if ph.Start.Before(t.Start) {
+			t.Start = ph.Start
+		}
+		if ph.End.After(t.End) {
+			t.End = ph.End
+		}
+	}
+
+	for _, day := range slices.SortedFunc(maps.Keys(byDay), time.Time.Compare) {
+		dh := byDay[day]
+		dh.Total = sessionize(days[day], opts.IdleGap).Duration
+		sortProjectHours(dh.Projects)
+		estimate.Days = append(estimate.Days, *dh)
+		estimate.Total += dh.Total
+	}
+	for _, t := range totals {
+		estimate.Projects = append(estimate.Projects, *t)
+	}
+	sortProjectHours(estimate.Projects)
+	return estimate
+}
+
+// forEachProject calls fn with each entry and the project it counts
+// toward: its own project, else the first project of its work item, else
+// UnassignedProject.
+func forEachProject(entries []sources.Entry, fn func(project string, e sources.Entry)) {
+	for _, item := range Correlate(entries) {
+		for _, e := range item.Entries {
+			_, project := projectOf(e)
+			if project == "" && len(item.Projects) > 0 {
+				project = item.Projects[0]
+			}
+			fn(cmp.Or(project, UnassignedProject), e)
+		}
+	}
+}
+
+// workedAt reports whether the timestamp of an entry is a time of activity.
+// Open tasks carry their due date instead, which may even lie ahead, and
+// approximate times only bound when something happened.
+func workedAt(e sources.Entry) bool {
+	return e.Metadata["task_status"] != "open" && e.Metadata["time_approximate"] != "true"
+}
+
+// entrySpan returns the time an entry covers. Recorded durations are used
+// as is, except for CI runs, whose duration is machine time.
+func entrySpan(e sources.Entry, opts HoursOptions) span {
+	s := span{start: e.Timestamp, end: e.Timestamp, leadIn: opts.leadIn(e.Source)}
+	if e.Source == "ci" {
+		return s
+	}
+	if end, err := time.Parse(time.RFC3339, e.Metadata["end"]); err == nil && end.After(e.Timestamp) {
+		s.end = end
+	} else if minutes, err := strconv.Atoi(e.Metadata["duration_minutes"]); err == nil && minutes > 0 {
+		s.end = e.Timestamp.Add(time.Duration(minutes) * time.Minute)
+	}
+	return s
+}
+
+// sessionize merges spans into sessions and returns their total length,
+// count and extent. Each session starts with the lead-in of its first span.
+func sessionize(spans []span, idleGap time.Duration) ProjectHours {
+	if len(spans) == 0 {
+		return ProjectHours{}
+	}
+	spans = slices.Clone(spans)
+	slices.SortFunc(spans, func(a, b span) int {
+		return cmp.Or(a.start.Compare(b.start), cmp.Compare(b.leadIn, a.leadIn))
+	})
+
+	ph := ProjectHours{Sessions: 1, Entries: len(spans)}
+	start, end := spans[0].start.Add(-spans[0].leadIn), spans[0].end
+	ph.Start = start
+	for _, s := range spans[1:] {
+		if s.start.Sub(end) > idleGap {
+			ph.Duration += end.Sub(start)
+			ph.Sessions++
+			start, end = s.start.Add(-s.leadIn), s.end
+			continue
+		}
+		if s.end.After(end) {
+			end = s.end
+		}
+	}
+	ph.Duration += end.Sub(start)
+	ph.End = end
+	return ph
+}
+
+func sortProjectHours(ph []ProjectHours) {
+	slices.SortFunc(ph, func(a, b ProjectHours) int {
+		return cmp.Or(cmp.Compare(b.Duration, a.Duration), cmp.Compare(a.Project, b.Project))
+	})
+}
+
+// RenderHoursForAI writes the estimate as a markdown section for AI input.
+func RenderHoursForAI(w io.Writer, estimate *HoursEstimate) {
+	_, _ = fmt.Fprintf(w, "## Estimated Working Time\n\n")
+	_, _ = fmt.Fprintf(w, "Estimated from activity timestamps (sessions split at idle gaps, with lead-in time before the first entry). Use these hours, not entry counts, for time distribution.\n\n")
+	_, _ = fmt.Fprintf(w, "**Total:** %s\n\n", FormatHours(estimate.Total))
+	for _, p := range estimate.Projects {
+		_, _ = fmt.Fprintf(w, "- %s: %s (%d sessions)\n", p.Project, FormatHours(p.Duration), p.Sessions)
+	}
+	_, _ = fmt.Fprintf(w, "\n")
+	for _, d := range estimate.Days {
+		parts := make([]string, len(d.Projects))
+		for i, p := range d.Projects {
+			parts[i] = p.Project + " " + FormatHours(p.Duration)
+		}
+		_, _ = fmt.Fprintf(w, "- %s: %s (%s)\n", d.Date.Format("2006-01-02 Mon"), FormatHours(d.Total), strings.Join(parts, ", "))
+	}
+	_, _ = fmt.Fprintf(w, "\n---\n\n")
+}
+
+// FormatHours renders a duration rounded to minutes as "45m" or "2h05m".
+func FormatHours(d time.Duration) string {
+	return timetrack.FormatMinutes(minutes(d))
+}
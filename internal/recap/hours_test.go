@@ -0,0 +1,111 @@
+package recap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+func TestEstimateHours(t *testing.T) {
+	day := time.Date(2026, 4, 7, 0, 0, 0, 0, time.Local)
+	at := func(d, h, m int) time.Time {
+		return day.AddDate(0, 0, d).Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	commit := func(ts time.Time, repo string) sources.Entry {
+		return sources.Entry{Timestamp: ts, Source: "git", Location: "/code/" + repo, Content: "commit"}
+	}
+
+	entries := []sources.Entry{
+		// ikno: 20m lead-in + 9:00-9:40 = 1h.
+		commit(at(0, 9, 0), "ikno"),
+		commit(at(0, 9, 25), "ikno"),
+		commit(at(0, 9, 40), "ikno"),
+		commit(at(0, 11, 40), "ikno"),
+		// After a break, a 45 minute Claude session in ikno starts a new
+		// session; the commit at 11:40 falls inside it.
+		{Timestamp: at(0, 11, 0), Source: "claude", Location: "/home/u/.claude",
+			Metadata: map[string]string{"cwd": "/code/ikno", "duration_minutes": "45"}},
+		// Tracked time on another project in parallel.
+		{Timestamp: at(0, 9, 0), Source: "timewarrior", Location: "/home/u/.timewarrior",
+			Metadata: map[string]string{"project": "acme", "start": at(0, 9, 0).Format(time.RFC3339), "end": at(0, 10, 30).Format(time.RFC3339), "duration_minutes": "90"}},
+		// CI durations are machine time.
+		{Timestamp: at(1, 14, 0), Source: "ci", Location: "/code/ikno",
+			Metadata: map[string]string{"duration_minutes": "25"}},
+		commit(at(1, 14, 10), "ikno"),
+	}
+
+	got := EstimateHours(entries, DefaultHoursOptions())
+
+	if len(got.Days) != 2 {
+		t.Fatalf("expected 2 days, got %+v", got.Days)
+	}
+	first := got.Days[0]
+	hours := map[string]time.Duration{}
+	sessions := map[string]int{}
+	for _, p := range first.Projects {
+		hours[p.Project] = p.Duration
+		sessions[p.Project] = p.Sessions
+	}
+	// ikno: 8:40-9:40 (1h) + 11:00-11:45 (45m; the 11:40 commit is inside).
+	if hours["ikno"] != 105*time.Minute || sessions["ikno"] != 2 {
+		t.Errorf("ikno = %s in %d sessions, want 1h45m in 2", hours["ikno"], sessions["ikno"])
+	}
+	if hours["acme"] != 90*time.Minute {
+		t.Errorf("acme = %s, want 1h30m", hours["acme"])
+	}
+	if first.Projects[0].Project != "ikno" {
+		t.Errorf("projects should be sorted longest first: %+v", first.Projects)
+	}
+	// Day total counts parallel work once, and the 30m pause after the
+	// tracked time is not idle: 8:40-11:45.
+	if first.Total != 185*time.Minute {
+		t.Errorf("day total = %s, want 3h05m", first.Total)
+	}
+
+	// Day two: CI at 14:00 with no lead-in, commit at 14:10 joins it.
+	if got.Days[1].Total != 10*time.Minute {
+		t.Errorf("second day = %s, want 10m", got.Days[1].Total)
+	}
+	if got.Total != first.Total+got.Days[1].Total {
+		t.Errorf("total = %s", got.Total)
+	}
+	if got.Projects[0].Project != "ikno" || got.Projects[0].Duration != 115*time.Minute {
+		t.Errorf("period projects = %+v", got.Projects)
+	}
+}
+
+func TestEstimateHours_Options(t *testing.T) {
+	now := time.Date(2026, 4, 7, 10, 0, 0, 0, time.Local)
+	entries := []sources.Entry{
+		{Timestamp: now, Source: "obsidian", Location: "/vault", Content: "Daily note"},
+		{Timestamp: now.Add(50 * time.Minute), Source: "obsidian", Location: "/vault", Content: "Daily note"},
+	}
+
+	opts := DefaultHoursOptions()
+	got := EstimateHours(entries, opts)
+	if got.Total != 20*time.Minute || got.Projects[0].Project != UnassignedProject {
+		t.Errorf("default: %s for %q, want two 10m sessions", got.Total, got.Projects[0].Project)
+	}
+
+	opts.IdleGap = time.Hour
+	opts.DefaultLeadIn = 5 * time.Minute
+	if got := EstimateHours(entries, opts); got.Total != 55*time.Minute {
+		t.Errorf("with 1h idle gap: %s, want 55m", got.Total)
+	}
+}
+
+func TestEstimateHours_OpenTasks(t *testing.T) {
+	due := time.Date(2026, 4, 9, 0, 0, 0, 0, time.Local)
+	entries := []sources.Entry{
+		{Timestamp: due, Source: "todotxt", Location: "/home/u/todo.txt", Content: "open: call Bob",
+			Metadata: map[string]string{"task_status": "open", "due_date": "2026-04-09"}},
+		{Timestamp: due, Source: "markdown", Location: "/notes", Content: "open: ship pricing page (due 2026-04-09)",
+			Metadata: map[string]string{"kind": "task", "task_status": "open", "due_date": "2026-04-09"}},
+	}
+
+	got := EstimateHours(entries, DefaultHoursOptions())
+	if got.Total != 0 || len(got.Days) != 0 || len(got.Projects) != 0 {
+		t.Errorf("open tasks should not count as worked time, got %s over %+v", got.Total, got.Days)
+	}
+}
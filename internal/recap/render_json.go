@@ -3,12 +3,51 @@ package recap
 import (
 	"encoding/json"
 	"io"
+	"time"
 
 	"github.com/charemma/ikno/internal/sources"
 )
 
-// RenderJSON writes the recap as structured JSON.
+// RenderJSON writes the recap as structured JSON. Work items reference
+// their entries by index into activities. Estimated hours are included
+// when the result carries them, in whole minutes. Sources lists how reading
+// each source went.
 func RenderJSON(w io.Writer, result *RecapResult) error {
+	type JSONWorkItem struct {
+		Title      string    `json:"title"`
+		Projects   []string  `json:"projects,omitempty"`
+		Branches   []string  `json:"branches,omitempty"`
+		Issues     []string  `json:"issues,omitempty"`
+		Sources    []string  `json:"sources"`
+		Start      time.Time `json:"start"`
+		End        time.Time `json:"end"`
+		Activities []int     `json:"activities"`
+	}
+	type JSONProjectHours struct {
+		Project  string `json:"project"`
+		Minutes  int    `json:"minutes"`
+		Sessions int    `json:"sessions"`
+		Entries  int    `json:"entries"`
+	}
+	type JSONDayHours struct {
+		Date     string             `json:"date"`
+		Minutes  int                `json:"minutes"`
+		Projects []JSONProjectHours `json:"projects"`
+	}
+	type JSONHours struct {
+		Minutes  int                `json:"minutes"`
+		Days     []JSONDayHours     `json:"days"`
+		Projects []JSONProjectHours `json:"projects"`
+	}
+	type JSONSource struct {
+		Type       string `json:"type"`
+		Location   string `json:"location"`
+		Entries    int    `json:"entries"`
+		DurationMS int64  `json:"duration_ms"`
+		Error      string `json:"error,omitempty"`
+		Stage      string `json:"stage,omitempty"`
+		TimedOut   bool   `json:"timed_out,omitempty"`
+	}
 	type JSONReport struct {
 		Period struct {
 			From string `json:"from"`
@@ -16,16 +55,75 @@ func RenderJSON(w io.Writer, result *RecapResult) error {
 		} `json:"period"`
 		Total      int             `json:"total"`
 		Activities []sources.Entry `json:"activities"`
+		WorkItems  []JSONWorkItem  `json:"work_items"`
+		Hours      *JSONHours      `json:"hours,omitempty"`
+		Sources    []JSONSource    `json:"sources"`
 	}
 
 	report := JSONReport{
 		Total:      len(result.Entries),
 		Activities: result.Entries,
+		WorkItems:  []JSONWorkItem{},
+		Sources:    []JSONSource{},
 	}
 	report.Period.From = result.TimeRange.From.Format("2006-01-02")
 	report.Period.To = result.TimeRange.To.Format("2006-01-02")
 
+	for _, item := range Correlate(result.Entries) {
+		report.WorkItems = append(report.WorkItems, JSONWorkItem{
+			Title:      item.Title,
+			Projects:   item.Projects,
+			Branches:   item.Branches,
+			Issues:     item.Issues,
+			Sources:    item.Sources,
+			Start:      item.Start,
+			End:        item.End,
+			Activities: item.indexes,
+		})
+	}
+
+	if result.Hours != nil {
+		projectHours := func(ph []ProjectHours) []JSONProjectHours {
+			out := make([]JSONProjectHours, len(ph))
+			for i, p := range ph {
+				out[i] = JSONProjectHours{Project: p.Project, Minutes: minutes(p.Duration), Sessions: p.Sessions, Entries: p.Entries}
+			}
+			return out
+		}
+		report.Hours = &JSONHours{
+			Minutes:  minutes(result.Hours.Total),
+			Days:     []JSONDayHours{},
+			Projects: projectHours(result.Hours.Projects),
+		}
+		for _, d := range result.Hours.Days {
+			report.Hours.Days = append(report.Hours.Days, JSONDayHours{
+				Date:     d.Date.Format("2006-01-02"),
+				Minutes:  minutes(d.Total),
+				Projects: projectHours(d.Projects),
+			})
+		}
+	}
+
+	for _, src := range result.Sources {
+		js := JSONSource{
+			Type:       src.Type,
+			Location:   src.Location,
+			Entries:    src.Entries,
+			DurationMS: src.Duration.Milliseconds(),
+		}
+		if f := src.Failure; f != nil {
+			js.Error = f.Err.Error()
+			js.Stage = f.Stage
+			js.TimedOut = f.TimedOut
+		}
+		report.Sources = append(report.Sources, js)
+	}
+
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(report)
 }
+
+func minutes(d time.Duration) int {
+	return int(d.Round(time.Minute).Minutes())
+}
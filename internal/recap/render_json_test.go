@@ -0,0 +1,58 @@
+package recap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/timerange"
+)
+
+func TestRenderJSON_Sources(t *testing.T) {
+	now := time.Now()
+	result := &RecapResult{
+		TimeRange: &timerange.TimeRange{From: now.AddDate(0, 0, -7), To: now},
+		Entries:   []sources.Entry{{Timestamp: now, Source: "git", Location: "/code/ikno", Content: "fix: timeout"}},
+		Sources: []SourceReport{
+			{Type: "git", Location: "/code/ikno", Entries: 1, Duration: 120 * time.Millisecond},
+			{Type: "git", Location: "/mnt/remote", Duration: time.Minute, Failure: &SourceFailure{
+				Type: "git", Location: "/mnt/remote", Stage: StageCollect, TimedOut: true,
+				Err: fmt.Errorf("timed out after 1m0s: %w", context.DeadlineExceeded),
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := RenderJSON(&buf, result); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Sources []struct {
+			Type       string `json:"type"`
+			Location   string `json:"location"`
+			Entries    int    `json:"entries"`
+			DurationMS int64  `json:"duration_ms"`
+			Error      string `json:"error"`
+			Stage      string `json:"stage"`
+			TimedOut   bool   `json:"timed_out"`
+		} `json:"sources"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got.Sources) != 2 {
+		t.Fatalf("expected 2 sources, got %+v", got.Sources)
+	}
+	if ok := got.Sources[0]; ok.Entries != 1 || ok.DurationMS != 120 || ok.Error != "" {
+		t.Errorf("unexpected working source: %+v", ok)
+	}
+	failed := got.Sources[1]
+	if failed.Error != "timed out after 1m0s: context deadline exceeded" || failed.Stage != StageCollect || !failed.TimedOut {
+		t.Errorf("unexpected failed source: %+v", failed)
+	}
+}
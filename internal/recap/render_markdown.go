@@ -17,32 +17,71 @@ import (
 const maxAIPromptLength = 100
 
 // RenderForAI writes a markdown representation of the recap suitable as AI input.
-// Claude session entries are condensed to metadata-only summaries to reduce
-// token usage (typically 60-80% reduction for Claude-heavy recaps).
-// Non-Claude entries are rendered identically to renderMarkdownRaw.
+// Entries are correlated into work items (see Correlate) so that commits,
+// AI sessions, notes and tracked time about the same piece of work reach the
+// model as one section. Claude session entries are condensed to
+// metadata-only summaries to reduce token usage (typically 60-80% reduction
+// for Claude-heavy recaps). Other entries are rendered identically to
+// renderMarkdownRaw.
 func RenderForAI(w io.Writer, result *RecapResult) error {
+	items := Correlate(result.Entries)
+
 	_, _ = fmt.Fprintf(w, "# Work Recap\n\n")
 	_, _ = fmt.Fprintf(w, "**Period:** %s to %s\n", result.TimeRange.From.Format("2006-01-02"), result.TimeRange.To.Format("2006-01-02"))
-	_, _ = fmt.Fprintf(w, "**Total Activities:** %d\n\n", len(result.Entries))
+	_, _ = fmt.Fprintf(w, "**Total Activities:** %d\n", len(result.Entries))
+	_, _ = fmt.Fprintf(w, "**Work Items:** %d\n\n", len(items))
 	_, _ = fmt.Fprintf(w, "---\n\n")
 	_, _ = fmt.Fprintf(w, "This recap contains git commits with full diffs for the specified period.\n")
-	_, _ = fmt.Fprintf(w, "Each commit includes the message and the complete code changes.\n\n")
-
-	for _, group := range GroupByRepo(result.Entries) {
-		if group.Source == "claude" {
-			renderClaudeGroupForAI(w, group)
-			continue
-		}
-
-		renderGroupHeader(w, group)
-		for i, entry := range group.Entries {
-			renderEntryFull(w, entry, i+1)
+	_, _ = fmt.Fprintf(w, "Each commit includes the message and the complete code changes.\n")
+	_, _ = fmt.Fprintf(w, "Activity is grouped into work items: entries from different sources that\n")
+	_, _ = fmt.Fprintf(w, "share a project, branch, issue key or time slot belong to the same work.\n\n")
+
+	for _, item := range items {
+		renderWorkItemHeader(w, item)
+		for _, group := range GroupByRepo(item.Entries) {
+			if group.Source == "claude" {
+				renderClaudeGroupForAI(w, group)
+				continue
+			}
+
+			_, _ = fmt.Fprintf(w, "### %s: %s\n\n", SourceLabel(group.Source), group.Name)
+			_, _ = fmt.Fprintf(w, "`%s`\n\n", group.Path)
+			for i, entry := range group.Entries {
+				renderEntryFull(w, entry, i+1)
+			}
 		}
 	}
 
 	return nil
 }
 
+// renderWorkItemHeader writes the section header for a work item with the
+// links that tie its entries together.
+func renderWorkItemHeader(w io.Writer, item WorkItem) {
+	_, _ = fmt.Fprintf(w, "## Work Item: %s\n\n", item.Title)
+
+	labels := make([]string, len(item.Sources))
+	for i, s := range item.Sources {
+		labels[i] = SourceLabel(s)
+	}
+	_, _ = fmt.Fprintf(w, "**Sources:** %s\n", strings.Join(labels, ", "))
+	if item.Start.Format("2006-01-02") == item.End.Format("2006-01-02") {
+		_, _ = fmt.Fprintf(w, "**Time:** %s %s-%s\n", item.Start.Format("2006-01-02"), item.Start.Format("15:04"), item.End.Format("15:04"))
+	} else {
+		_, _ = fmt.Fprintf(w, "**Time:** %s to %s\n", item.Start.Format("2006-01-02 15:04"), item.End.Format("2006-01-02 15:04"))
+	}
+	if len(item.Projects) > 1 {
+		_, _ = fmt.Fprintf(w, "**Projects:** %s\n", strings.Join(item.Projects, ", "))
+	}
+	if len(item.Branches) > 0 {
+		_, _ = fmt.Fprintf(w, "**Branches:** %s\n", strings.Join(item.Branches, ", "))
+	}
+	if len(item.Issues) > 0 {
+		_, _ = fmt.Fprintf(w, "**Issues:** %s\n", strings.Join(item.Issues, ", "))
+	}
+	_, _ = fmt.Fprintf(w, "\n")
+}
+
 // RenderMarkdown writes a full markdown recap with diffs.
 // When stdout is a terminal the output is rendered via glamour for readability.
 func RenderMarkdown(w io.Writer, result *RecapResult) error {
@@ -130,7 +169,11 @@ func renderEntryFull(w io.Writer, entry sources.Entry, num int) {
 // table with just the key metadata: date, project, turns, duration, branch, and
 // a truncated topic line.
 func renderClaudeGroupForAI(w io.Writer, group RepoGroup) {
-	_, _ = fmt.Fprintf(w, "## Claude Sessions (%d sessions)\n\n", len(group.Entries))
+	noun := "sessions"
+	if len(group.Entries) == 1 {
+		noun = "session"
+	}
+	_, _ = fmt.Fprintf(w, "### Claude Sessions (%d %s)\n\n", len(group.Entries), noun)
 
 	for _, entry := range group.Entries {
 		date := entry.Timestamp.Format("2006-01-02")
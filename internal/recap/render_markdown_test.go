@@ -88,9 +88,12 @@ func TestRenderForAI_CondensesClaudeSessions(t *testing.T) {
 
 	aiText := aiBuf.String()
 
-	// Should contain condensed Claude section header
-	if !strings.Contains(aiText, "Claude Sessions (2 sessions)") {
-		t.Error("missing condensed Claude section header")
+	// The ikno session joins the ikno commits; dotfiles is its own work item
+	if !strings.Contains(aiText, "## Work Item: ikno") || !strings.Contains(aiText, "## Work Item: dotfiles") {
+		t.Error("missing work item sections")
+	}
+	if strings.Count(aiText, "Claude Sessions (1 session)") != 2 {
+		t.Error("missing condensed Claude section headers")
 	}
 
 	// Should contain truncated prompt (not the full 800+ char prompt)
@@ -11,4 +11,18 @@ type RecapResult struct {
 	TimeRange *timerange.TimeRange
 	Timespec  string
 	Entries   []sources.Entry
+	Hours     *HoursEstimate // estimated working time, nil unless requested
+	Sources   []SourceReport // how reading each source went, in config order
+}
+
+// Failures returns the failures of sources that could not be read
+// completely.
+func (r *RecapResult) Failures() []SourceFailure {
+	var failures []SourceFailure
+	for _, s := range r.Sources {
+		if s.Failure != nil {
+			failures = append(failures, *s.Failure)
+		}
+	}
+	return failures
 }
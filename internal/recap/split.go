@@ -0,0 +1,62 @@
+package recap
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/timerange"
+)
+
+// SplitByDay splits a recap into one recap per local calendar day with
+// activity, oldest first. The parts carry the day as timespec and time
+// range, and no hours or source reports.
+func SplitByDay(result *RecapResult) []*RecapResult {
+	byDay := make(map[time.Time][]sources.Entry)
+	for _, e := range result.Entries {
+		day := localDay(e.Timestamp)
+		byDay[day] = append(byDay[day], e)
+	}
+
+	parts := make([]*RecapResult, 0, len(byDay))
+	for _, day := range slices.SortedFunc(maps.Keys(byDay), time.Time.Compare) {
+		parts = append(parts, &RecapResult{
+			TimeRange: &timerange.TimeRange{From: day, To: day.AddDate(0, 0, 1).Add(-time.Nanosecond)},
+			Timespec:  day.Format("2006-01-02 Mon"),
+			Entries:   byDay[day],
+		})
+	}
+	return parts
+}
+
+// SplitByProject splits a recap into one recap per project, as assigned for
+// the hours estimate, with the most active project first and unassigned
+// activity last. The parts carry the project name as timespec and the time
+// range of the whole recap.
+func SplitByProject(result *RecapResult) []*RecapResult {
+	byProject := make(map[string][]sources.Entry)
+	forEachProject(result.Entries, func(project string, e sources.Entry) {
+		byProject[project] = append(byProject[project], e)
+	})
+
+	names := slices.Sorted(maps.Keys(byProject))
+	slices.SortStableFunc(names, func(a, b string) int {
+		if (a == UnassignedProject) != (b == UnassignedProject) {
+			if a == UnassignedProject {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(len(byProject[b]), len(byProject[a]))
+	})
+
+	parts := make([]*RecapResult, 0, len(names))
+	for _, name := range names {
+		entries := byProject[name]
+		slices.SortStableFunc(entries, func(a, b sources.Entry) int { return a.Timestamp.Compare(b.Timestamp) })
+		parts = append(parts, &RecapResult{TimeRange: result.TimeRange, Timespec: name, Entries: entries})
+	}
+	return parts
+}
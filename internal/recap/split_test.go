@@ -0,0 +1,45 @@
+package recap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charemma/ikno/internal/projects"
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/timerange"
+)
+
+func TestSplit(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	at := func(d, h int) time.Time { return day.AddDate(0, 0, d).Add(time.Duration(h) * time.Hour) }
+	result := &RecapResult{
+		TimeRange: &timerange.TimeRange{From: day, To: at(7, 0)},
+		Entries: []sources.Entry{
+			{Timestamp: at(2, 9), Source: "git", Location: "/code/ikno", Content: "feat: split"},
+			{Timestamp: at(0, 9), Source: "git", Location: "/code/ikno", Content: "feat: chunks"},
+			{Timestamp: at(0, 11), Source: "toggl", Location: "toggl", Metadata: map[string]string{projects.KeyProject: "acme"}},
+			{Timestamp: at(0, 23), Source: "git", Location: "/code/ikno", Content: "fix: budget"},
+			{Timestamp: at(4, 22), Source: "obsidian", Location: "/vault", Content: "Reading list"},
+		},
+	}
+
+	days := SplitByDay(result)
+	if len(days) != 3 || days[0].Timespec != "2026-03-02 Mon" || len(days[0].Entries) != 3 || days[2].Timespec != "2026-03-06 Fri" {
+		t.Fatalf("unexpected days: %+v", days)
+	}
+	if !days[1].TimeRange.From.Equal(at(2, 0)) || days[1].TimeRange.To.Day() != 4 {
+		t.Errorf("unexpected time range of the second day: %+v", days[1].TimeRange)
+	}
+
+	parts := SplitByProject(result)
+	var names []string
+	for _, p := range parts {
+		names = append(names, p.Timespec)
+	}
+	if len(parts) != 3 || names[0] != "ikno" || names[1] != "acme" || names[2] != UnassignedProject {
+		t.Fatalf("unexpected projects: %v", names)
+	}
+	if len(parts[0].Entries) != 3 || parts[0].Entries[0].Content != "feat: chunks" {
+		t.Errorf("ikno entries not sorted by time: %+v", parts[0].Entries)
+	}
+}
@@ -0,0 +1,258 @@
+package recap
+
+import (
+	"cmp"
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Stats are activity statistics of a period, counted from the entries and
+// the hours estimate of a recap.
+type Stats struct {
+	From  time.Time // first day of the period, local midnight
+	To    time.Time // last day counted, local midnight
+	Total int
+	Hours time.Duration
+
+	Days         []DayStats       // every day from From to To
+	ActiveDays   int              // days with at least one entry
+	Weekdays     [7]int           // entries per weekday, Monday first
+	WeekdayHours [7]time.Duration // estimated hours per weekday, Monday first
+	HoursOfDay   [24]int          // entries per local hour of the day
+
+	Sources  []SourceCount  // most entries first
+	Projects []ProjectHours // longest first
+
+	CurrentStreak Streak
+	LongestStreak Streak
+}
+
+// DayStats is the activity of one day.
+type DayStats struct {
+	Date    time.Time // local midnight
+	Entries int
+	Hours   time.Duration
+}
+
+// SourceCount is the number of entries of a source type.
+type SourceCount struct {
+	Source  string
+	Entries int
+}
+
+// Streak is a run of working days with activity. Weekend days without
+// activity neither break nor extend it.
+type Streak struct {
+	Days  int // active days
+	Start time.Time
+	End   time.Time
+}
+
+// ComputeStats counts the activity of a recap per day, weekday, hour and
+// source. Days after now are left out, so that a period reaching into the
+// future does not end in empty days, and so are their entries, such as
+// tasks due later: every count covers the same days. The current streak is the one still
+// running on the last counted day or the day before: a day without activity
+// so far does not end it.
+func ComputeStats(result *RecapResult, now time.Time) *Stats {
+	from := localDay(result.TimeRange.From)
+	to := localDay(result.TimeRange.To)
+	if today := localDay(now); today.Before(to) {
+		to = today
+	}
+	if to.Before(from) {
+		to = from
+	}
+
+	s := &Stats{From: from, To: to}
+
+	byDay := make(map[time.Time]*DayStats)
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		s.Days = append(s.Days, DayStats{Date: d})
+	}
+	for i := range s.Days {
+		byDay[s.Days[i].Date] = &s.Days[i]
+	}
+
+	bySource := make(map[string]int)
+	for _, e := range result.Entries {
+		local := e.Timestamp.Local()
+		ds, ok := byDay[localDay(local)]
+		if !ok {
+			continue
+		}
+		ds.Entries++
+		s.Total++
+		s.Weekdays[weekdayIndex(local.Weekday())]++
+		s.HoursOfDay[local.Hour()]++
+		bySource[e.Source]++
+	}
+
+	if result.Hours != nil {
+		s.Hours = result.Hours.Total
+		s.Projects = result.Hours.Projects
+		for _, dh := range result.Hours.Days {
+			if ds, ok := byDay[localDay(dh.Date)]; ok {
+				ds.Hours = dh.Total
+				s.WeekdayHours[weekdayIndex(dh.Date.Weekday())] += dh.Total
+			}
+		}
+	}
+
+	for _, src := range slices.Sorted(maps.Keys(bySource)) {
+		s.Sources = append(s.Sources, SourceCount{Source: src, Entries: bySource[src]})
+	}
+	slices.SortStableFunc(s.Sources, func(a, b SourceCount) int { return cmp.Compare(b.Entries, a.Entries) })
+
+	var run Streak
+	for _, d := range s.Days {
+		switch {
+		case d.Entries > 0:
+			s.ActiveDays++
+			if run.Days == 0 {
+				run.Start = d.Date
+			}
+			run.Days++
+			run.End = d.Date
+			if run.Days > s.LongestStreak.Days {
+				s.LongestStreak = run
+			}
+		case isWeekend(d.Date):
+		default:
+			run = Streak{}
+		}
+	}
+	current := s.Days
+	if n := len(current); n > 1 && current[n-1].Entries == 0 {
+		current = current[:n-1]
+	}
+	s.CurrentStreak = streakEndingAt(current)
+	return s
+}
+
+// streakEndingAt returns the streak running on the last of days.
+func streakEndingAt(days []DayStats) Streak {
+	var run Streak
+	for i := len(days) - 1; i >= 0; i-- {
+		d := days[i]
+		switch {
+		case d.Entries > 0:
+			if run.Days == 0 {
+				run.End = d.Date
+			}
+			run.Days++
+			run.Start = d.Date
+		case isWeekend(d.Date):
+		default:
+			return run
+		}
+	}
+	return run
+}
+
+func localDay(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// weekdayIndex numbers weekdays from Monday (0) to Sunday (6).
+func weekdayIndex(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
+
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// RenderStatsJSON writes the statistics as structured JSON, hours in whole
+// minutes.
+func RenderStatsJSON(w io.Writer, s *Stats) error {
+	type JSONDay struct {
+		Date    string `json:"date"`
+		Entries int    `json:"entries"`
+		Minutes int    `json:"minutes"`
+	}
+	type JSONWeekday struct {
+		Weekday string `json:"weekday"`
+		Entries int    `json:"entries"`
+		Minutes int    `json:"minutes"`
+	}
+	type JSONHour struct {
+		Hour    int `json:"hour"`
+		Entries int `json:"entries"`
+	}
+	type JSONSource struct {
+		Source  string `json:"source"`
+		Entries int    `json:"entries"`
+	}
+	type JSONProject struct {
+		Project  string `json:"project"`
+		Minutes  int    `json:"minutes"`
+		Sessions int    `json:"sessions"`
+		Entries  int    `json:"entries"`
+	}
+	type JSONStreak struct {
+		Days  int    `json:"days"`
+		Start string `json:"start,omitempty"`
+		End   string `json:"end,omitempty"`
+	}
+	type JSONStats struct {
+		Period struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"period"`
+		Total         int           `json:"total"`
+		Minutes       int           `json:"minutes"`
+		ActiveDays    int           `json:"active_days"`
+		CurrentStreak JSONStreak    `json:"current_streak"`
+		LongestStreak JSONStreak    `json:"longest_streak"`
+		Days          []JSONDay     `json:"days"`
+		Weekdays      []JSONWeekday `json:"weekdays"`
+		Hours         []JSONHour    `json:"hours"`
+		Sources       []JSONSource  `json:"sources"`
+		Projects      []JSONProject `json:"projects"`
+	}
+
+	streak := func(st Streak) JSONStreak {
+		if st.Days == 0 {
+			return JSONStreak{}
+		}
+		return JSONStreak{Days: st.Days, Start: st.Start.Format("2006-01-02"), End: st.End.Format("2006-01-02")}
+	}
+
+	report := JSONStats{
+		Total:         s.Total,
+		Minutes:       minutes(s.Hours),
+		ActiveDays:    s.ActiveDays,
+		CurrentStreak: streak(s.CurrentStreak),
+		LongestStreak: streak(s.LongestStreak),
+		Days:          []JSONDay{},
+		Sources:       []JSONSource{},
+		Projects:      []JSONProject{},
+	}
+	report.Period.From = s.From.Format("2006-01-02")
+	report.Period.To = s.To.Format("2006-01-02")
+
+	for _, d := range s.Days {
+		report.Days = append(report.Days, JSONDay{Date: d.Date.Format("2006-01-02"), Entries: d.Entries, Minutes: minutes(d.Hours)})
+	}
+	for i, n := range s.Weekdays {
+		report.Weekdays = append(report.Weekdays, JSONWeekday{Weekday: time.Weekday((i + 1) % 7).String(), Entries: n, Minutes: minutes(s.WeekdayHours[i])})
+	}
+	for h, n := range s.HoursOfDay {
+		report.Hours = append(report.Hours, JSONHour{Hour: h, Entries: n})
+	}
+	for _, src := range s.Sources {
+		report.Sources = append(report.Sources, JSONSource{Source: src.Source, Entries: src.Entries})
+	}
+	for _, p := range s.Projects {
+		report.Projects = append(report.Projects, JSONProject{Project: p.Project, Minutes: minutes(p.Duration), Sessions: p.Sessions, Entries: p.Entries})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
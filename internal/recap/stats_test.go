@@ -0,0 +1,103 @@
+package recap
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/timerange"
+)
+
+func TestComputeStats(t *testing.T) {
+	// Monday 2026-04-06 to Sunday 2026-04-19, "now" on Thursday 2026-04-16.
+	monday := time.Date(2026, 4, 6, 0, 0, 0, 0, time.Local)
+	at := func(day, hour int) time.Time { return monday.AddDate(0, 0, day).Add(time.Duration(hour) * time.Hour) }
+	commit := func(ts time.Time) sources.Entry {
+		return sources.Entry{Timestamp: ts, Source: "git", Location: "/code/ikno", Content: "commit"}
+	}
+
+	entries := []sources.Entry{
+		commit(at(0, 9)), commit(at(0, 10)), // Mon
+		commit(at(1, 9)), // Tue
+		{Timestamp: at(2, 14), Source: "obsidian", Location: "/vault"}, // Wed
+		// Thu and Fri empty: the streak breaks.
+		commit(at(5, 22)), // Sat
+		// Sun empty, but weekends do not break streaks.
+		commit(at(7, 9)), commit(at(7, 9)), commit(at(7, 9)), // Mon
+		commit(at(8, 10)), // Tue
+		commit(at(9, 11)), // Wed
+		// Thu, today: nothing yet.
+		// Next Saturday, after today: a task due then is not counted.
+		{Timestamp: at(12, 0), Source: "todotxt", Location: "/home/u/todo.txt", Content: "open: file taxes",
+			Metadata: map[string]string{"task_status": "open"}},
+	}
+	result := &RecapResult{
+		TimeRange: &timerange.TimeRange{From: monday, To: at(13, 23)},
+		Entries:   entries,
+	}
+	result.Hours = EstimateHours(entries, DefaultHoursOptions())
+
+	s := ComputeStats(result, at(10, 8))
+
+	if len(s.Days) != 11 || !s.To.Equal(monday.AddDate(0, 0, 10)) {
+		t.Fatalf("expected 11 days up to today, got %d ending %s", len(s.Days), s.To)
+	}
+	if s.Total != 10 || s.ActiveDays != 7 {
+		t.Errorf("total %d, active days %d", s.Total, s.ActiveDays)
+	}
+	if s.Days[7].Entries != 3 {
+		t.Errorf("second Monday has %d entries, want 3", s.Days[7].Entries)
+	}
+	if s.Weekdays != [7]int{5, 2, 2, 0, 0, 1, 0} {
+		t.Errorf("weekdays = %v", s.Weekdays)
+	}
+	if s.HoursOfDay[9] != 5 || s.HoursOfDay[22] != 1 {
+		t.Errorf("hours of day = %v", s.HoursOfDay)
+	}
+	if s.WeekdayHours[0] == 0 || s.Hours != result.Hours.Total {
+		t.Errorf("hours not taken from the estimate: %v, %s", s.WeekdayHours, s.Hours)
+	}
+	if len(s.Sources) != 2 || s.Sources[0].Source != "git" || s.Sources[0].Entries != 9 {
+		t.Errorf("sources = %+v", s.Sources)
+	}
+
+	// Sat, Mon, Tue, Wed: the empty Sunday does not count, today does not
+	// break the streak yet.
+	if s.CurrentStreak.Days != 4 || !s.CurrentStreak.Start.Equal(monday.AddDate(0, 0, 5)) || !s.CurrentStreak.End.Equal(monday.AddDate(0, 0, 9)) {
+		t.Errorf("current streak = %+v", s.CurrentStreak)
+	}
+	if s.LongestStreak.Days != 4 {
+		t.Errorf("longest streak = %+v", s.LongestStreak)
+	}
+
+	// A weekday without activity before today ends the current streak.
+	later := ComputeStats(result, at(11, 8))
+	if later.CurrentStreak.Days != 0 || later.LongestStreak.Days != 4 {
+		t.Errorf("streaks on Friday: current %+v, longest %+v", later.CurrentStreak, later.LongestStreak)
+	}
+
+	var buf strings.Builder
+	if err := RenderStatsJSON(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		ActiveDays    int `json:"active_days"`
+		CurrentStreak struct {
+			Days  int    `json:"days"`
+			Start string `json:"start"`
+		} `json:"current_streak"`
+		Weekdays []struct {
+			Weekday string `json:"weekday"`
+			Entries int    `json:"entries"`
+		} `json:"weekdays"`
+		Hours []struct{} `json:"hours"`
+	}
+	if err := json.Unmarshal([]byte(buf.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ActiveDays != 7 || got.CurrentStreak.Start != "2026-04-11" || got.Weekdays[0].Weekday != "Monday" || got.Weekdays[0].Entries != 5 || len(got.Hours) != 24 {
+		t.Errorf("unexpected JSON: %s", buf.String())
+	}
+}
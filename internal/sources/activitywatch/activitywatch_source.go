@@ -0,0 +1,239 @@
+package activitywatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/sources/timetrack"
+)
+
+// DefaultURL is the address of a local aw-server.
+const DefaultURL = "http://localhost:5600"
+
+const (
+	// mergeGap is the largest pause between two window events of the same
+	// app that still counts as one interval.
+	mergeGap = 2 * time.Minute
+	// minInterval drops app switches too short to be meaningful.
+	minInterval = time.Minute
+)
+
+// ActivityWatchSource implements the Source interface for a running
+// ActivityWatch server. Window events are merged into per-app intervals;
+// time marked as AFK is left out.
+type ActivityWatchSource struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewActivityWatchSource creates a source for the aw-server at baseURL. An
+// empty URL uses DefaultURL.
+func NewActivityWatchSource(baseURL string) *ActivityWatchSource {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	return &ActivityWatchSource{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *ActivityWatchSource) Type() string {
+	return "activitywatch"
+}
+
+func (s *ActivityWatchSource) Location() string {
+	return s.baseURL
+}
+
+func (s *ActivityWatchSource) Validate() error {
+	var info struct {
+		Hostname string `json:"hostname"`
+		Version  string `json:"version"`
+	}
+	if err := s.get(context.Background(), "/api/0/info", nil, &info); err != nil {
+		return fmt.Errorf("activitywatch server not reachable: %w", err)
+	}
+	return nil
+}
+
+type bucket struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Hostname string `json:"hostname"`
+}
+
+type event struct {
+	Timestamp time.Time `json:"timestamp"`
+	Duration  float64   `json:"duration"` // seconds
+	Data      struct {
+		App    string `json:"app"`
+		Title  string `json:"title"`
+		Status string `json:"status"` // afkstatus buckets: "afk" or "not-afk"
+	} `json:"data"`
+}
+
+func (e event) end() time.Time {
+	return e.Timestamp.Add(time.Duration(e.Duration * float64(time.Second)))
+}
+
+func (s *ActivityWatchSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	var buckets map[string]bucket
+	if err := s.get(ctx, "/api/0/buckets/", nil, &buckets); err != nil {
+		return nil, fmt.Errorf("failed to list activitywatch buckets: %w", err)
+	}
+
+	// Sort bucket IDs for deterministic output.
+	ids := slices.Sorted(maps.Keys(buckets))
+
+	afk := make(map[string][]event) // hostname -> afk periods
+	for _, id := range ids {
+		b := buckets[id]
+		if b.Type != "afkstatus" {
+			continue
+		}
+		events, err := s.events(ctx, id, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			if e.Data.Status == "afk" {
+				afk[b.Hostname] = append(afk[b.Hostname], e)
+			}
+		}
+	}
+
+	var entries []sources.Entry
+	for _, id := range ids {
+		b := buckets[id]
+		if b.Type != "currentwindow" {
+			continue
+		}
+		events, err := s.events(ctx, id, from, to)
+		if err != nil {
+			return nil, err
+		}
+		events = slices.DeleteFunc(events, func(e event) bool {
+			return e.Data.App == "" || duringAFK(e, afk[b.Hostname])
+		})
+
+		for _, iv := range mergeEvents(events) {
+			if b.Hostname != "" {
+				iv.Tags = append(iv.Tags, b.Hostname)
+			}
+			entry := iv.ToEntry("activitywatch", s.baseURL, to)
+			entry.Content = iv.App + ": " + entry.Content
+			entry.Metadata["app"] = iv.App
+			entry.Metadata["bucket"] = id
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+func (s *ActivityWatchSource) events(ctx context.Context, bucketID string, from, to time.Time) ([]event, error) {
+	query := url.Values{
+		"start": {from.Format(time.RFC3339)},
+		"end":   {to.Format(time.RFC3339)},
+		"limit": {"-1"},
+	}
+	var events []event
+	if err := s.get(ctx, "/api/0/buckets/"+url.PathEscape(bucketID)+"/events", query, &events); err != nil {
+		return nil, fmt.Errorf("failed to read events of bucket %s: %w", bucketID, err)
+	}
+	// The API returns newest first.
+	slices.SortFunc(events, func(a, b event) int { return a.Timestamp.Compare(b.Timestamp) })
+	return events, nil
+}
+
+func (s *ActivityWatchSource) get(ctx context.Context, path string, query url.Values, v any) error {
+	u := s.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// duringAFK reports whether the midpoint of e falls into an AFK period.
+func duringAFK(e event, periods []event) bool {
+	mid := e.Timestamp.Add(e.end().Sub(e.Timestamp) / 2)
+	for _, p := range periods {
+		if !mid.Before(p.Timestamp) && mid.Before(p.end()) {
+			return true
+		}
+	}
+	return false
+}
+
+// appInterval is time spent in one application. The app is no project:
+// projects come from the configured project mapping.
+type appInterval struct {
+	timetrack.Interval
+	App string
+}
+
+// mergeEvents joins consecutive events of the same app into intervals. The
+// title with the most time becomes the description.
+func mergeEvents(events []event) []appInterval {
+	var intervals []appInterval
+	var cur *appInterval
+	titles := make(map[string]time.Duration)
+
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		var best string
+		var bestDur time.Duration
+		for title, d := range titles {
+			if d > bestDur || (d == bestDur && title < best) {
+				best, bestDur = title, d
+			}
+		}
+		cur.Description = best
+		if cur.End.Sub(cur.Start) >= minInterval {
+			intervals = append(intervals, *cur)
+		}
+		cur = nil
+		clear(titles)
+	}
+
+	for _, e := range events {
+		if cur != nil && (cur.App != e.Data.App || e.Timestamp.Sub(cur.End) > mergeGap) {
+			flush()
+		}
+		if cur == nil {
+			cur = &appInterval{Interval: timetrack.Interval{Start: e.Timestamp.Local(), End: e.end().Local()}, App: e.Data.App}
+		} else if end := e.end().Local(); end.After(cur.End) {
+			cur.End = end
+		}
+		titles[e.Data.Title] += e.end().Sub(e.Timestamp)
+	}
+	flush()
+
+	return intervals
+}
@@ -0,0 +1,101 @@
+package activitywatch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var day = time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)
+
+func ev(offset time.Duration, seconds float64, app, title, status string) map[string]any {
+	return map[string]any{
+		"timestamp": day.Add(offset).Format(time.RFC3339),
+		"duration":  seconds,
+		"data":      map[string]string{"app": app, "title": title, "status": status},
+	}
+}
+
+// newTestServer stands in for aw-server with one window and one AFK bucket.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/0/info", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"hostname": "laptop", "version": "v0.13"})
+	})
+	mux.HandleFunc("/api/0/buckets/", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"aw-watcher-window_laptop": map[string]string{"id": "aw-watcher-window_laptop", "type": "currentwindow", "hostname": "laptop"},
+			"aw-watcher-afk_laptop":    map[string]string{"id": "aw-watcher-afk_laptop", "type": "afkstatus", "hostname": "laptop"},
+		})
+	})
+	mux.HandleFunc("/api/0/buckets/aw-watcher-window_laptop/events", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("start") == "" {
+			t.Error("expected start parameter")
+		}
+		// newest first, like the real server
+		_ = json.NewEncoder(w).Encode([]any{
+			ev(12*time.Hour, 1800, "Slack", "lunch chat", ""), // during AFK
+			ev(10*time.Hour+30*time.Minute, 20, "Firefox", "docs", ""),
+			ev(9*time.Hour+31*time.Minute, 1200, "Code", "billing.go", ""),
+			ev(9*time.Hour, 1800, "Code", "pricing.go", ""),
+		})
+	})
+	mux.HandleFunc("/api/0/buckets/aw-watcher-afk_laptop/events", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]any{
+			ev(12*time.Hour, 3600, "", "", "afk"),
+			ev(9*time.Hour, 3600, "", "", "not-afk"),
+		})
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestActivityWatchSource_GetEntries(t *testing.T) {
+	srv := newTestServer(t)
+	source := NewActivityWatchSource(srv.URL + "/")
+	if source.Location() != srv.URL {
+		t.Errorf("location = %q, want %q", source.Location(), srv.URL)
+	}
+	if err := source.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := source.GetEntries(t.Context(), day, day.Add(24*time.Hour-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The two Code events merge; the 20s Firefox switch and AFK Slack time drop out.
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %+v", len(entries), entries)
+	}
+	e := entries[0]
+	if e.Content != "Code: pricing.go -- 51m" {
+		t.Errorf("content = %q", e.Content)
+	}
+	if e.Metadata["app"] != "Code" || e.Metadata["project"] != "" {
+		t.Errorf("expected the app apart from the project: %v", e.Metadata)
+	}
+	if e.Metadata["tags"] != "laptop" || e.Metadata["bucket"] != "aw-watcher-window_laptop" {
+		t.Errorf("unexpected metadata: %v", e.Metadata)
+	}
+}
+
+func TestActivityWatchSource_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	source := NewActivityWatchSource(srv.URL)
+	if err := source.Validate(); err == nil {
+		t.Error("expected error for unreachable server")
+	}
+	if _, err := source.GetEntries(t.Context(), day, day.Add(time.Hour)); err == nil {
+		t.Error("expected error for unreachable server")
+	}
+}
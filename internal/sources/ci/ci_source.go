@@ -0,0 +1,251 @@
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/git"
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/sources/timetrack"
+)
+
+// Supported CI providers.
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// maxPages bounds pagination so a busy repository cannot stall a recap.
+const maxPages = 10
+
+// Run is a workflow run or pipeline.
+type Run struct {
+	ID         int64
+	Number     int64
+	Workflow   string
+	Title      string // commit message or PR title, GitHub only
+	Branch     string
+	SHA        string
+	Event      string
+	Status     string // normalized: passed, failed, cancelled, skipped, running
+	Actor      string
+	Start      time.Time
+	End        time.Time // zero while running
+	URL        string
+	FailedJobs []string
+}
+
+// provider fetches the runs a user triggered in a repository.
+type provider interface {
+	currentUser(ctx context.Context) (string, error)
+	runs(ctx context.Context, user string, from, to time.Time) ([]Run, error)
+}
+
+// CISource implements the Source interface for CI runs on GitHub Actions
+// and GitLab CI. The repository is taken from the remote of a local git
+// checkout, so it can reuse the paths of registered git sources.
+type CISource struct {
+	repoPath string
+	remote   string // git remote name
+	kind     string // provider override for self-hosted instances
+	apiURL   string // API base URL override
+	user     string
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewCISource creates a source for the git checkout at repoPath. Config
+// metadata may set "remote" (default origin), "provider" (github or
+// gitlab, for self-hosted hosts that are not recognized by name),
+// "api_url" and "user" (the login whose runs are reported; defaults to the
+// owner of the API token).
+func NewCISource(repoPath string, meta map[string]string) *CISource {
+	remote := meta["remote"]
+	if remote == "" {
+		remote = "origin"
+	}
+	return &CISource{
+		repoPath: repoPath,
+		remote:   remote,
+		kind:     meta["provider"],
+		apiURL:   strings.TrimRight(meta["api_url"], "/"),
+		user:     meta["user"],
+		client:   &http.Client{Timeout: 30 * time.Second},
+		now:      time.Now,
+	}
+}
+
+func (s *CISource) Type() string {
+	return "ci"
+}
+
+func (s *CISource) Location() string {
+	return s.repoPath
+}
+
+func (s *CISource) Validate() error {
+	remote, err := s.Remote(context.Background())
+	if err != nil {
+		return err
+	}
+	if _, err := s.provider(remote); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Remote reads and parses the configured git remote of the checkout.
+func (s *CISource) Remote(ctx context.Context) (git.Remote, error) {
+	out, err := exec.CommandContext(ctx, "git", "-C", s.repoPath, "remote", "get-url", s.remote).Output()
+	if err != nil {
+		return git.Remote{}, fmt.Errorf("failed to read git remote %q: %w", s.remote, err)
+	}
+	return git.ParseRemote(string(out))
+}
+
+// provider picks the CI API for a remote and reads its token from the
+// environment.
+func (s *CISource) provider(remote git.Remote) (provider, error) {
+	kind := s.kind
+	if kind == "" {
+		switch {
+		case remote.Host == "github.com":
+			kind = ProviderGitHub
+		case strings.Contains(remote.Host, "gitlab"):
+			kind = ProviderGitLab
+		default:
+			return nil, fmt.Errorf("cannot tell the CI provider of %s, set provider to github or gitlab", remote.Host)
+		}
+	}
+
+	switch kind {
+	case ProviderGitHub:
+		api := s.apiURL
+		if api == "" {
+			api = "https://api.github.com"
+			if remote.Host != "github.com" {
+				api = "https://" + remote.Host + "/api/v3" // GitHub Enterprise Server
+			}
+		}
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			token = os.Getenv("GH_TOKEN")
+		}
+		return &github{api: api, repo: remote.Path, token: token, client: s.client}, nil
+	case ProviderGitLab:
+		api := s.apiURL
+		if api == "" {
+			api = "https://" + remote.Host + "/api/v4"
+		}
+		return &gitlab{api: api, project: remote.Path, token: os.Getenv("GITLAB_TOKEN"), client: s.client}, nil
+	default:
+		return nil, fmt.Errorf("unsupported CI provider %q", kind)
+	}
+}
+
+func (s *CISource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	remote, err := s.Remote(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.provider(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	user := s.user
+	if user == "" {
+		if user, err = p.currentUser(ctx); err != nil {
+			return nil, fmt.Errorf("failed to determine CI user (set user or an API token): %w", err)
+		}
+	}
+
+	runs, err := p.runs(ctx, user, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []sources.Entry
+	for _, r := range runs {
+		if r.Start.Before(from) || r.Start.After(to) {
+			continue
+		}
+		entries = append(entries, s.entry(remote, r))
+	}
+	return entries, nil
+}
+
+func (s *CISource) entry(remote git.Remote, r Run) sources.Entry {
+	end := r.End
+	if end.IsZero() {
+		end = s.now()
+	}
+	minutes := int(end.Sub(r.Start).Round(time.Minute).Minutes())
+
+	content := fmt.Sprintf("[%s] %s #%d on %s: %s", remote.Name(), r.Workflow, r.Number, r.Branch, r.Status)
+	if r.Status != "running" {
+		content += " after " + timetrack.FormatMinutes(minutes)
+	}
+	if len(r.FailedJobs) > 0 {
+		content += " -- failing: " + strings.Join(r.FailedJobs, ", ")
+	}
+
+	metadata := map[string]string{
+		"repo":             remote.Path,
+		"workflow":         r.Workflow,
+		"run_id":           strconv.FormatInt(r.ID, 10),
+		"run_number":       strconv.FormatInt(r.Number, 10),
+		"status":           r.Status,
+		"branch":           r.Branch,
+		"sha":              r.SHA,
+		"event":            r.Event,
+		"actor":            r.Actor,
+		"url":              r.URL,
+		"duration_minutes": strconv.Itoa(minutes),
+		"start":            r.Start.Format(time.RFC3339),
+	}
+	if !r.End.IsZero() {
+		metadata["end"] = r.End.Format(time.RFC3339)
+	}
+	if r.Title != "" {
+		metadata["title"] = r.Title
+	}
+	if len(r.FailedJobs) > 0 {
+		metadata["failed_jobs"] = strings.Join(r.FailedJobs, ",")
+	}
+
+	return sources.Entry{
+		Timestamp: r.Start,
+		Source:    "ci",
+		Location:  s.repoPath,
+		Content:   content,
+		Metadata:  metadata,
+	}
+}
+
+// getJSON performs an authenticated GET and decodes the JSON response.
+// It returns the response headers for pagination.
+func getJSON(client *http.Client, req *http.Request, v any) (http.Header, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", req.URL.Path, err)
+	}
+	return resp.Header, nil
+}
@@ -0,0 +1,162 @@
+package ci
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// initRepo creates a git checkout with the given origin URL.
+func initRepo(t *testing.T, remoteURL string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", remoteURL},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	return dir
+}
+
+const githubRunsResponse = `{
+  "total_count": 3,
+  "workflow_runs": [
+    {"id": 901, "name": "Integration", "display_title": "Stop sharing the test database", "head_branch": "fix/flaky", "head_sha": "abc123", "event": "push",
+     "status": "completed", "conclusion": "success", "run_number": 58, "created_at": "2026-04-07T15:00:00Z", "updated_at": "2026-04-07T15:09:30Z",
+     "run_started_at": "2026-04-07T15:00:10Z", "html_url": "https://github.com/acme/shop/actions/runs/901", "actor": {"login": "octo"}},
+    {"id": 900, "name": "Integration", "display_title": "Retry on connection reset", "head_branch": "fix/flaky", "head_sha": "abc122", "event": "push",
+     "status": "completed", "conclusion": "failure", "run_number": 57, "created_at": "2026-04-07T13:00:00Z", "updated_at": "2026-04-07T13:12:00Z",
+     "run_started_at": "2026-04-07T13:00:00Z", "html_url": "https://github.com/acme/shop/actions/runs/900", "actor": {"login": "octo"}},
+    {"id": 902, "name": "Lint", "head_branch": "main", "head_sha": "abc124", "event": "push",
+     "status": "in_progress", "conclusion": null, "run_number": 12, "created_at": "2026-04-07T16:00:00Z", "updated_at": "2026-04-07T16:01:00Z",
+     "run_started_at": "2026-04-07T16:00:00Z", "actor": {"login": "octo"}}
+  ]
+}`
+
+const githubJobsResponse = `{"jobs": [
+  {"name": "unit", "conclusion": "success"},
+  {"name": "integration (postgres)", "conclusion": "failure"},
+  {"name": "e2e", "conclusion": "timed_out"}
+]}`
+
+func TestCISource_GitHub(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gh-token" {
+			http.Error(w, `{"message": "Bad credentials"}`, http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/user":
+			_, _ = w.Write([]byte(`{"login": "octo"}`))
+		case "/repos/acme/shop/actions/runs":
+			queries = append(queries, r.URL.RawQuery)
+			_, _ = w.Write([]byte(githubRunsResponse))
+		case "/repos/acme/shop/actions/runs/900/jobs":
+			_, _ = w.Write([]byte(githubJobsResponse))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITHUB_TOKEN", "gh-token")
+	repo := initRepo(t, "git@github.com:acme/shop.git")
+	source := NewCISource(repo, map[string]string{"api_url": server.URL})
+	source.now = func() time.Time { return time.Date(2026, 4, 7, 16, 5, 0, 0, time.UTC) }
+	if err := source.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)
+	entries, err := source.GetEntries(t.Context(), day, day.Add(24*time.Hour-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Content)
+	}
+	want := []string{
+		"[shop] Integration #58 on fix/flaky: passed after 9m",
+		"[shop] Integration #57 on fix/flaky: failed after 12m -- failing: integration (postgres), e2e",
+		"[shop] Lint #12 on main: running",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("entries:\n got %q\nwant %q", got, want)
+	}
+
+	failed := entries[1].Metadata
+	if failed["failed_jobs"] != "integration (postgres),e2e" || failed["title"] != "Retry on connection reset" || failed["repo"] != "acme/shop" {
+		t.Errorf("unexpected metadata: %+v", failed)
+	}
+	if entries[2].Metadata["duration_minutes"] != "5" || entries[2].Metadata["end"] != "" {
+		t.Errorf("running run should count up to now: %+v", entries[2].Metadata)
+	}
+	if len(queries) != 1 || !strings.Contains(queries[0], "actor=octo") || !strings.Contains(queries[0], "created=2026-04-07T00%3A00%3A00Z..") {
+		t.Errorf("unexpected run queries: %q", queries)
+	}
+}
+
+func TestCISource_GitLab(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "gl-token" {
+			http.Error(w, `{"message": "401 Unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/infra%2Fplatform%2Fdeployer/pipelines":
+			if r.URL.Query().Get("username") != "jdoe" {
+				t.Errorf("unexpected username filter: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`[
+  {"id": 7001, "iid": 310, "status": "failed", "ref": "main", "sha": "f00", "source": "push",
+   "created_at": "2026-04-07T09:00:00Z", "updated_at": "2026-04-07T09:25:00Z", "web_url": "https://gitlab.example.com/p/7001"},
+  {"id": 7002, "iid": 311, "name": "nightly", "status": "canceled", "ref": "main", "sha": "f01", "source": "schedule",
+   "created_at": "2026-04-08T01:00:00Z", "updated_at": "2026-04-08T01:05:00Z"}
+]`))
+		case "/api/v4/projects/infra%2Fplatform%2Fdeployer/pipelines/7001/jobs":
+			if r.URL.Query().Get("scope[]") != "failed" {
+				t.Errorf("expected failed job scope: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`[{"name": "deploy:staging", "allow_failure": false}, {"name": "audit", "allow_failure": true}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("GITLAB_TOKEN", "gl-token")
+	repo := initRepo(t, "https://gitlab.example.com/infra/platform/deployer.git")
+	source := NewCISource(repo, map[string]string{"api_url": server.URL + "/api/v4", "user": "jdoe"})
+
+	day := time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)
+	entries, err := source.GetEntries(t.Context(), day, day.Add(24*time.Hour-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %+v", len(entries), entries)
+	}
+	if entries[0].Content != "[deployer] pipeline #310 on main: failed after 25m -- failing: deploy:staging" {
+		t.Errorf("content = %q", entries[0].Content)
+	}
+}
+
+func TestCISource_UnknownProvider(t *testing.T) {
+	repo := initRepo(t, "https://git.example.com/team/app.git")
+	if err := NewCISource(repo, nil).Validate(); err == nil {
+		t.Error("expected error for a host that is neither GitHub nor GitLab")
+	}
+	if err := NewCISource(repo, map[string]string{"provider": ProviderGitLab}).Validate(); err != nil {
+		t.Errorf("explicit provider should be accepted: %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// github reads GitHub Actions workflow runs.
+type github struct {
+	api    string
+	repo   string // owner/repo
+	token  string
+	client *http.Client
+}
+
+type githubRun struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	DisplayTitle string    `json:"display_title"`
+	HeadBranch   string    `json:"head_branch"`
+	HeadSHA      string    `json:"head_sha"`
+	Event        string    `json:"event"`
+	Status       string    `json:"status"`     // queued, in_progress, completed
+	Conclusion   string    `json:"conclusion"` // success, failure, cancelled, skipped, timed_out, ...
+	RunNumber    int64     `json:"run_number"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	RunStartedAt time.Time `json:"run_started_at"`
+	HTMLURL      string    `json:"html_url"`
+	Actor        struct {
+		Login string `json:"login"`
+	} `json:"actor"`
+}
+
+func (g *github) request(ctx context.Context, path string, query url.Values) (*http.Request, error) {
+	u := g.api + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+	return req, nil
+}
+
+func (g *github) currentUser(ctx context.Context) (string, error) {
+	if g.token == "" {
+		return "", fmt.Errorf("GITHUB_TOKEN is not set")
+	}
+	req, err := g.request(ctx, "/user", nil)
+	if err != nil {
+		return "", err
+	}
+	var user struct {
+		Login string `json:"login"`
+	}
+	if _, err := getJSON(g.client, req, &user); err != nil {
+		return "", err
+	}
+	return user.Login, nil
+}
+
+func (g *github) runs(ctx context.Context, user string, from, to time.Time) ([]Run, error) {
+	var runs []Run
+	for page := 1; page <= maxPages; page++ {
+		query := url.Values{
+			"actor":    {user},
+			"created":  {from.UTC().Format(time.RFC3339) + ".." + to.UTC().Format(time.RFC3339)},
+			"per_page": {"100"},
+			"page":     {strconv.Itoa(page)},
+		}
+		req, err := g.request(ctx, "/repos/"+g.repo+"/actions/runs", query)
+		if err != nil {
+			return nil, err
+		}
+		var resp struct {
+			WorkflowRuns []githubRun `json:"workflow_runs"`
+		}
+		if _, err := getJSON(g.client, req, &resp); err != nil {
+			return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+		}
+
+		for _, wr := range resp.WorkflowRuns {
+			run := g.run(wr)
+			if run.Status == "failed" {
+				if run.FailedJobs, err = g.failedJobs(ctx, wr.ID); err != nil {
+					return nil, err
+				}
+			}
+			runs = append(runs, run)
+		}
+		if len(resp.WorkflowRuns) < 100 {
+			break
+		}
+	}
+	return runs, nil
+}
+
+func (g *github) run(wr githubRun) Run {
+	start := wr.RunStartedAt
+	if start.IsZero() {
+		start = wr.CreatedAt
+	}
+	run := Run{
+		ID:       wr.ID,
+		Number:   wr.RunNumber,
+		Workflow: wr.Name,
+		Title:    wr.DisplayTitle,
+		Branch:   wr.HeadBranch,
+		SHA:      wr.HeadSHA,
+		Event:    wr.Event,
+		Actor:    wr.Actor.Login,
+		Start:    start,
+		URL:      wr.HTMLURL,
+		Status:   "running",
+	}
+	if wr.Status == "completed" {
+		run.End = wr.UpdatedAt
+		run.Status = normalizeStatus(wr.Conclusion)
+	}
+	return run
+}
+
+// failedJobs lists the names of the failed jobs of a run's latest attempt.
+func (g *github) failedJobs(ctx context.Context, runID int64) ([]string, error) {
+	path := fmt.Sprintf("/repos/%s/actions/runs/%d/jobs", g.repo, runID)
+	req, err := g.request(ctx, path, url.Values{"per_page": {"100"}})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Jobs []struct {
+			Name       string `json:"name"`
+			Conclusion string `json:"conclusion"`
+		} `json:"jobs"`
+	}
+	if _, err := getJSON(g.client, req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list jobs of run %d: %w", runID, err)
+	}
+
+	var names []string
+	for _, j := range resp.Jobs {
+		if normalizeStatus(j.Conclusion) == "failed" {
+			names = append(names, j.Name)
+		}
+	}
+	return names, nil
+}
+
+// normalizeStatus maps GitHub conclusions and GitLab pipeline statuses to
+// passed, failed, cancelled, skipped or running.
+func normalizeStatus(status string) string {
+	switch status {
+	case "success":
+		return "passed"
+	case "failure", "failed", "timed_out", "startup_failure":
+		return "failed"
+	case "cancelled", "canceled":
+		return "cancelled"
+	case "skipped", "neutral":
+		return "skipped"
+	default:
+		return "running"
+	}
+}
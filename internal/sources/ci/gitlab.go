@@ -0,0 +1,150 @@
+package ci
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// gitlab reads GitLab CI pipelines.
+type gitlab struct {
+	api     string
+	project string // group/subgroup/project
+	token   string
+	client  *http.Client
+}
+
+type gitlabPipeline struct {
+	ID        int64     `json:"id"`
+	IID       int64     `json:"iid"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Ref       string    `json:"ref"`
+	SHA       string    `json:"sha"`
+	Source    string    `json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	WebURL    string    `json:"web_url"`
+}
+
+// gitlabDone are the pipeline statuses that will not change anymore.
+var gitlabDone = map[string]bool{"success": true, "failed": true, "canceled": true, "skipped": true}
+
+func (g *gitlab) request(ctx context.Context, path string, query url.Values) (*http.Request, error) {
+	u := g.api + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if g.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", g.token)
+	}
+	return req, nil
+}
+
+// projectPath is the URL-encoded project path GitLab accepts in place of
+// the numeric ID.
+func (g *gitlab) projectPath() string {
+	return "/projects/" + url.PathEscape(g.project)
+}
+
+func (g *gitlab) currentUser(ctx context.Context) (string, error) {
+	if g.token == "" {
+		return "", fmt.Errorf("GITLAB_TOKEN is not set")
+	}
+	req, err := g.request(ctx, "/user", nil)
+	if err != nil {
+		return "", err
+	}
+	var user struct {
+		Username string `json:"username"`
+	}
+	if _, err := getJSON(g.client, req, &user); err != nil {
+		return "", err
+	}
+	return user.Username, nil
+}
+
+// runs lists pipelines updated since from. The list API has no start or
+// finish time, so created_at and updated_at bound the duration.
+func (g *gitlab) runs(ctx context.Context, user string, from, to time.Time) ([]Run, error) {
+	var runs []Run
+	for page := 1; page <= maxPages; page++ {
+		query := url.Values{
+			"username":      {user},
+			"updated_after": {from.UTC().Format(time.RFC3339)},
+			"per_page":      {"100"},
+			"page":          {strconv.Itoa(page)},
+		}
+		req, err := g.request(ctx, g.projectPath()+"/pipelines", query)
+		if err != nil {
+			return nil, err
+		}
+		var pipelines []gitlabPipeline
+		if _, err := getJSON(g.client, req, &pipelines); err != nil {
+			return nil, fmt.Errorf("failed to list pipelines: %w", err)
+		}
+
+		for _, p := range pipelines {
+			if p.CreatedAt.After(to) {
+				continue
+			}
+			run := Run{
+				ID:       p.ID,
+				Number:   p.IID,
+				Workflow: cmp.Or(p.Name, "pipeline"),
+				Branch:   p.Ref,
+				SHA:      p.SHA,
+				Event:    p.Source,
+				Actor:    user,
+				Start:    p.CreatedAt,
+				URL:      p.WebURL,
+				Status:   "running",
+			}
+			if gitlabDone[p.Status] {
+				run.End = p.UpdatedAt
+				run.Status = normalizeStatus(p.Status)
+			}
+			if run.Status == "failed" {
+				if run.FailedJobs, err = g.failedJobs(ctx, p.ID); err != nil {
+					return nil, err
+				}
+			}
+			runs = append(runs, run)
+		}
+		if len(pipelines) < 100 {
+			break
+		}
+	}
+	return runs, nil
+}
+
+func (g *gitlab) failedJobs(ctx context.Context, pipelineID int64) ([]string, error) {
+	path := fmt.Sprintf("%s/pipelines/%d/jobs", g.projectPath(), pipelineID)
+	req, err := g.request(ctx, path, url.Values{"scope[]": {"failed"}, "per_page": {"100"}})
+	if err != nil {
+		return nil, err
+	}
+	var jobs []struct {
+		Name         string `json:"name"`
+		AllowFailure bool   `json:"allow_failure"`
+	}
+	if _, err := getJSON(g.client, req, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to list jobs of pipeline %d: %w", pipelineID, err)
+	}
+
+	var names []string
+	for _, j := range jobs {
+		if !j.AllowFailure {
+			names = append(names, j.Name)
+		}
+	}
+	return names, nil
+}
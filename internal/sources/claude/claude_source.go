@@ -2,6 +2,7 @@ package claude
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -171,7 +172,7 @@ func (c *ClaudeSource) Validate() error {
 	return fmt.Errorf("no session files found in %s", projectsDir)
 }
 
-func (c *ClaudeSource) GetEntries(from, to time.Time) ([]sources.Entry, error) {
+func (c *ClaudeSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
 	projectsDir := filepath.Join(c.claudeHome, "projects")
 
 	dirEntries, err := os.ReadDir(projectsDir)
@@ -221,6 +222,9 @@ func (c *ClaudeSource) GetEntries(from, to time.Time) ([]sources.Entry, error) {
 		wg.Add(1)
 		go func(idx int, j fileJob) {
 			defer wg.Done()
+			if ctx.Err() != nil {
+				return
+			}
 			fileEntries, err := c.parseSessions(j.path, from, to, j.projectDir)
 			if err != nil {
 				mu.Lock()
@@ -233,6 +237,9 @@ func (c *ClaudeSource) GetEntries(from, to time.Time) ([]sources.Entry, error) {
 	}
 
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	var entries []sources.Entry
 	for _, r := range results {
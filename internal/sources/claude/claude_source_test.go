@@ -80,7 +80,7 @@ func TestClaudeSource_GetEntries(t *testing.T) {
 	appendSessionLine(t, sessionFile, userLineWithOpts("fix the test", now.Add(-30*time.Minute), false, sid, cwd, "main"))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(yesterday, now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), yesterday, now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -121,7 +121,7 @@ func TestClaudeSource_GetEntries_SessionOutsideRange(t *testing.T) {
 	appendSessionLine(t, sessionFile, userLine("old message", lastWeek, false))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(now.Add(-24*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-24*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -143,7 +143,7 @@ func TestClaudeSource_GetEntries_SkipsToolResults(t *testing.T) {
 	appendSessionLine(t, sessionFile, toolResultLine)
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -167,7 +167,7 @@ func TestClaudeSource_GetEntries_SkipsMetaMessages(t *testing.T) {
 	appendSessionLine(t, sessionFile, userLine("meta message", now.Add(1*time.Minute), true))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -189,7 +189,7 @@ func TestClaudeSource_GetEntries_SkipsSystemInterrupts(t *testing.T) {
 	appendSessionLine(t, sessionFile, userLine("[Request interrupted by user]", now.Add(1*time.Minute), false))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -218,7 +218,7 @@ func TestClaudeSource_GetEntries_MultipleProjects(t *testing.T) {
 		userLineWithOpts("message from bar", now.Add(1*time.Minute), false, "s-bar", "/home/user/code/bar", "main"))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -251,7 +251,7 @@ func TestClaudeSource_GetEntries_SessionMetadata(t *testing.T) {
 	appendSessionLine(t, sessionFile, userLineWithOpts("looks good", now.Add(10*time.Minute), false, sid, cwd, "feat/test"))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -300,7 +300,7 @@ func TestClaudeSource_GetEntries_ToolUseExtraction(t *testing.T) {
 	appendSessionLine(t, sessionFile, assistantLine(now.Add(5*time.Second), sid, "/tmp/test/project1", "main", "claude-opus-4-6", tools))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -326,7 +326,7 @@ func TestClaudeSource_GetEntries_SlashCommandSkip(t *testing.T) {
 	appendSessionLine(t, sessionFile, userLine("implement the feature", now.Add(1*time.Minute), false))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -354,7 +354,7 @@ func TestClaudeSource_GetEntries_FirstPromptCap(t *testing.T) {
 	appendSessionLine(t, sessionFile, userLine(longText, now, false))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -383,7 +383,7 @@ func TestClaudeSource_GetEntries_SessionDuration(t *testing.T) {
 	appendSessionLine(t, sessionFile, userLineWithOpts("end", start.Add(47*time.Minute+30*time.Second), false, "s1", "/tmp/test/project1", "main"))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(start.Add(-1*time.Hour), start.Add(2*time.Hour))
+	entries, err := source.GetEntries(t.Context(), start.Add(-1*time.Hour), start.Add(2*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -414,7 +414,7 @@ func TestClaudeSource_GetEntries_LogsParseErrors(t *testing.T) {
 	source := NewClaudeSource(claudeHome)
 	source.warn = &buf
 
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -443,7 +443,7 @@ func TestClaudeSource_GetEntries_PreservesFullContent(t *testing.T) {
 	appendSessionLine(t, sessionFile, userLine(text, now, false))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -470,7 +470,7 @@ func TestClaudeSource_GetEntries_EmptySession(t *testing.T) {
 	appendSessionLine(t, sessionFile, userLine("[Request interrupted by user]", now.Add(1*time.Minute), false))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -488,7 +488,7 @@ func TestClaudeSource_GetEntries_NoAssistantMessages(t *testing.T) {
 	appendSessionLine(t, sessionFile, userLine("interrupted before response", now, false))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -516,7 +516,7 @@ func TestClaudeSource_GetEntries_MultiSessionFile(t *testing.T) {
 	appendSessionLine(t, sessionFile, userLineWithOpts("session B", now.Add(1*time.Minute), false, "session-b", "/tmp/test/project1", "main"))
 
 	source := NewClaudeSource(claudeHome)
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -547,7 +547,7 @@ func TestClaudeSource_GetEntries_AllLinesFail(t *testing.T) {
 	source := NewClaudeSource(claudeHome)
 	source.warn = &buf
 
-	entries, err := source.GetEntries(now.Add(-1*time.Hour), now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), now.Add(-1*time.Hour), now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -0,0 +1,196 @@
+package containers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// Tools the source can query.
+const (
+	ToolKubectl = "kubectl"
+	ToolHelm    = "helm"
+	ToolDocker  = "docker"
+	ToolPodman  = "podman"
+)
+
+// DefaultTools are queried when the config does not name any.
+var DefaultTools = []string{ToolKubectl, ToolHelm, ToolDocker, ToolPodman}
+
+// runner executes a command and returns its stdout. Tests replace it to
+// serve fixture files instead of calling the real tools.
+type runner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// ContainerSource implements the Source interface for container and
+// Kubernetes activity: resources changed with kubectl (from their managed
+// fields), Helm release history, and Docker/Podman events. Docker keeps
+// only recent events in memory, so a captured `docker events` log can be
+// read as well.
+type ContainerSource struct {
+	kubeconfig  string
+	kubeContext string
+	eventsFile  string
+
+	currentContext string // looked up once per run when kubeContext is empty
+	tools          []string
+	run            runner
+	lookPath       func(string) (string, error)
+}
+
+// DefaultKubeconfig returns the first file in $KUBECONFIG, else
+// ~/.kube/config.
+func DefaultKubeconfig() string {
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		return filepath.SplitList(env)[0]
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".kube", "config")
+}
+
+// NewContainerSource creates a source using the given kubeconfig. Config
+// metadata may set "tools" (comma-separated subset of DefaultTools),
+// "context" (kube context) and "events_file" (a JSON log written by
+// `docker events --format '{{json .}}'`).
+func NewContainerSource(kubeconfig string, meta map[string]string) *ContainerSource {
+	s := &ContainerSource{
+		kubeconfig:  kubeconfig,
+		kubeContext: meta["context"],
+		eventsFile:  meta["events_file"],
+		tools:       DefaultTools,
+		run:         runCommand,
+		lookPath:    exec.LookPath,
+	}
+	if tools := meta["tools"]; tools != "" {
+		s.tools = nil
+		for t := range strings.SplitSeq(tools, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				s.tools = append(s.tools, t)
+			}
+		}
+	}
+	return s
+}
+
+func (s *ContainerSource) Type() string {
+	return "containers"
+}
+
+func (s *ContainerSource) Location() string {
+	return s.kubeconfig
+}
+
+func (s *ContainerSource) Validate() error {
+	for _, t := range s.tools {
+		if !slices.Contains(DefaultTools, t) {
+			return fmt.Errorf("unknown tool %q (supported: %s)", t, strings.Join(DefaultTools, ", "))
+		}
+	}
+	if s.eventsFile != "" {
+		if _, err := os.Stat(s.eventsFile); err != nil {
+			return fmt.Errorf("events file not accessible: %w", err)
+		}
+		return nil
+	}
+	if len(s.available()) == 0 {
+		return fmt.Errorf("none of %s found in PATH", strings.Join(s.tools, ", "))
+	}
+	return nil
+}
+
+// available returns the configured tools that are installed.
+func (s *ContainerSource) available() []string {
+	var found []string
+	for _, t := range s.tools {
+		if _, err := s.lookPath(t); err == nil {
+			found = append(found, t)
+		}
+	}
+	return found
+}
+
+// GetEntries queries every installed tool. A tool that fails, such as
+// kubectl without a reachable cluster or docker without a running daemon,
+// is skipped; an error is returned only when nothing could be read.
+func (s *ContainerSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	var entries []sources.Entry
+	var errs []error
+	read := 0
+
+	collect := func(found []sources.Entry, err error) {
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		read++
+		for _, e := range found {
+			if !e.Timestamp.Before(from) && !e.Timestamp.After(to) {
+				entries = append(entries, e)
+			}
+		}
+	}
+
+	for _, tool := range s.available() {
+		switch tool {
+		case ToolKubectl:
+			collect(s.kubectlEntries(ctx))
+		case ToolHelm:
+			collect(s.helmEntries(ctx, from))
+		case ToolDocker, ToolPodman:
+			collect(s.runtimeEvents(ctx, tool, from, to))
+		}
+	}
+	if s.eventsFile != "" {
+		collect(s.eventsFileEntries())
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if read == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	slices.SortFunc(entries, func(a, b sources.Entry) int { return a.Timestamp.Compare(b.Timestamp) })
+	return entries, nil
+}
+
+// kubeArgs returns the flags selecting kubeconfig and context. Helm spells
+// the context flag differently.
+func (s *ContainerSource) kubeArgs(tool string) []string {
+	var args []string
+	if s.kubeconfig != "" {
+		if _, err := os.Stat(s.kubeconfig); err == nil {
+			args = append(args, "--kubeconfig", s.kubeconfig)
+		}
+	}
+	if s.kubeContext != "" {
+		flag := "--context"
+		if tool == ToolHelm {
+			flag = "--kube-context"
+		}
+		args = append(args, flag, s.kubeContext)
+	}
+	return args
+}
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("failed to run %s: %s", name, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,241 @@
+package containers
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const kubectlFixture = `{
+  "items": [
+    {
+      "kind": "Deployment",
+      "metadata": {
+        "name": "api",
+        "namespace": "prod",
+        "managedFields": [
+          {"manager": "kubectl-client-side-apply", "operation": "Update", "time": "2026-04-07T09:10:00Z"},
+          {"manager": "kube-controller-manager", "operation": "Update", "time": "2026-04-07T09:11:00Z"},
+          {"manager": "kubectl-rollout", "operation": "Update", "time": "2026-04-07T15:00:00Z"}
+        ]
+      }
+    },
+    {
+      "kind": "ConfigMap",
+      "metadata": {
+        "name": "flags",
+        "namespace": "staging",
+        "managedFields": [
+          {"manager": "kubectl-edit", "operation": "Update", "time": "2026-04-01T08:00:00Z"}
+        ]
+      }
+    }
+  ]
+}`
+
+const helmListFixture = `[
+  {"name": "api", "namespace": "prod", "revision": "13", "updated": "2026-04-07 11:00:00.123456 +0000 UTC", "status": "deployed", "chart": "api-1.4.2"},
+  {"name": "legacy", "namespace": "prod", "revision": "2", "updated": "2025-12-01 10:00:00.5 +0000 UTC", "status": "deployed", "chart": "legacy-0.9.0"}
+]`
+
+const helmHistoryFixture = `[
+  {"revision": 11, "updated": "2026-04-06T16:00:00Z", "status": "superseded", "chart": "api-1.4.1", "app_version": "1.4.1", "description": "Upgrade complete"},
+  {"revision": 12, "updated": "2026-04-07T10:00:00Z", "status": "failed", "chart": "api-1.4.2", "app_version": "1.4.2", "description": "Upgrade \"api\" failed"},
+  {"revision": 13, "updated": "2026-04-07T11:00:00Z", "status": "deployed", "chart": "api-1.4.2", "app_version": "1.4.2", "description": "Upgrade complete"}
+]`
+
+const dockerEventsFixture = `{"status":"pull","id":"nginx:1.27","Type":"image","Action":"pull","Actor":{"ID":"nginx:1.27","Attributes":{"name":"nginx"}},"scope":"local","time":1775553000,"timeNano":1775553000000000000}
+{"status":"start","id":"3f2a","from":"nginx:1.27","Type":"container","Action":"start","Actor":{"ID":"3f2a","Attributes":{"com.docker.compose.project":"shop","image":"nginx:1.27","name":"shop-web-1"}},"scope":"local","time":1775553060,"timeNano":1775553060000000000}
+{"Type":"container","Action":"exec_start: sh","Actor":{"ID":"3f2a","Attributes":{"name":"shop-web-1"}},"time":1775553100}
+{"status":"die","id":"3f2a","from":"nginx:1.27","Type":"container","Action":"die","Actor":{"ID":"3f2a","Attributes":{"com.docker.compose.project":"shop","exitCode":"137","image":"nginx:1.27","name":"shop-web-1"}},"scope":"local","time":1775556000,"timeNano":1775556000000000000}
+`
+
+const podmanEventsFixture = `{"ID":"91bc","Image":"quay.io/acme/worker:2","Name":"worker","Status":"start","Time":"2026-04-07T12:00:00.5Z","Type":"container","Attributes":{"image":"quay.io/acme/worker:2"}}
+{"ID":"91bc","Image":"quay.io/acme/worker:2","Name":"worker","Status":"died","Time":"2026-04-07T12:01:00Z","Type":"container","ContainerExitCode":0}
+{"ID":"aa01","Name":"localhost/acme/worker:dev","Status":"build","Time":"2026-04-07T12:05:00Z","Type":"image"}
+`
+
+// fixtureRunner serves command output from fixture files named after the
+// tool and subcommand, e.g. "helm-history-api.json".
+func fixtureRunner(t *testing.T, fixtures map[string]string) (runner, *[]string) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range fixtures {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var calls []string
+	return func(_ context.Context, name string, args ...string) ([]byte, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		key := name
+		for i, a := range args {
+			switch {
+			case a == "config" || a == "list" || a == "events" || a == "get":
+				key += "-" + a
+			case a == "history":
+				key += "-history-" + args[i+1]
+			}
+		}
+		data, err := os.ReadFile(filepath.Join(dir, key+".json"))
+		if err != nil {
+			return nil, errors.New("failed to run " + name + ": no fixture " + key)
+		}
+		return data, nil
+	}, &calls
+}
+
+func newTestSource(run runner, tools ...string) *ContainerSource {
+	s := NewContainerSource("", map[string]string{"tools": strings.Join(tools, ",")})
+	s.run = run
+	s.lookPath = func(name string) (string, error) { return "/usr/bin/" + name, nil }
+	return s
+}
+
+func contents(t *testing.T, s *ContainerSource, from, to time.Time) []string {
+	t.Helper()
+	entries, err := s.GetEntries(t.Context(), from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for _, e := range entries {
+		out = append(out, e.Location+": "+e.Content)
+	}
+	return out
+}
+
+func TestContainerSource_Kubernetes(t *testing.T) {
+	run, calls := fixtureRunner(t, map[string]string{
+		"kubectl-config.json":      "prod-eu\n",
+		"kubectl-get.json":         kubectlFixture,
+		"helm-list.json":           helmListFixture,
+		"helm-history-api.json":    helmHistoryFixture,
+		"helm-history-legacy.json": `[]`,
+	})
+	source := newTestSource(run, ToolKubectl, ToolHelm)
+
+	day := time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)
+	got := contents(t, source, day, day.Add(24*time.Hour-time.Second))
+	want := []string{
+		"prod-eu: applied deployment/api in namespace prod",
+		"prod-eu: failed to deploy chart api-1.4.2 to namespace prod (release api, revision 12)",
+		"prod-eu: deployed chart api-1.4.2 to namespace prod (release api, revision 13)",
+		"prod-eu: rolled out deployment/api in namespace prod",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("entries:\n got %q\nwant %q", got, want)
+	}
+
+	for _, c := range *calls {
+		if strings.Contains(c, "history legacy") {
+			t.Error("history of a release not updated in range should not be fetched")
+		}
+	}
+}
+
+func TestContainerSource_RuntimeEvents(t *testing.T) {
+	run, calls := fixtureRunner(t, map[string]string{
+		"docker-events.json": dockerEventsFixture,
+		"podman-events.json": podmanEventsFixture,
+	})
+	source := newTestSource(run, ToolDocker, ToolPodman)
+
+	day := time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)
+	entries, err := source.GetEntries(t.Context(), day, day.Add(24*time.Hour-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Location+": "+e.Content)
+	}
+	want := []string{
+		"docker: pulled image nginx:1.27",
+		"docker: [shop] started container shop-web-1 (nginx:1.27)",
+		"docker: [shop] container shop-web-1 exited with code 137 (nginx:1.27)",
+		"podman: started container worker (quay.io/acme/worker:2)",
+		"podman: container worker exited with code 0 (quay.io/acme/worker:2)",
+		"podman: built image localhost/acme/worker:dev",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("entries:\n got %q\nwant %q", got, want)
+	}
+	if entries[2].Metadata["exit_code"] != "137" || entries[2].Metadata["project"] != "shop" {
+		t.Errorf("unexpected metadata: %+v", entries[2].Metadata)
+	}
+
+	if !slices.ContainsFunc(*calls, func(c string) bool { return strings.Contains(c, "--stream=false") }) {
+		t.Errorf("podman events should not stream: %q", *calls)
+	}
+}
+
+func TestContainerSource_EventsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docker-events.jsonl")
+	if err := os.WriteFile(path, []byte(dockerEventsFixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+	source := NewContainerSource("", map[string]string{"tools": ToolDocker, "events_file": path})
+	source.lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	if err := source.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := contents(t, source, time.Unix(1775553050, 0), time.Unix(1775560000, 0))
+	if len(got) != 2 || !strings.HasPrefix(got[0], path+": [shop] started") {
+		t.Errorf("unexpected entries: %q", got)
+	}
+}
+
+func TestContainerSource_ToolFailures(t *testing.T) {
+	run, _ := fixtureRunner(t, map[string]string{"docker-events.json": dockerEventsFixture})
+
+	// kubectl has no fixture and fails; docker results are still returned.
+	source := newTestSource(run, ToolKubectl, ToolDocker)
+	if got := contents(t, source, time.Unix(0, 0), time.Unix(1775560000, 0)); len(got) != 3 {
+		t.Errorf("expected docker entries despite kubectl failure, got %q", got)
+	}
+
+	source = newTestSource(run, ToolKubectl)
+	if _, err := source.GetEntries(t.Context(), time.Unix(0, 0), time.Now()); err == nil {
+		t.Error("expected error when every tool fails")
+	}
+}
+
+func TestContainerSource_Validate(t *testing.T) {
+	source := NewContainerSource("", map[string]string{"tools": "kubectl,nerdctl"})
+	if err := source.Validate(); err == nil || !strings.Contains(err.Error(), "nerdctl") {
+		t.Errorf("expected unknown tool error, got %v", err)
+	}
+
+	source = NewContainerSource("", map[string]string{"tools": ToolHelm})
+	source.lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	if err := source.Validate(); err == nil {
+		t.Error("expected error when no tool is installed")
+	}
+}
+
+func TestKubectlAction(t *testing.T) {
+	tests := []struct {
+		manager, operation, want string
+	}{
+		{"kubectl", "Apply", "applied"},
+		{"kubectl-client-side-apply", "Update", "applied"},
+		{"kubectl-scale", "Update", "scaled"},
+		{"kubectl-debug", "Update", "updated"},
+		{"helm", "Update", ""},
+		{"argocd-controller", "Apply", ""},
+	}
+	for _, tt := range tests {
+		if got := kubectlAction(tt.manager, tt.operation); got != tt.want {
+			t.Errorf("kubectlAction(%q, %q) = %q, want %q", tt.manager, tt.operation, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package containers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// kubeResources are the resource types whose changes are reported. Secrets
+// are left out on purpose.
+const kubeResources = "deployments,statefulsets,daemonsets,cronjobs,services,ingresses,configmaps"
+
+// helmListLayout is the format of "updated" in `helm list -o json`.
+const helmListLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// kubectlActions maps kubectl field managers to the verb reported for the
+// change. Server-side apply uses the plain "kubectl" manager.
+var kubectlActions = map[string]string{
+	"kubectl-client-side-apply": "applied",
+	"kubectl-create":            "created",
+	"kubectl-expose":            "created",
+	"kubectl-edit":              "edited",
+	"kubectl-patch":             "patched",
+	"kubectl-replace":           "replaced",
+	"kubectl-scale":             "scaled",
+	"kubectl-rollout":           "rolled out",
+	"kubectl-set":               "updated",
+	"kubectl-label":             "labeled",
+	"kubectl-annotate":          "annotated",
+}
+
+type kubeList struct {
+	Items []struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name          string `json:"name"`
+			Namespace     string `json:"namespace"`
+			ManagedFields []struct {
+				Manager   string    `json:"manager"`
+				Operation string    `json:"operation"`
+				Time      time.Time `json:"time"`
+			} `json:"managedFields"`
+		} `json:"metadata"`
+	} `json:"items"`
+}
+
+type helmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Updated   string `json:"updated"`
+}
+
+type helmRevision struct {
+	Revision    int       `json:"revision"`
+	Updated     time.Time `json:"updated"`
+	Status      string    `json:"status"`
+	Chart       string    `json:"chart"`
+	AppVersion  string    `json:"app_version"`
+	Description string    `json:"description"`
+}
+
+// kubeLocation names the cluster entries are grouped under: the configured
+// context, else kubectl's current context.
+func (s *ContainerSource) kubeLocation(ctx context.Context) string {
+	if s.kubeContext != "" {
+		return s.kubeContext
+	}
+	if s.currentContext == "" {
+		s.currentContext = "kubernetes"
+		args := append(s.kubeArgs(ToolKubectl), "config", "current-context")
+		if out, err := s.run(ctx, ToolKubectl, args...); err == nil {
+			if name := strings.TrimSpace(string(out)); name != "" {
+				s.currentContext = name
+			}
+		}
+	}
+	return s.currentContext
+}
+
+// kubectlEntries reports resources last changed through kubectl. The API
+// server records the time of each field manager's latest write, so only
+// the most recent change per resource and kubectl command is visible.
+func (s *ContainerSource) kubectlEntries(ctx context.Context) ([]sources.Entry, error) {
+	args := append(s.kubeArgs(ToolKubectl), "get", kubeResources, "--all-namespaces", "-o", "json")
+	out, err := s.run(ctx, ToolKubectl, args...)
+	if err != nil {
+		return nil, err
+	}
+	var list kubeList
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse kubectl output: %w", err)
+	}
+
+	location := s.kubeLocation(ctx)
+	var entries []sources.Entry
+	for _, item := range list.Items {
+		resource := strings.ToLower(item.Kind) + "/" + item.Metadata.Name
+		for _, mf := range item.Metadata.ManagedFields {
+			action := kubectlAction(mf.Manager, mf.Operation)
+			if action == "" || mf.Time.IsZero() {
+				continue
+			}
+			content := action + " " + resource
+			if item.Metadata.Namespace != "" {
+				content += " in namespace " + item.Metadata.Namespace
+			}
+			entries = append(entries, sources.Entry{
+				Timestamp: mf.Time,
+				Source:    "containers",
+				Location:  location,
+				Content:   content,
+				Metadata: map[string]string{
+					"tool":      ToolKubectl,
+					"action":    action,
+					"kind":      item.Kind,
+					"name":      item.Metadata.Name,
+					"namespace": item.Metadata.Namespace,
+					"manager":   mf.Manager,
+				},
+			})
+		}
+	}
+	return entries, nil
+}
+
+// kubectlAction returns the verb for a kubectl field manager, or "" for
+// changes made by controllers, operators and other clients.
+func kubectlAction(manager, operation string) string {
+	if manager == "kubectl" {
+		if operation == "Apply" {
+			return "applied"
+		}
+		return "updated"
+	}
+	if action, ok := kubectlActions[manager]; ok {
+		return action
+	}
+	if strings.HasPrefix(manager, "kubectl-") {
+		return "updated"
+	}
+	return ""
+}
+
+// helmEntries reports Helm release revisions. Releases not updated since
+// from are skipped without fetching their history.
+func (s *ContainerSource) helmEntries(ctx context.Context, from time.Time) ([]sources.Entry, error) {
+	kubeArgs := s.kubeArgs(ToolHelm)
+	out, err := s.run(ctx, ToolHelm, slices.Concat(kubeArgs, []string{"list", "--all-namespaces", "--all", "-o", "json"})...)
+	if err != nil {
+		return nil, err
+	}
+	var releases []helmRelease
+	if err := json.Unmarshal(out, &releases); err != nil {
+		return nil, fmt.Errorf("failed to parse helm list output: %w", err)
+	}
+
+	location := s.kubeLocation(ctx)
+	var entries []sources.Entry
+	for _, rel := range releases {
+		if updated, err := time.Parse(helmListLayout, rel.Updated); err == nil && updated.Before(from) {
+			continue
+		}
+		args := slices.Concat(kubeArgs, []string{"history", rel.Name, "--namespace", rel.Namespace, "-o", "json"})
+		out, err := s.run(ctx, ToolHelm, args...)
+		if err != nil {
+			return nil, err
+		}
+		var history []helmRevision
+		if err := json.Unmarshal(out, &history); err != nil {
+			return nil, fmt.Errorf("failed to parse helm history of %s: %w", rel.Name, err)
+		}
+		for _, rev := range history {
+			if content := helmContent(rel, rev); content != "" {
+				entries = append(entries, sources.Entry{
+					Timestamp: rev.Updated,
+					Source:    "containers",
+					Location:  location,
+					Content:   content,
+					Metadata: map[string]string{
+						"tool":        ToolHelm,
+						"release":     rel.Name,
+						"namespace":   rel.Namespace,
+						"revision":    strconv.Itoa(rev.Revision),
+						"chart":       rev.Chart,
+						"app_version": rev.AppVersion,
+						"status":      rev.Status,
+					},
+				})
+			}
+		}
+	}
+	return entries, nil
+}
+
+// helmContent describes a release revision, or returns "" for revisions
+// still pending.
+func helmContent(rel helmRelease, rev helmRevision) string {
+	detail := fmt.Sprintf("(release %s, revision %d)", rel.Name, rev.Revision)
+	switch {
+	case strings.HasPrefix(rev.Status, "pending"):
+		return ""
+	case rev.Status == "uninstalled" || rev.Status == "uninstalling":
+		return fmt.Sprintf("uninstalled chart %s from namespace %s %s", rev.Chart, rel.Namespace, detail)
+	case rev.Status == "failed":
+		return fmt.Sprintf("failed to deploy chart %s to namespace %s %s", rev.Chart, rel.Namespace, detail)
+	case strings.HasPrefix(rev.Description, "Rollback to "):
+		target := strings.TrimPrefix(rev.Description, "Rollback to ")
+		return fmt.Sprintf("rolled back chart %s in namespace %s to revision %s %s", rev.Chart, rel.Namespace, target, detail)
+	default:
+		return fmt.Sprintf("deployed chart %s to namespace %s %s", rev.Chart, rel.Namespace, detail)
+	}
+}
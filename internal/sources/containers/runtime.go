@@ -0,0 +1,226 @@
+package containers
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// runtimeActions lists the Docker/Podman events worth reporting, by object
+// type. Exec, attach, health and network events are noise.
+var runtimeActions = map[string]map[string]bool{
+	"container": {"start": true, "die": true, "restart": true},
+	"image":     {"pull": true, "push": true, "build": true, "tag": true},
+}
+
+// runtimeEvent is a container runtime event, normalized across Docker's
+// and Podman's JSON formats.
+type runtimeEvent struct {
+	Time     time.Time
+	Type     string
+	Action   string
+	Name     string
+	Image    string
+	ExitCode string
+	Project  string // docker compose project
+}
+
+// runtimeEvents runs `docker events` or `podman events` for the range.
+// The end is capped at now, since both tools wait for future events.
+func (s *ContainerSource) runtimeEvents(ctx context.Context, tool string, from, to time.Time) ([]sources.Entry, error) {
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+	args := []string{"events", "--since", from.Format(time.RFC3339), "--until", to.Format(time.RFC3339)}
+	if tool == ToolPodman {
+		args = append(args, "--stream=false", "--format", "json")
+	} else {
+		args = append(args, "--format", "{{json .}}")
+	}
+
+	out, err := s.run(ctx, tool, args...)
+	if err != nil {
+		return nil, err
+	}
+	events, err := parseEvents(bytes.NewReader(out))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s events: %w", tool, err)
+	}
+	return eventEntries(events, tool, tool), nil
+}
+
+// eventsFileEntries reads a log captured with
+// `docker events --format '{{json .}}' >> FILE`.
+func (s *ContainerSource) eventsFileEntries() ([]sources.Entry, error) {
+	f, err := os.Open(s.eventsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open events file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	events, err := parseEvents(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse events file: %w", err)
+	}
+	return eventEntries(events, ToolDocker, s.eventsFile), nil
+}
+
+// parseEvents reads JSON events, one per line, keeping the reportable
+// ones. Lines that are not JSON objects are skipped.
+func parseEvents(r io.Reader) ([]runtimeEvent, error) {
+	var events []runtimeEvent
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		if e, ok := parseEvent(line); ok && runtimeActions[e.Type][e.Action] {
+			events = append(events, e)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// parseEvent decodes a Docker event ("Type", "Action", "Actor", "time",
+// "timeNano") or a Podman event ("Type", "Status", "Name", "Image",
+// "Time").
+func parseEvent(line []byte) (runtimeEvent, bool) {
+	var raw struct {
+		Type     string          `json:"Type"`
+		Action   string          `json:"Action"`
+		Status   string          `json:"Status"`
+		Name     string          `json:"Name"`
+		Image    string          `json:"Image"`
+		ExitCode *int            `json:"ContainerExitCode"`
+		TimeNano int64           `json:"timeNano"`
+		Time     json.RawMessage `json:"time"`
+		Actor    struct {
+			ID         string            `json:"ID"`
+			Attributes map[string]string `json:"Attributes"`
+		} `json:"Actor"`
+		Attributes map[string]string `json:"Attributes"`
+	}
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return runtimeEvent{}, false
+	}
+
+	e := runtimeEvent{Type: raw.Type, Action: raw.Action, Name: raw.Name, Image: raw.Image}
+	if e.Action == "" {
+		e.Action = raw.Status
+	}
+	if e.Action == "died" { // podman
+		e.Action = "die"
+	}
+	attrs := raw.Actor.Attributes
+	if attrs == nil {
+		attrs = raw.Attributes
+	}
+	if e.Name == "" {
+		e.Name = attrs["name"]
+	}
+	if e.Image == "" {
+		e.Image = attrs["image"]
+	}
+	if e.Type == "image" && e.Image == "" {
+		// Docker identifies the image by reference, or by digest for tag
+		// events, where the new reference is the name attribute.
+		e.Image = raw.Actor.ID
+		if e.Image == "" || strings.HasPrefix(e.Image, "sha256:") {
+			e.Image = e.Name
+		}
+	}
+	e.Project = attrs["com.docker.compose.project"]
+	if code := attrs["exitCode"]; code != "" {
+		e.ExitCode = code
+	} else if raw.ExitCode != nil {
+		e.ExitCode = strconv.Itoa(*raw.ExitCode)
+	}
+
+	switch {
+	case raw.TimeNano > 0:
+		e.Time = time.Unix(0, raw.TimeNano)
+	case len(raw.Time) > 0:
+		e.Time = parseEventTime(raw.Time)
+	}
+	return e, !e.Time.IsZero()
+}
+
+// parseEventTime accepts Unix seconds (Docker) or an RFC 3339 string
+// (Podman).
+func parseEventTime(raw json.RawMessage) time.Time {
+	var sec int64
+	if err := json.Unmarshal(raw, &sec); err == nil {
+		return time.Unix(sec, 0)
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func eventEntries(events []runtimeEvent, tool, location string) []sources.Entry {
+	entries := make([]sources.Entry, 0, len(events))
+	for _, e := range events {
+		content := eventContent(e)
+		if e.Project != "" {
+			content = "[" + e.Project + "] " + content
+		}
+		metadata := map[string]string{
+			"tool":   tool,
+			"type":   e.Type,
+			"action": e.Action,
+			"image":  e.Image,
+		}
+		if e.Type == "container" {
+			metadata["container"] = e.Name
+		}
+		if e.ExitCode != "" {
+			metadata["exit_code"] = e.ExitCode
+		}
+		if e.Project != "" {
+			metadata["project"] = e.Project
+		}
+		entries = append(entries, sources.Entry{
+			Timestamp: e.Time,
+			Source:    "containers",
+			Location:  location,
+			Content:   content,
+			Metadata:  metadata,
+		})
+	}
+	return entries
+}
+
+func eventContent(e runtimeEvent) string {
+	switch e.Type + " " + e.Action {
+	case "container start":
+		return fmt.Sprintf("started container %s (%s)", e.Name, e.Image)
+	case "container restart":
+		return fmt.Sprintf("restarted container %s (%s)", e.Name, e.Image)
+	case "container die":
+		return fmt.Sprintf("container %s exited with code %s (%s)", e.Name, cmp.Or(e.ExitCode, "?"), e.Image)
+	case "image pull":
+		return "pulled image " + e.Image
+	case "image push":
+		return "pushed image " + e.Image
+	case "image build":
+		return "built image " + e.Image
+	default:
+		return "tagged image " + e.Image
+	}
+}
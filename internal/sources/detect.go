@@ -18,6 +18,7 @@ type DetectedSource struct {
 // DetectType inspects path and returns matching source types using priority rules:
 //   - .git/ present: only git (plus claude if applicable), obsidian and markdown skipped
 //   - .obsidian/ present: only obsidian (plus claude if applicable), markdown skipped
+//   - logseq/ and journals/ present: only logseq (plus claude if applicable), markdown skipped
 //   - claude (.claude/projects/ child or path under ~/.claude): only claude, markdown skipped
 //   - markdown: only when none of the above match
 //
@@ -34,15 +35,19 @@ func DetectType(path string) ([]DetectedSource, error) {
 	hasBareGit := !hasDotGit && git.IsBareGitRepo(abs)
 	hasGit := hasDotGit || hasBareGit
 	hasObsidian := isDir(filepath.Join(abs, ".obsidian"))
+	hasLogseq := !hasObsidian && isDir(filepath.Join(abs, "logseq")) && isDir(filepath.Join(abs, "journals"))
 	hasClaude := isClaudePath(abs)
 
-	// git takes highest priority; obsidian is next. They are mutually exclusive.
+	// git takes highest priority; obsidian and logseq are next. They are
+	// mutually exclusive.
 	if hasDotGit {
 		results = append(results, DetectedSource{Path: abs, Type: "git", Reason: "found .git/"})
 	} else if hasBareGit {
 		results = append(results, DetectedSource{Path: abs, Type: "git", Reason: "found bare git repository"})
 	} else if hasObsidian {
 		results = append(results, DetectedSource{Path: abs, Type: "obsidian", Reason: "found .obsidian/"})
+	} else if hasLogseq {
+		results = append(results, DetectedSource{Path: abs, Type: "logseq", Reason: "found logseq/ and journals/"})
 	}
 
 	// claude is independent of git/obsidian but blocks markdown.
@@ -50,8 +55,8 @@ func DetectType(path string) ([]DetectedSource, error) {
 		results = append(results, DetectedSource{Path: abs, Type: "claude", Reason: "found .claude/projects/"})
 	}
 
-	// markdown only when no git, obsidian, or claude source was found.
-	if !hasGit && !hasObsidian && !hasClaude && hasMDFiles(abs) {
+	// markdown only when no git, obsidian, logseq, or claude source was found.
+	if !hasGit && !hasObsidian && !hasLogseq && !hasClaude && hasMDFiles(abs) {
 		results = append(results, DetectedSource{Path: abs, Type: "markdown", Reason: "found .md files"})
 	}
 
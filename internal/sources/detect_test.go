@@ -77,6 +77,15 @@ func TestDetectType(t *testing.T) {
 			},
 			wantTypes: []string{"git"},
 		},
+		{
+			name: "logseq graph with markdown files -- no markdown type",
+			setup: func(t *testing.T, dir string) {
+				mkDir(t, dir, "logseq")
+				mkDir(t, dir, "journals")
+				mkFile(t, dir, "notes.md")
+			},
+			wantTypes: []string{"logseq"},
+		},
 		{
 			name:          "empty directory -- no match",
 			setup:         func(t *testing.T, dir string) {},
@@ -0,0 +1,256 @@
+package editor
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// burstGap is the longest pause between two saves of the same file that
+// still counts as one editing burst.
+const burstGap = 15 * time.Minute
+
+// Editor names used in metadata.
+const (
+	EditorVSCode    = "vscode"
+	EditorJetBrains = "jetbrains"
+	EditorVim       = "vim"
+)
+
+// projectMarkers identify a project root when walking up from an edited file.
+var projectMarkers = []string{".git", "go.mod", "package.json", "Cargo.toml", "pyproject.toml", "flake.nix", ".terraform"}
+
+// edit is a single recorded save or change of a file.
+type edit struct {
+	Path   string
+	Time   time.Time
+	Editor string
+}
+
+// historyDir is an editor history location found under the source root.
+type historyDir struct {
+	Editor string
+	Path   string
+}
+
+// EditorSource implements the Source interface for editor local history:
+// VS Code's User/History, JetBrains LocalHistory and Vim/Neovim persistent
+// undo directories. Saves of the same file close together are reported as
+// one editing burst, attributed to the file's project.
+type EditorSource struct {
+	root string
+	now  func() time.Time
+}
+
+// NewEditorSource creates a source rooted at root: either a home directory,
+// under which all known editor history locations are searched, or a single
+// history directory.
+func NewEditorSource(root string) *EditorSource {
+	return &EditorSource{root: root, now: time.Now}
+}
+
+func (s *EditorSource) Type() string {
+	return "editor"
+}
+
+func (s *EditorSource) Location() string {
+	return s.root
+}
+
+func (s *EditorSource) Validate() error {
+	if _, err := os.Stat(s.root); err != nil {
+		return fmt.Errorf("path not accessible: %w", err)
+	}
+	if len(s.historyDirs()) == 0 {
+		return fmt.Errorf("no VS Code, JetBrains or Vim history found under %s", s.root)
+	}
+	return nil
+}
+
+func (s *EditorSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	var edits []edit
+	for _, dir := range s.historyDirs() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var found []edit
+		var err error
+		switch dir.Editor {
+		case EditorVSCode:
+			found, err = vscodeEdits(dir.Path, from)
+		case EditorJetBrains:
+			found, err = jetbrainsEdits(dir.Path, from, s.now())
+		case EditorVim:
+			found, err = vimEdits(dir.Path, from, s.now())
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range found {
+			if !e.Time.Before(from) && !e.Time.After(to) {
+				edits = append(edits, e)
+			}
+		}
+	}
+
+	roots := make(map[string]string) // dir -> project root, cached per run
+	var entries []sources.Entry
+	for _, b := range groupBursts(edits) {
+		entries = append(entries, s.burstEntry(b, roots))
+	}
+	slices.SortFunc(entries, func(a, b sources.Entry) int { return a.Timestamp.Compare(b.Timestamp) })
+	return entries, nil
+}
+
+// historyDirs returns the editor history directories to read. A root that
+// is itself a history directory is used as is.
+func (s *EditorSource) historyDirs() []historyDir {
+	if editor := classifyDir(s.root); editor != "" {
+		return []historyDir{{Editor: editor, Path: s.root}}
+	}
+
+	var dirs []historyDir
+	add := func(editor string, pattern string) {
+		matches, _ := filepath.Glob(filepath.Join(s.root, pattern))
+		for _, m := range matches {
+			if isDir(m) {
+				dirs = append(dirs, historyDir{Editor: editor, Path: m})
+			}
+		}
+	}
+
+	for _, app := range []string{"Code", "Code - Insiders", "VSCodium", "Cursor"} {
+		add(EditorVSCode, filepath.Join(".config", app, "User", "History"))
+		add(EditorVSCode, filepath.Join("Library", "Application Support", app, "User", "History"))
+		add(EditorVSCode, filepath.Join("AppData", "Roaming", app, "User", "History"))
+	}
+	add(EditorJetBrains, filepath.Join(".cache", "JetBrains", "*", "LocalHistory"))
+	add(EditorJetBrains, filepath.Join("Library", "Caches", "JetBrains", "*", "LocalHistory"))
+	add(EditorJetBrains, filepath.Join("AppData", "Local", "JetBrains", "*", "LocalHistory"))
+	add(EditorVim, filepath.Join(".vim", "undo"))
+	add(EditorVim, filepath.Join(".vim", "undodir"))
+	add(EditorVim, filepath.Join(".local", "state", "nvim", "undo"))
+	add(EditorVim, filepath.Join(".local", "share", "nvim", "undo"))
+
+	return dirs
+}
+
+// classifyDir recognizes a directory that is itself an editor history
+// location, returning the editor name or "".
+func classifyDir(dir string) string {
+	switch {
+	case filepath.Base(dir) == "History" && filepath.Base(filepath.Dir(dir)) == "User":
+		return EditorVSCode
+	case fileExists(filepath.Join(dir, jetbrainsStorageFile)):
+		return EditorJetBrains
+	case filepath.Base(dir) == "undo" || filepath.Base(dir) == "undodir":
+		return EditorVim
+	}
+	return ""
+}
+
+// burst is a run of edits to one file with no pause longer than burstGap.
+type burst struct {
+	Path   string
+	Editor string
+	Start  time.Time
+	End    time.Time
+	Edits  int
+}
+
+func groupBursts(edits []edit) []burst {
+	slices.SortFunc(edits, func(a, b edit) int {
+		return cmp.Or(cmp.Compare(a.Path, b.Path), a.Time.Compare(b.Time))
+	})
+
+	var bursts []burst
+	for _, e := range edits {
+		if n := len(bursts); n > 0 {
+			last := &bursts[n-1]
+			if last.Path == e.Path && e.Time.Sub(last.End) <= burstGap {
+				if e.Time.After(last.End) {
+					last.End = e.Time
+				}
+				last.Edits++
+				continue
+			}
+		}
+		bursts = append(bursts, burst{Path: e.Path, Editor: e.Editor, Start: e.Time, End: e.Time, Edits: 1})
+	}
+	return bursts
+}
+
+func (s *EditorSource) burstEntry(b burst, roots map[string]string) sources.Entry {
+	root := projectRoot(filepath.Dir(b.Path), roots)
+	project := filepath.Base(root)
+	rel, err := filepath.Rel(root, b.Path)
+	if err != nil {
+		rel = b.Path
+	}
+
+	minutes := int(b.End.Sub(b.Start).Round(time.Minute).Minutes())
+	content := fmt.Sprintf("[%s] edited %s", project, rel)
+	if b.Edits > 1 {
+		content += fmt.Sprintf(" (%d saves, %s-%s)", b.Edits, b.Start.Format("15:04"), b.End.Format("15:04"))
+	}
+
+	return sources.Entry{
+		Timestamp: b.Start,
+		Source:    "editor",
+		Location:  s.root,
+		Content:   content,
+		Metadata: map[string]string{
+			"editor":           b.Editor,
+			"file":             b.Path,
+			"relative_path":    rel,
+			"project":          project,
+			"project_root":     root,
+			"edits":            strconv.Itoa(b.Edits),
+			"duration_minutes": strconv.Itoa(minutes),
+			"start":            b.Start.Format(time.RFC3339),
+			"end":              b.End.Format(time.RFC3339),
+		},
+	}
+}
+
+// projectRoot walks up from dir to the nearest directory containing a
+// project marker. Without one, dir itself is the project.
+func projectRoot(dir string, cache map[string]string) string {
+	if root, ok := cache[dir]; ok {
+		return root
+	}
+
+	root := dir
+	for d := dir; ; d = filepath.Dir(d) {
+		found := slices.ContainsFunc(projectMarkers, func(m string) bool {
+			return fileExists(filepath.Join(d, m))
+		})
+		if found {
+			root = d
+			break
+		}
+		if parent := filepath.Dir(d); parent == d {
+			break
+		}
+	}
+
+	cache[dir] = root
+	return root
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
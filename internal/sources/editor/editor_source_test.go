@@ -0,0 +1,151 @@
+package editor
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// vimUndoFile builds a minimal undo file with one header per change time.
+func vimUndoFile(times ...time.Time) []byte {
+	data := append([]byte{}, vimUndoMagic...)
+	data = append(data, make([]byte, 40)...) // file header fields
+	for _, ts := range times {
+		header := make([]byte, vimTimeOffset+8)
+		copy(header, vimHeaderMagic)
+		binary.BigEndian.PutUint64(header[vimTimeOffset:], uint64(ts.Unix()))
+		data = append(data, header...)
+	}
+	return data
+}
+
+// jetbrainsChange builds a change record: a millisecond timestamp followed
+// by a length-prefixed path.
+func jetbrainsChange(ts time.Time, path string) []byte {
+	data := make([]byte, 8, 8+16+2+len(path))
+	binary.BigEndian.PutUint64(data, uint64(ts.UnixMilli()))
+	data = append(data, make([]byte, 16)...)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(path)))
+	return append(data, path...)
+}
+
+func TestEditorSource_GetEntries(t *testing.T) {
+	home := t.TempDir()
+	project := filepath.Join(home, "src", "ikno")
+	if err := os.MkdirAll(filepath.Join(project, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	mainGo := filepath.Join(project, "cmd", "main.go")
+	notes := filepath.Join(project, "notes.md")
+	readme := filepath.Join(project, "README.md")
+
+	day := time.Date(2026, 4, 7, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	ms := func(ts time.Time) string { return strconv.FormatInt(ts.UnixMilli(), 10) }
+
+	writeFile(t, filepath.Join(home, ".config", "Code", "User", "History", "-5f2a", "entries.json"), []byte(`{
+  "version": 1,
+  "resource": "file://`+mainGo+`",
+  "entries": [
+    {"id": "a1.go", "timestamp": `+ms(at(10, 0))+`},
+    {"id": "a2.go", "source": "Format", "timestamp": `+ms(at(10, 5))+`},
+    {"id": "a3.go", "timestamp": `+ms(at(10, 40))+`},
+    {"id": "a0.go", "timestamp": `+ms(at(-20, 0))+`}
+  ]
+}`))
+	writeFile(t, filepath.Join(home, ".config", "Code", "User", "History", "7c01", "entries.json"),
+		[]byte(`{"resource": "untitled:Untitled-1", "entries": [{"id": "u.txt", "timestamp": `+ms(at(11, 0))+`}]}`))
+
+	undoName := strings.ReplaceAll(filepath.ToSlash(notes), "/", "%")
+	writeFile(t, filepath.Join(home, ".local", "state", "nvim", "undo", undoName), vimUndoFile(at(14, 0), at(14, 10)))
+
+	writeFile(t, filepath.Join(home, ".cache", "JetBrains", "GoLand2025.1", "LocalHistory", jetbrainsStorageFile),
+		jetbrainsChange(at(16, 30), filepath.ToSlash(readme)))
+
+	source := NewEditorSource(home)
+	source.now = func() time.Time { return at(18, 0) }
+	if err := source.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := source.GetEntries(t.Context(), day, day.Add(24*time.Hour-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d: %+v", len(entries), entries)
+	}
+
+	first := entries[0]
+	if first.Content != "[ikno] edited "+filepath.Join("cmd", "main.go")+" (2 saves, 10:00-10:05)" {
+		t.Errorf("content = %q", first.Content)
+	}
+	if first.Metadata["project_root"] != project || first.Metadata["editor"] != EditorVSCode || first.Metadata["duration_minutes"] != "5" {
+		t.Errorf("unexpected metadata: %+v", first.Metadata)
+	}
+	if !entries[1].Timestamp.Equal(at(10, 40)) || entries[1].Metadata["edits"] != "1" {
+		t.Errorf("expected separate burst after a long pause, got %+v", entries[1])
+	}
+	if entries[2].Metadata["editor"] != EditorVim || entries[2].Metadata["relative_path"] != "notes.md" || entries[2].Metadata["edits"] != "2" {
+		t.Errorf("unexpected vim entry: %+v", entries[2])
+	}
+	if entries[3].Metadata["editor"] != EditorJetBrains || !entries[3].Timestamp.Equal(at(16, 30)) {
+		t.Errorf("unexpected jetbrains entry: %+v", entries[3])
+	}
+}
+
+func TestEditorSource_HistoryDirAsRoot(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Code", "User", "History")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewEditorSource(dir)
+	dirs := source.historyDirs()
+	if len(dirs) != 1 || dirs[0].Editor != EditorVSCode {
+		t.Errorf("expected root to be used as VS Code history, got %+v", dirs)
+	}
+}
+
+func TestEditorSource_ValidateNoHistory(t *testing.T) {
+	if err := NewEditorSource(t.TempDir()).Validate(); err == nil {
+		t.Error("expected error when no editor history exists")
+	}
+}
+
+func TestParseVimUndoTimes(t *testing.T) {
+	now := time.Date(2026, 4, 7, 12, 0, 0, 0, time.UTC)
+	ts := now.Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"two changes", vimUndoFile(ts, ts.Add(time.Minute)), 2},
+		{"duplicate times", vimUndoFile(ts, ts), 1},
+		{"future time", vimUndoFile(now.Add(72 * time.Hour)), 0},
+		{"not an undo file", []byte("plain text"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseVimUndoTimes(tt.data, now); len(got) != tt.want {
+				t.Errorf("got %d times, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package editor
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// jetbrainsStorageFile holds the change sets of a JetBrains IDE's local
+// history.
+const jetbrainsStorageFile = "changes.storageData"
+
+// jetbrainsLookback is how far before a path string we search for the
+// change set timestamp it belongs to.
+const jetbrainsLookback = 512
+
+// jetbrainsEdits extracts edited files from JetBrains local history.
+//
+// The storage format is internal to the IDE and undocumented. Change sets
+// are written with Java's DataOutputStream: file paths as length-prefixed
+// UTF strings, timestamps as big-endian millisecond longs. We scan for
+// absolute paths and pair each with the nearest plausible timestamp before
+// it. This is best effort; unrecognised data is skipped.
+func jetbrainsEdits(dir string, from, now time.Time) ([]edit, error) {
+	path := filepath.Join(dir, jetbrainsStorageFile)
+	info, err := os.Stat(path)
+	if err != nil || info.ModTime().Before(from) {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil
+	}
+
+	var edits []edit
+	seen := make(map[string]bool)
+	for _, c := range scanJetBrains(data, now) {
+		key := c.Path + "|" + c.Time.String()
+		if !seen[key] {
+			seen[key] = true
+			edits = append(edits, c)
+		}
+	}
+	return edits, nil
+}
+
+func scanJetBrains(data []byte, now time.Time) []edit {
+	var edits []edit
+	for i := 2; i < len(data); i++ {
+		if data[i] != '/' {
+			continue
+		}
+		n := int(binary.BigEndian.Uint16(data[i-2:]))
+		if n < 2 || n > 4096 || i+n > len(data) {
+			continue
+		}
+		s := string(data[i : i+n])
+		if !isFilePath(s) {
+			continue
+		}
+		ts, ok := timestampBefore(data, i-2, now)
+		if !ok {
+			continue
+		}
+		edits = append(edits, edit{Path: filepath.FromSlash(s), Time: ts, Editor: EditorJetBrains})
+		i += n - 1
+	}
+	return edits
+}
+
+// isFilePath accepts absolute paths to files: valid UTF-8, no control
+// characters, with a file extension in the last component.
+func isFilePath(s string) bool {
+	if !utf8.ValidString(s) || strings.ContainsFunc(s, func(r rune) bool { return r < 0x20 }) {
+		return false
+	}
+	base := s[strings.LastIndexByte(s, '/')+1:]
+	return strings.Contains(base, ".") && !strings.HasPrefix(base, ".")
+}
+
+// timestampBefore returns the nearest big-endian millisecond timestamp in
+// the bytes preceding end that falls in a plausible range.
+func timestampBefore(data []byte, end int, now time.Time) (time.Time, bool) {
+	start := max(end-jetbrainsLookback, 0)
+	for i := end - 8; i >= start; i-- {
+		ms := int64(binary.BigEndian.Uint64(data[i:]))
+		t := time.UnixMilli(ms)
+		if ms > 0 && plausibleTime(t, now) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,94 @@
+package editor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// vimUndoMagic starts every Vim and Neovim persistent undo file.
+var vimUndoMagic = []byte("Vim\x9fUnDo\xe5")
+
+// vimHeaderMagic starts each serialized undo header (UF_HEADER_MAGIC).
+var vimHeaderMagic = []byte{0x5f, 0xd0}
+
+// vimTimeOffset is the position of the 8-byte save time within a serialized
+// undo header: magic (2), four header pointers (16), seq (4), cursor (12),
+// cursor_vcol (4), flags (2), 26 named marks (312) and visual info (32).
+const vimTimeOffset = 384
+
+// vimEdits reads the undo files in an undodir. Vim names them after the
+// edited file with every path separator replaced by '%'. Each undo header
+// carries the time of the change; files whose headers cannot be read fall
+// back to their modification time.
+func vimEdits(undoDir string, from, now time.Time) ([]edit, error) {
+	files, err := os.ReadDir(undoDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read undo directory: %w", err)
+	}
+
+	var edits []edit
+	for _, f := range files {
+		if f.IsDir() || !strings.HasPrefix(f.Name(), "%") {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil || info.ModTime().Before(from) {
+			continue
+		}
+
+		path := filepath.FromSlash(strings.ReplaceAll(f.Name(), "%", "/"))
+		data, err := os.ReadFile(filepath.Join(undoDir, f.Name()))
+		if err != nil {
+			continue
+		}
+
+		times := parseVimUndoTimes(data, now)
+		if len(times) == 0 {
+			times = []time.Time{info.ModTime()}
+		}
+		for _, t := range times {
+			edits = append(edits, edit{Path: path, Time: t, Editor: EditorVim})
+		}
+	}
+	return edits, nil
+}
+
+// parseVimUndoTimes extracts the change times from an undo file. Header
+// magic bytes can also occur in saved text, so only plausible timestamps
+// are kept.
+func parseVimUndoTimes(data []byte, now time.Time) []time.Time {
+	if !bytes.HasPrefix(data, vimUndoMagic) {
+		return nil
+	}
+
+	var times []time.Time
+	seen := make(map[int64]bool)
+	for i := len(vimUndoMagic); ; {
+		idx := bytes.Index(data[i:], vimHeaderMagic)
+		if idx < 0 {
+			break
+		}
+		pos := i + idx
+		i = pos + len(vimHeaderMagic)
+
+		if pos+vimTimeOffset+8 > len(data) {
+			continue
+		}
+		sec := int64(binary.BigEndian.Uint64(data[pos+vimTimeOffset:]))
+		if plausibleTime(time.Unix(sec, 0), now) && !seen[sec] {
+			seen[sec] = true
+			times = append(times, time.Unix(sec, 0))
+		}
+	}
+	return times
+}
+
+// plausibleTime rejects values that cannot be real edit times.
+func plausibleTime(t, now time.Time) bool {
+	return t.Year() >= 2000 && !t.After(now.Add(24*time.Hour))
+}
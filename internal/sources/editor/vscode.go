@@ -0,0 +1,62 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// vscodeIndex mirrors User/History/<hash>/entries.json. Timestamps are
+// Unix milliseconds.
+type vscodeIndex struct {
+	Resource string `json:"resource"`
+	Entries  []struct {
+		ID        string `json:"id"`
+		Source    string `json:"source"`
+		Timestamp int64  `json:"timestamp"`
+	} `json:"entries"`
+}
+
+// vscodeEdits reads the local history index of every file. Directories not
+// written to since from are skipped without being read.
+func vscodeEdits(historyDir string, from time.Time) ([]edit, error) {
+	dirs, err := os.ReadDir(historyDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read VS Code history: %w", err)
+	}
+
+	var edits []edit
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		indexPath := filepath.Join(historyDir, d.Name(), "entries.json")
+		info, err := os.Stat(indexPath)
+		if err != nil || info.ModTime().Before(from) {
+			continue
+		}
+
+		data, err := os.ReadFile(indexPath)
+		if err != nil {
+			continue
+		}
+		var index vscodeIndex
+		if err := json.Unmarshal(data, &index); err != nil {
+			continue
+		}
+
+		u, err := url.Parse(index.Resource)
+		if err != nil || u.Scheme != "file" {
+			continue // untitled buffers, remote workspaces
+		}
+		path := filepath.FromSlash(u.Path)
+
+		for _, e := range index.Entries {
+			edits = append(edits, edit{Path: path, Time: time.UnixMilli(e.Timestamp), Editor: EditorVSCode})
+		}
+	}
+	return edits, nil
+}
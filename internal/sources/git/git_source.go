@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"slices"
@@ -51,14 +52,14 @@ func (g *GitSource) Validate() error {
 	return nil
 }
 
-func (g *GitSource) GetEntries(from, to time.Time) ([]sources.Entry, error) {
+func (g *GitSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
 	// Format: %H|%an|%ae|%at|%s
 	// Hash|Author Name|Author Email|Timestamp|Subject
 	format := "--pretty=format:%H|%an|%ae|%at|%s"
 	since := fmt.Sprintf("--since=%s", from.Format(time.RFC3339))
 	until := fmt.Sprintf("--until=%s", to.Format(time.RFC3339))
 
-	cmd := exec.Command("git", "-C", g.repoPath, "log", format, since, until, "--all")
+	cmd := exec.CommandContext(ctx, "git", "-C", g.repoPath, "log", format, since, until, "--all")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get git log: %w", err)
@@ -118,8 +119,8 @@ func parseUnixTimestamp(s string) (time.Time, error) {
 
 // GetDiff retrieves the full diff for a commit hash.
 // Returns the diff as a string, or empty string if the diff cannot be retrieved.
-func (g *GitSource) GetDiff(commitHash string) (string, error) {
-	cmd := exec.Command("git", "-C", g.repoPath, "show", "--format=", "--no-color", commitHash)
+func (g *GitSource) GetDiff(ctx context.Context, commitHash string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", g.repoPath, "show", "--format=", "--no-color", commitHash)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get diff for %s: %w", commitHash, err)
@@ -129,10 +130,14 @@ func (g *GitSource) GetDiff(commitHash string) (string, error) {
 
 // EnrichWithDiffs adds diff information to entries that have a commit hash in metadata.
 // Modifies entries in place by adding a "diff" field to their Metadata.
-func (g *GitSource) EnrichWithDiffs(entries []sources.Entry) error {
+// It stops with ctx's error when ctx is done.
+func (g *GitSource) EnrichWithDiffs(ctx context.Context, entries []sources.Entry) error {
 	for i := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if hash, ok := entries[i].Metadata["hash"]; ok {
-			diff, err := g.GetDiff(hash)
+			diff, err := g.GetDiff(ctx, hash)
 			if err != nil {
 				// Log warning but don't fail - continue with other entries
 				continue
@@ -142,7 +142,7 @@ func TestGitSource_GetEntries(t *testing.T) {
 	now := time.Now()
 	yesterday := now.Add(-24 * time.Hour)
 
-	entries, err := source.GetEntries(yesterday, now)
+	entries, err := source.GetEntries(t.Context(), yesterday, now)
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -194,7 +194,7 @@ func TestGitSource_GetEntries_TimeRange(t *testing.T) {
 	now := time.Now()
 	yesterday := now.Add(-24 * time.Hour)
 
-	entries, err := source.GetEntries(yesterday, now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), yesterday, now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -218,7 +218,7 @@ func TestGitSource_GetEntries_NoCommits(t *testing.T) {
 	now := time.Now()
 	yesterday := now.Add(-24 * time.Hour)
 
-	entries, err := source.GetEntries(yesterday, now)
+	entries, err := source.GetEntries(t.Context(), yesterday, now)
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -277,7 +277,7 @@ func TestGitSource_GetEntries_MultipleAuthors(t *testing.T) {
 
 	// Test filtering by multiple authors (comma-separated)
 	source := NewGitSource(repoPath, "test@example.com, third@example.com")
-	entries, err := source.GetEntries(yesterday, now)
+	entries, err := source.GetEntries(t.Context(), yesterday, now)
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -0,0 +1,275 @@
+package gitbug
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// Operation types of git-bug's operation packs.
+const (
+	opCreate      = 1
+	opSetTitle    = 2
+	opAddComment  = 3
+	opSetStatus   = 4
+	opLabelChange = 5
+)
+
+// statusClosed is the SetStatus value for a closed bug.
+const statusClosed = 2
+
+const (
+	bugRefPrefix   = "refs/bugs/"
+	identityPrefix = "refs/identities/"
+)
+
+// Event kinds emitted per operation.
+const (
+	KindCreated   = "created"
+	KindCommented = "commented"
+	KindClosed    = "closed"
+	KindReopened  = "reopened"
+	KindRetitled  = "retitled"
+	KindLabeled   = "labeled"
+)
+
+// GitBugSource implements the Source interface for issues that git-bug
+// stores as operation packs under refs/bugs/ in a git repository.
+type GitBugSource struct {
+	repoPath string
+	authors  []string // emails or names; empty reports everyone's activity
+}
+
+// NewGitBugSource creates a source for the repository at repoPath.
+// authorFilter is a comma-separated list of emails or names.
+func NewGitBugSource(repoPath, authorFilter string) *GitBugSource {
+	var authors []string
+	for a := range strings.SplitSeq(authorFilter, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			authors = append(authors, strings.ToLower(a))
+		}
+	}
+	return &GitBugSource{repoPath: repoPath, authors: authors}
+}
+
+func (s *GitBugSource) Type() string {
+	return "git-bug"
+}
+
+func (s *GitBugSource) Location() string {
+	return s.repoPath
+}
+
+func (s *GitBugSource) Validate() error {
+	cmd := exec.Command("git", "-C", s.repoPath, "rev-parse", "--git-dir")
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("not a valid git repository: %w", err)
+	}
+	return nil
+}
+
+// operation is one entry of an operation pack. Fields not used by a given
+// type are left empty.
+type operation struct {
+	Type      int             `json:"type"`
+	Author    json.RawMessage `json:"author"`
+	Timestamp int64           `json:"timestamp"`
+	Title     string          `json:"title"`
+	Message   string          `json:"message"`
+	Status    int             `json:"status"`
+	Added     []string        `json:"added"`
+	Removed   []string        `json:"removed"`
+}
+
+type operationPack struct {
+	Ops []operation `json:"ops"`
+}
+
+// identity is the author as recorded in an operation: either inline
+// (older git-bug versions) or a reference to refs/identities/<id>.
+type identity struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func (s *GitBugSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	refs, err := s.git(ctx, "for-each-ref", "--format=%(refname)", bugRefPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bugs: %w", err)
+	}
+
+	identities := make(map[string]identity)
+	var entries []sources.Entry
+
+	for _, ref := range strings.Fields(refs) {
+		bugID := strings.TrimPrefix(ref, bugRefPrefix)
+		ops, err := s.operations(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		slices.SortStableFunc(ops, func(a, b operation) int { return cmp.Compare(a.Timestamp, b.Timestamp) })
+
+		// Replay titles so each event shows the title the bug had at the time.
+		var title string
+		for _, op := range ops {
+			prevTitle := title
+			if op.Type == opCreate || op.Type == opSetTitle {
+				title = op.Title
+			}
+
+			ts := time.Unix(op.Timestamp, 0)
+			if ts.Before(from) || ts.After(to) {
+				continue
+			}
+
+			author := s.resolveIdentity(ctx, op.Author, identities)
+			if !s.matchesAuthor(author) {
+				continue
+			}
+
+			if e, ok := opEntry(op, bugID, title, prevTitle, author, ts, s.repoPath); ok {
+				entries = append(entries, e)
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// operations reads all operation packs of a bug, walking its commit chain.
+func (s *GitBugSource) operations(ctx context.Context, ref string) ([]operation, error) {
+	commits, err := s.git(ctx, "rev-list", ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ref, err)
+	}
+
+	var ops []operation
+	for _, commit := range strings.Fields(commits) {
+		data, err := s.git(ctx, "cat-file", "-p", commit+":ops")
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			continue // commit without an operation pack (e.g. a merge)
+		}
+		var pack operationPack
+		if err := json.Unmarshal([]byte(data), &pack); err != nil {
+			return nil, fmt.Errorf("failed to parse operations of %s: %w", ref, err)
+		}
+		ops = append(ops, pack.Ops...)
+	}
+	return ops, nil
+}
+
+// resolveIdentity returns the author of an operation, looking up identity
+// references in refs/identities/ once per id.
+func (s *GitBugSource) resolveIdentity(ctx context.Context, raw json.RawMessage, cache map[string]identity) identity {
+	var id identity
+	if err := json.Unmarshal(raw, &id); err != nil || id.ID == "" || id.Email != "" || id.Name != "" {
+		return id
+	}
+	if cached, ok := cache[id.ID]; ok {
+		return cached
+	}
+
+	resolved := id
+	if data, err := s.git(ctx, "cat-file", "-p", identityPrefix+id.ID+":version"); err == nil {
+		var v identity
+		if err := json.Unmarshal([]byte(data), &v); err == nil {
+			resolved.Name, resolved.Email = v.Name, v.Email
+		}
+	}
+	cache[id.ID] = resolved
+	return resolved
+}
+
+func (s *GitBugSource) matchesAuthor(a identity) bool {
+	if len(s.authors) == 0 {
+		return true
+	}
+	return slices.Contains(s.authors, strings.ToLower(a.Email)) || slices.Contains(s.authors, strings.ToLower(a.Name))
+}
+
+func (s *GitBugSource) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", s.repoPath}, args...)...)
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+func opEntry(op operation, bugID, title, prevTitle string, author identity, ts time.Time, repoPath string) (sources.Entry, bool) {
+	meta := map[string]string{
+		"bug_id": bugID,
+		"title":  title,
+	}
+	if author.Name != "" {
+		meta["author"] = author.Name
+	}
+	if author.Email != "" {
+		meta["author_email"] = author.Email
+	}
+
+	var content string
+	switch op.Type {
+	case opCreate:
+		meta["kind"] = KindCreated
+		content = "opened: " + title
+	case opAddComment:
+		meta["kind"] = KindCommented
+		content = "commented on: " + title
+		if msg := firstLine(op.Message); msg != "" {
+			content += " -- " + msg
+		}
+	case opSetStatus:
+		if op.Status == statusClosed {
+			meta["kind"] = KindClosed
+			content = "closed: " + title
+		} else {
+			meta["kind"] = KindReopened
+			content = "reopened: " + title
+		}
+	case opSetTitle:
+		meta["kind"] = KindRetitled
+		content = "renamed: " + prevTitle + " -> " + title
+	case opLabelChange:
+		meta["kind"] = KindLabeled
+		var parts []string
+		for _, l := range op.Added {
+			parts = append(parts, "+"+l)
+		}
+		for _, l := range op.Removed {
+			parts = append(parts, "-"+l)
+		}
+		meta["labels"] = strings.Join(parts, ",")
+		content = fmt.Sprintf("labeled: %s (%s)", title, strings.Join(parts, " "))
+	default:
+		return sources.Entry{}, false
+	}
+
+	short := bugID
+	if len(short) > 7 {
+		short = short[:7]
+	}
+	return sources.Entry{
+		Timestamp: ts,
+		Source:    "git-bug",
+		Location:  repoPath,
+		Content:   fmt.Sprintf("[%s] %s", short, content),
+		Metadata:  meta,
+	}, true
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	if len(line) > 120 {
+		line = line[:120] + "..."
+	}
+	return line
+}
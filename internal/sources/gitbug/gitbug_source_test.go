@@ -0,0 +1,134 @@
+package gitbug
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gitOut runs git in dir with stdin and returns trimmed stdout.
+func gitOut(t *testing.T, dir, stdin string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Env = append(cmd.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// writeEntity stores blobs as a commit tree and points ref at it, the way
+// git-bug stores bugs and identities.
+func writeEntity(t *testing.T, dir, ref, parent string, blobs map[string]string) string {
+	t.Helper()
+
+	var tree strings.Builder
+	for name, content := range blobs {
+		hash := gitOut(t, dir, content, "hash-object", "-w", "--stdin")
+		tree.WriteString("100644 blob " + hash + "\t" + name + "\n")
+	}
+	treeHash := gitOut(t, dir, tree.String(), "mktree")
+
+	args := []string{"commit-tree", treeHash, "-m", "op"}
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+	commit := gitOut(t, dir, "", args...)
+	gitOut(t, dir, "", "update-ref", ref, commit)
+	return commit
+}
+
+func setupBugRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	gitOut(t, dir, "", "init", "-q")
+
+	writeEntity(t, dir, "refs/identities/id1", "", map[string]string{
+		"version": `{"version":2,"name":"Jane Doe","email":"jane@example.com"}`,
+	})
+	writeEntity(t, dir, "refs/identities/id2", "", map[string]string{
+		"version": `{"version":2,"name":"Bob","email":"bob@example.com"}`,
+	})
+
+	day := time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)
+	ts := func(d time.Duration) string { return itoa(day.Add(d).Unix()) }
+
+	first := writeEntity(t, dir, "refs/bugs/abcdef0123456789", "", map[string]string{
+		"ops": `{"ops":[{"type":1,"author":{"id":"id1"},"timestamp":` + itoa(day.Add(-48*time.Hour).Unix()) + `,"title":"Login fails","message":"steps..."}]}`,
+	})
+	writeEntity(t, dir, "refs/bugs/abcdef0123456789", first, map[string]string{
+		"ops": `{"ops":[` +
+			`{"type":2,"author":{"id":"id1"},"timestamp":` + ts(9*time.Hour) + `,"title":"Login fails on Safari","was":"Login fails"},` +
+			`{"type":3,"author":{"id":"id2"},"timestamp":` + ts(10*time.Hour) + `,"message":"cannot reproduce\nmore"},` +
+			`{"type":5,"author":{"id":"id1"},"timestamp":` + ts(11*time.Hour) + `,"added":["bug"],"removed":["triage"]},` +
+			`{"type":4,"author":{"id":"id1"},"timestamp":` + ts(12*time.Hour) + `,"status":2}]}`,
+	})
+	return dir
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func TestGitBugSource_GetEntries(t *testing.T) {
+	dir := setupBugRepo(t)
+	from := time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24*time.Hour - time.Second)
+
+	source := NewGitBugSource(dir, "")
+	if err := source.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := source.GetEntries(t.Context(), from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var contents []string
+	for _, e := range entries {
+		contents = append(contents, e.Content)
+	}
+	want := []string{
+		"[abcdef0] renamed: Login fails -> Login fails on Safari",
+		"[abcdef0] commented on: Login fails on Safari -- cannot reproduce",
+		"[abcdef0] labeled: Login fails on Safari (+bug -triage)",
+		"[abcdef0] closed: Login fails on Safari",
+	}
+	if strings.Join(contents, "|") != strings.Join(want, "|") {
+		t.Errorf("contents =\n%q\nwant\n%q", contents, want)
+	}
+	if entries[3].Metadata["author_email"] != "jane@example.com" || entries[3].Metadata["kind"] != KindClosed {
+		t.Errorf("unexpected metadata: %v", entries[3].Metadata)
+	}
+
+	// Author filter matches resolved identities by email or name.
+	entries, err = NewGitBugSource(dir, "Bob").GetEntries(t.Context(), from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Metadata["kind"] != KindCommented {
+		t.Errorf("expected only Bob's comment, got %+v", entries)
+	}
+}
+
+func TestGitBugSource_NoBugs(t *testing.T) {
+	dir := t.TempDir()
+	gitOut(t, dir, "", "init", "-q")
+
+	entries, err := NewGitBugSource(dir, "").GetEntries(t.Context(), time.Time{}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(entries))
+	}
+}
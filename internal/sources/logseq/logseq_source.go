@@ -0,0 +1,366 @@
+package logseq
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/sources/org"
+)
+
+// journalLayout is Logseq's default journal file name format (yyyy_MM_dd).
+const journalLayout = "2006_01_02"
+
+// markers are the Logseq task keywords a block may start with.
+var markers = []string{"TODO", "DOING", "DONE", "LATER", "NOW", "WAITING", "WAIT", "CANCELED", "CANCELLED", "IN-PROGRESS"}
+
+var (
+	blockRegex    = regexp.MustCompile(`^(\s*)-\s+(.*)$`)
+	propertyRegex = regexp.MustCompile(`^\s*([A-Za-z0-9_-]+)::\s*(.*)$`)
+	tagRegex      = regexp.MustCompile(`(?:^|\s)#(\[\[[^\]]+\]\]|[\p{L}\p{N}_/-]+)`)
+	pageRefRegex  = regexp.MustCompile(`\[\[([^\]]+)\]\]`)
+	timeRegex     = regexp.MustCompile(`^(\d{1,2}):(\d{2})\s+`)
+	priorityRegex = regexp.MustCompile(`\s*\[#[A-C]\]\s*`)
+)
+
+// Block is one outline item of a Logseq page.
+type Block struct {
+	Text       string            // block content without marker and properties
+	Marker     string            // task marker (TODO, DONE, ...), empty for plain blocks
+	Properties map[string]string // "key:: value" lines of the block
+	Tags       []string          // #tags and #[[tags]] in the block
+	Refs       []string          // [[page]] references in the block
+	Parent     string            // text of the parent block, if nested
+	Depth      int
+	Line       int
+	Clocks     []Clock // closed CLOCK entries from the block's :LOGBOOK:
+}
+
+// Clock is a logged time interval.
+type Clock struct {
+	Start time.Time
+	End   time.Time
+}
+
+// LogseqSource implements the Source interface for Logseq graphs. Journal
+// pages are read for every day in range; other pages contribute clocked time.
+type LogseqSource struct {
+	graphPath string
+}
+
+// NewLogseqSource creates a source for the Logseq graph at graphPath.
+func NewLogseqSource(graphPath string) *LogseqSource {
+	return &LogseqSource{graphPath: graphPath}
+}
+
+func (l *LogseqSource) Type() string {
+	return "logseq"
+}
+
+func (l *LogseqSource) Location() string {
+	return l.graphPath
+}
+
+func (l *LogseqSource) Validate() error {
+	info, err := os.Stat(filepath.Join(l.graphPath, "journals"))
+	if err != nil {
+		return fmt.Errorf("not a Logseq graph (journals directory missing): %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("not a Logseq graph: %s/journals is not a directory", l.graphPath)
+	}
+	return nil
+}
+
+func (l *LogseqSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+
+	var entries []sources.Entry
+
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for day := start; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		path := filepath.Join(l.graphPath, "journals", day.Format(journalLayout)+".md")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		for _, b := range ParsePage(data) {
+			entries = append(entries, l.journalEntry(path, day, b))
+		}
+	}
+
+	pageEntries, err := l.pageClockEntries(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	entries = append(entries, pageEntries...)
+
+	return entries, nil
+}
+
+// pageClockEntries reports logbook clocks of blocks on non-journal pages.
+// Pages not modified since from cannot hold a clock that ended in range.
+func (l *LogseqSource) pageClockEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	pagesDir := filepath.Join(l.graphPath, "pages")
+	files, err := os.ReadDir(pagesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read pages: %w", err)
+	}
+
+	var entries []sources.Entry
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".md") {
+			continue
+		}
+		if info, err := f.Info(); err != nil || info.ModTime().Before(from) {
+			continue
+		}
+
+		path := filepath.Join(pagesDir, f.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		page := pageName(f.Name())
+		for _, b := range ParsePage(data) {
+			for _, c := range b.Clocks {
+				if c.Start.Before(from) || c.Start.After(to) {
+					continue
+				}
+				meta := blockMetadata(b, path)
+				meta["kind"] = "clock"
+				meta["page"] = page
+				minutes := int(c.End.Sub(c.Start).Round(time.Minute).Minutes())
+				meta["duration_minutes"] = strconv.Itoa(minutes)
+				meta["clock_end"] = c.End.Format(time.RFC3339)
+				entries = append(entries, sources.Entry{
+					Timestamp: c.Start,
+					Source:    "logseq",
+					Location:  l.graphPath,
+					Content:   fmt.Sprintf("%s: %s (clocked %dm)", page, b.Text, minutes),
+					Metadata:  meta,
+				})
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (l *LogseqSource) journalEntry(path string, day time.Time, b Block) sources.Entry {
+	meta := blockMetadata(b, path)
+	meta["kind"] = "journal"
+	meta["date"] = day.Format("2006-01-02")
+
+	text := b.Text
+	ts := day
+	if m := timeRegex.FindStringSubmatch(text); m != nil {
+		hh, _ := strconv.Atoi(m[1])
+		mm, _ := strconv.Atoi(m[2])
+		if hh < 24 && mm < 60 {
+			ts = day.Add(time.Duration(hh)*time.Hour + time.Duration(mm)*time.Minute)
+			text = strings.TrimSpace(text[len(m[0]):])
+			meta["time"] = ts.Format("15:04")
+		}
+	}
+
+	var minutes int
+	for _, c := range b.Clocks {
+		minutes += int(c.End.Sub(c.Start).Round(time.Minute).Minutes())
+	}
+	if minutes > 0 {
+		meta["duration_minutes"] = strconv.Itoa(minutes)
+	}
+
+	content := text
+	if b.Marker != "" {
+		content = b.Marker + " " + text
+	}
+
+	return sources.Entry{
+		Timestamp: ts,
+		Source:    "logseq",
+		Location:  l.graphPath,
+		Content:   content,
+		Metadata:  meta,
+	}
+}
+
+func blockMetadata(b Block, path string) map[string]string {
+	meta := make(map[string]string)
+	for k, v := range b.Properties {
+		meta["prop_"+k] = v
+	}
+	meta["file"] = filepath.Base(path)
+	meta["line"] = strconv.Itoa(b.Line)
+	if b.Marker != "" {
+		meta["marker"] = b.Marker
+	}
+	if len(b.Tags) > 0 {
+		meta["tags"] = strings.Join(b.Tags, ",")
+	}
+	if len(b.Refs) > 0 {
+		meta["refs"] = strings.Join(b.Refs, ",")
+	}
+	if b.Parent != "" {
+		meta["parent"] = b.Parent
+	}
+	return meta
+}
+
+// pageName decodes a Logseq page file name ("project___acme.md" is the
+// namespaced page "project/acme").
+func pageName(file string) string {
+	name := strings.TrimSuffix(file, ".md")
+	name = strings.ReplaceAll(name, "___", "/")
+	return strings.ReplaceAll(name, "%2F", "/")
+}
+
+// ParsePage parses the block outline of a Logseq markdown page. Page
+// properties before the first block are merged into every block's
+// properties; block properties take precedence.
+func ParsePage(data []byte) []Block {
+	var blocks []Block
+	pageProps := make(map[string]string)
+
+	type open struct {
+		indent int
+		text   string
+	}
+	var stack []open
+	var cur *Block
+	inLogbook := false
+
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		props := maps.Clone(pageProps)
+		maps.Copy(props, cur.Properties)
+		cur.Properties = props
+		cur.Text = strings.TrimSpace(cur.Text)
+		if cur.Text != "" || len(cur.Clocks) > 0 {
+			blocks = append(blocks, *cur)
+		}
+		cur = nil
+	}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if m := blockRegex.FindStringSubmatch(line); m != nil {
+			flush()
+			inLogbook = false
+			indent := indentWidth(m[1])
+			for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+				stack = stack[:len(stack)-1]
+			}
+
+			b := Block{Depth: len(stack), Line: i + 1, Properties: make(map[string]string)}
+			if len(stack) > 0 {
+				b.Parent = stack[len(stack)-1].text
+			}
+			text := m[2]
+			if pm := propertyRegex.FindStringSubmatch(text); pm != nil {
+				// a block consisting only of properties
+				b.Properties[pm[1]] = pm[2]
+				text = ""
+			}
+			b.Marker, text = splitMarker(text)
+			b.Text = text
+			cur = &b
+			stack = append(stack, open{indent: indent, text: cleanText(text)})
+			continue
+		}
+
+		if cur == nil {
+			if pm := propertyRegex.FindStringSubmatch(line); pm != nil {
+				pageProps[pm[1]] = pm[2]
+			}
+			continue
+		}
+
+		switch {
+		case trimmed == ":LOGBOOK:":
+			inLogbook = true
+		case trimmed == ":END:":
+			inLogbook = false
+		case inLogbook:
+			if start, end, ok := org.ParseClock(trimmed); ok {
+				cur.Clocks = append(cur.Clocks, Clock{Start: start, End: end})
+			}
+		default:
+			if pm := propertyRegex.FindStringSubmatch(line); pm != nil {
+				cur.Properties[pm[1]] = pm[2]
+			} else if trimmed != "" {
+				cur.Text += " " + trimmed
+			}
+		}
+	}
+	flush()
+
+	for i := range blocks {
+		b := &blocks[i]
+		for _, m := range tagRegex.FindAllStringSubmatch(b.Text, -1) {
+			tag := strings.TrimSuffix(strings.TrimPrefix(m[1], "[["), "]]")
+			if !slices.Contains(b.Tags, tag) {
+				b.Tags = append(b.Tags, tag)
+			}
+		}
+		for _, m := range pageRefRegex.FindAllStringSubmatch(b.Text, -1) {
+			if !slices.Contains(b.Refs, m[1]) && !slices.Contains(b.Tags, m[1]) {
+				b.Refs = append(b.Refs, m[1])
+			}
+		}
+		b.Text = cleanText(b.Text)
+	}
+
+	return blocks
+}
+
+// splitMarker separates a leading task marker from the block text.
+func splitMarker(text string) (string, string) {
+	first, rest, _ := strings.Cut(text, " ")
+	if slices.Contains(markers, first) {
+		return first, strings.TrimSpace(rest)
+	}
+	return "", text
+}
+
+// cleanText removes priorities and page-reference brackets for display.
+func cleanText(text string) string {
+	text = priorityRegex.ReplaceAllString(text, " ")
+	text = pageRefRegex.ReplaceAllString(text, "$1")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func indentWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if r == '\t' {
+			width += 4
+		} else {
+			width++
+		}
+	}
+	return width
+}
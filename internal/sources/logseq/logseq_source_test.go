@@ -0,0 +1,125 @@
+package logseq
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleJournal = `title:: Apr 7th, 2026
+type:: journal
+
+- 09:30 Standup with #team
+- DONE Review [[Acme]] pricing PR
+  project:: acme
+  :LOGBOOK:
+  CLOCK: [2026-04-07 Tue 10:00:00]--[2026-04-07 Tue 11:15:00] =>  01:15:00
+  :END:
+- Meeting notes
+	- Decided to ship on Friday #[[release plan]]
+- TODO [#A] Write migration guide
+`
+
+func TestParsePage(t *testing.T) {
+	blocks := ParsePage([]byte(sampleJournal))
+	if len(blocks) != 5 {
+		t.Fatalf("expected 5 blocks, got %d: %+v", len(blocks), blocks)
+	}
+
+	if blocks[0].Text != "09:30 Standup with #team" || strings.Join(blocks[0].Tags, ",") != "team" {
+		t.Errorf("unexpected first block: %+v", blocks[0])
+	}
+
+	done := blocks[1]
+	if done.Marker != "DONE" || done.Text != "Review Acme pricing PR" {
+		t.Errorf("unexpected task block: %+v", done)
+	}
+	if done.Properties["project"] != "acme" || done.Properties["type"] != "journal" {
+		t.Errorf("properties = %v", done.Properties)
+	}
+	if strings.Join(done.Refs, ",") != "Acme" {
+		t.Errorf("refs = %v", done.Refs)
+	}
+	if len(done.Clocks) != 1 || done.Clocks[0].End.Sub(done.Clocks[0].Start) != 75*time.Minute {
+		t.Errorf("clocks = %+v", done.Clocks)
+	}
+
+	child := blocks[3]
+	if child.Parent != "Meeting notes" || child.Depth != 1 {
+		t.Errorf("unexpected child block: %+v", child)
+	}
+	if strings.Join(child.Tags, ",") != "release plan" || len(child.Refs) != 0 {
+		t.Errorf("tags = %v, refs = %v", child.Tags, child.Refs)
+	}
+
+	if blocks[4].Marker != "TODO" || blocks[4].Text != "Write migration guide" {
+		t.Errorf("unexpected todo block: %+v", blocks[4])
+	}
+}
+
+func TestLogseqSource_Validate(t *testing.T) {
+	dir := t.TempDir()
+	if err := NewLogseqSource(dir).Validate(); err == nil {
+		t.Error("expected error for directory without journals")
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "journals"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewLogseqSource(dir).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLogseqSource_GetEntries(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"journals", "pages"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "journals", "2026_04_07.md"), []byte(sampleJournal), 0644); err != nil {
+		t.Fatal(err)
+	}
+	page := "- DOING Refactor billing\n  :LOGBOOK:\n  CLOCK: [2026-04-07 Tue 14:00:00]--[2026-04-07 Tue 14:45:00] =>  00:45:00\n  :END:\n"
+	if err := os.WriteFile(filepath.Join(dir, "pages", "project___acme.md"), []byte(page), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2026, 4, 7, 0, 0, 0, 0, time.Local)
+	to := time.Date(2026, 4, 7, 23, 59, 59, 0, time.Local)
+	entries, err := NewLogseqSource(dir).GetEntries(t.Context(), from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 6 {
+		t.Fatalf("expected 6 entries, got %d: %+v", len(entries), entries)
+	}
+
+	first := entries[0]
+	if first.Content != "Standup with #team" || first.Timestamp.Hour() != 9 || first.Metadata["time"] != "09:30" {
+		t.Errorf("unexpected first entry: %+v", first)
+	}
+	if entries[1].Content != "DONE Review Acme pricing PR" || entries[1].Metadata["duration_minutes"] != "75" {
+		t.Errorf("unexpected task entry: %+v", entries[1])
+	}
+	if entries[1].Metadata["prop_project"] != "acme" {
+		t.Errorf("expected block property in metadata, got %v", entries[1].Metadata)
+	}
+
+	clock := entries[5]
+	if clock.Metadata["kind"] != "clock" || clock.Metadata["page"] != "project/acme" || clock.Metadata["duration_minutes"] != "45" {
+		t.Errorf("unexpected clock entry: %+v", clock)
+	}
+
+	// A day without a journal page yields nothing.
+	entries, err = NewLogseqSource(dir).GetEntries(t.Context(), from.AddDate(0, 0, 1), to.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(entries))
+	}
+}
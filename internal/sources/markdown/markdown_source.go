@@ -2,6 +2,7 @@ package markdown
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io/fs"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/sources/tasks"
 )
 
 var tagRegex = regexp.MustCompile(`#(\w+)|\[\[([^\]]+)\]\]`)
@@ -20,6 +22,9 @@ type MarkdownSource struct {
 	basePath string
 	tags     []string
 	headings []string
+
+	taskMode  bool   // emit completed/due tasks instead of tagged lines
+	taskState string // optional state file for detecting checkbox changes
 }
 
 // NewMarkdownSource creates a new Markdown source.
@@ -33,6 +38,16 @@ func NewMarkdownSource(basePath string, tags, headings []string) *MarkdownSource
 	}
 }
 
+// EnableTasks switches the source to task mode: instead of tagged lines it
+// emits checkbox tasks completed in the range and open tasks due in it.
+// statePath, if non-empty, is used to remember task status between runs so
+// that checkboxes without a done date are reported when they flip.
+func (m *MarkdownSource) EnableTasks(statePath string) *MarkdownSource {
+	m.taskMode = true
+	m.taskState = statePath
+	return m
+}
+
 func (m *MarkdownSource) Type() string {
 	return "markdown"
 }
@@ -52,13 +67,20 @@ func (m *MarkdownSource) Validate() error {
 	return nil
 }
 
-func (m *MarkdownSource) GetEntries(from, to time.Time) ([]sources.Entry, error) {
+func (m *MarkdownSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	if m.taskMode {
+		return m.getTaskEntries(ctx, from, to)
+	}
+
 	var entries []sources.Entry
 
 	err := filepath.WalkDir(m.basePath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
 		if d.IsDir() {
 			return nil
@@ -183,3 +205,88 @@ func (m *MarkdownSource) hasRelevantTags(line string) bool {
 
 	return false
 }
+
+// getTaskEntries walks the directory in task mode. Files last modified before
+// the range cannot contain a task ticked within it, so only their open tasks
+// due in range are reported; files modified after the range are still checked
+// for completions since done dates are explicit.
+func (m *MarkdownSource) getTaskEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	var state *tasks.State
+	if m.taskState != "" {
+		var err error
+		state, err = tasks.LoadState(m.taskState)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var entries []sources.Entry
+
+	err := filepath.WalkDir(m.basePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		var candidates []tasks.Task
+		for _, t := range tasks.ParseLines(strings.Split(string(data), "\n")) {
+			if len(m.headings) > 0 && !m.isRelevantHeading(t.Heading) {
+				continue
+			}
+			if len(m.tags) > 0 && !m.hasRelevantTags(t.Text) {
+				continue
+			}
+			candidates = append(candidates, t)
+		}
+
+		relPath, err := filepath.Rel(m.basePath, path)
+		if err != nil {
+			relPath = path
+		}
+
+		var relevant []tasks.Task
+		if info.ModTime().Before(from) {
+			relevant = tasks.Due(candidates, from, to)
+			state.Keep(relPath, candidates)
+		} else {
+			relevant = tasks.Relevant(relPath, candidates, info.ModTime(), from, to, state)
+		}
+		for _, t := range relevant {
+			meta := t.Metadata()
+			meta["file"] = filepath.Base(path)
+			entries = append(entries, sources.Entry{
+				Timestamp: t.Timestamp(),
+				Source:    "markdown",
+				Location:  path,
+				Content:   t.Content(),
+				Metadata:  meta,
+			})
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory: %w", err)
+	}
+
+	if err := state.Save(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -110,7 +110,7 @@ Fixed a bug.
 	now := time.Now()
 	yesterday := now.Add(-24 * time.Hour)
 
-	entries, err := source.GetEntries(yesterday, now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), yesterday, now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -139,7 +139,7 @@ Line without relevant tags #other.
 	now := time.Now()
 	yesterday := now.Add(-24 * time.Hour)
 
-	entries, err := source.GetEntries(yesterday, now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), yesterday, now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -197,7 +197,7 @@ More work items.
 	now := time.Now()
 	yesterday := now.Add(-24 * time.Hour)
 
-	entries, err := source.GetEntries(yesterday, now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), yesterday, now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -230,7 +230,7 @@ func TestMarkdownSource_GetEntries_TimeFilter(t *testing.T) {
 	now := time.Now()
 	oneDayAgo := now.Add(-24 * time.Hour)
 
-	entries, err := source.GetEntries(oneDayAgo, now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), oneDayAgo, now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -256,7 +256,7 @@ func TestMarkdownSource_GetEntries_MultipleFiles(t *testing.T) {
 	now := time.Now()
 	yesterday := now.Add(-24 * time.Hour)
 
-	entries, err := source.GetEntries(yesterday, now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), yesterday, now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -295,7 +295,7 @@ Line without relevant wikilinks [[other]].
 	now := time.Now()
 	yesterday := now.Add(-24 * time.Hour)
 
-	entries, err := source.GetEntries(yesterday, now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), yesterday, now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -320,7 +320,7 @@ func TestMarkdownSource_GetEntries_SkipsNonMarkdownFiles(t *testing.T) {
 	now := time.Now()
 	yesterday := now.Add(-24 * time.Hour)
 
-	entries, err := source.GetEntries(yesterday, now.Add(1*time.Hour))
+	entries, err := source.GetEntries(t.Context(), yesterday, now.Add(1*time.Hour))
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -333,3 +333,69 @@ func TestMarkdownSource_GetEntries_SkipsNonMarkdownFiles(t *testing.T) {
 		t.Errorf("expected file 'notes.md', got %s", entries[0].Metadata["file"])
 	}
 }
+
+func TestMarkdownSource_GetEntries_TaskMode(t *testing.T) {
+	tmpDir := setupTestMarkdownDir(t)
+	today := time.Now().Format("2006-01-02")
+
+	content := `# Work
+- [x] shipped pricing page ✅ ` + today + `
+- [x] old task ✅ 2020-01-01
+- [ ] review invoice 📅 ` + today + `
+- [ ] someday
+plain line #work
+`
+	writeMarkdownFile(t, tmpDir, "tasks.md", content)
+
+	source := NewMarkdownSource(tmpDir, nil, nil).EnableTasks("")
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	entries, err := source.GetEntries(t.Context(), from, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetEntries failed: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 task entries, got %d", len(entries))
+	}
+
+	byStatus := make(map[string]string)
+	for _, e := range entries {
+		if e.Metadata["kind"] != "task" {
+			t.Errorf("expected kind=task, got %q", e.Metadata["kind"])
+		}
+		byStatus[e.Metadata["task_status"]] = e.Content
+	}
+
+	if byStatus["done"] != "done: shipped pricing page" {
+		t.Errorf("unexpected done entry: %q", byStatus["done"])
+	}
+	if byStatus["open"] != "open: review invoice (due "+today+")" {
+		t.Errorf("unexpected open entry: %q", byStatus["open"])
+	}
+}
+
+func TestMarkdownSource_GetEntries_TaskModeUntouchedFile(t *testing.T) {
+	tmpDir := setupTestMarkdownDir(t)
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	nextWeek := from.AddDate(0, 0, 7)
+
+	// A backlog written a month ago, with a task due in the queried range.
+	path := writeMarkdownFile(t, tmpDir, "backlog.md", "- [ ] renew certificate 📅 "+nextWeek.Format("2006-01-02")+"\n- [x] done long ago\n")
+	old := from.AddDate(0, -1, 0)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewMarkdownSource(tmpDir, nil, nil).EnableTasks(filepath.Join(t.TempDir(), "state.json"))
+	entries, err := source.GetEntries(t.Context(), from, nextWeek.Add(24*time.Hour-time.Second))
+	if err != nil {
+		t.Fatalf("GetEntries failed: %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].Metadata["task_status"] != "open" || entries[0].Metadata["due_date"] != nextWeek.Format("2006-01-02") {
+		t.Errorf("expected the open task due in range, got %+v", entries)
+	}
+}
@@ -0,0 +1,116 @@
+package obsidian
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// Change actions reported for vault notes.
+const (
+	actionCreated  = "created"
+	actionModified = "modified"
+	actionDeleted  = "deleted"
+	actionRenamed  = "renamed"
+)
+
+// headingRegex matches ATX markdown headings and captures the title.
+var headingRegex = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*\s*$`)
+
+// change is a content-level change to a single note, detected either from
+// the vault's git history or by diffing two snapshots.
+type change struct {
+	Time            time.Time `json:"time"`
+	Since           time.Time `json:"since,omitzero"` // set if the change happened at some point in (Since, Time]
+	Action          string    `json:"action"`
+	Path            string    `json:"path"`
+	PreviousPath    string    `json:"previous_path,omitempty"`
+	HeadingsAdded   []string  `json:"headings_added,omitempty"`
+	HeadingsRemoved []string  `json:"headings_removed,omitempty"`
+	LinesAdded      int       `json:"lines_added,omitempty"`
+	LinesRemoved    int       `json:"lines_removed,omitempty"`
+	DetectedBy      string    `json:"detected_by"` // "git", "snapshot" or "mtime"
+	Commit          string    `json:"commit,omitempty"`
+}
+
+// headingTitle returns the heading text of a markdown line, or "" if the
+// line is not a heading.
+func headingTitle(line string) string {
+	m := headingRegex.FindStringSubmatch(line)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// content renders the change as a single line for the AI, e.g.
+// `1 Projects/Acme proposal.md (modified): added section "Pricing", +12/-3 lines`.
+func (c change) content() string {
+	head := fmt.Sprintf("%s (%s)", c.Path, c.Action)
+	if c.Action == actionRenamed {
+		head = fmt.Sprintf("%s -> %s (%s)", c.PreviousPath, c.Path, c.Action)
+	}
+
+	var details []string
+	for _, h := range c.HeadingsAdded {
+		details = append(details, fmt.Sprintf("added section %q", h))
+	}
+	for _, h := range c.HeadingsRemoved {
+		details = append(details, fmt.Sprintf("removed section %q", h))
+	}
+	if c.Action == actionModified && (c.LinesAdded > 0 || c.LinesRemoved > 0) {
+		details = append(details, fmt.Sprintf("+%d/-%d lines", c.LinesAdded, c.LinesRemoved))
+	}
+	if !c.Since.IsZero() {
+		details = append(details, "at some point since "+c.Since.Local().Format("2006-01-02 15:04"))
+	}
+	if len(details) == 0 {
+		return head
+	}
+	return head + ": " + strings.Join(details, ", ")
+}
+
+// toEntry converts the change into a source entry for the given vault.
+func (c change) toEntry(vaultPath string) sources.Entry {
+	meta := map[string]string{
+		"file":        filepath.Base(c.Path),
+		"path":        c.Path,
+		"action":      c.Action,
+		"detected_by": c.DetectedBy,
+	}
+	if c.PreviousPath != "" {
+		meta["previous_path"] = c.PreviousPath
+	}
+	if len(c.HeadingsAdded) > 0 {
+		meta["headings_added"] = strings.Join(c.HeadingsAdded, ", ")
+	}
+	if len(c.HeadingsRemoved) > 0 {
+		meta["headings_removed"] = strings.Join(c.HeadingsRemoved, ", ")
+	}
+	if c.LinesAdded > 0 {
+		meta["lines_added"] = strconv.Itoa(c.LinesAdded)
+	}
+	if c.LinesRemoved > 0 {
+		meta["lines_removed"] = strconv.Itoa(c.LinesRemoved)
+	}
+	if c.Commit != "" {
+		meta["commit"] = c.Commit
+	}
+	if !c.Since.IsZero() {
+		meta["time_approximate"] = "true"
+		meta["not_before"] = c.Since.Format(time.RFC3339)
+	}
+
+	return sources.Entry{
+		Timestamp: c.Time,
+		Source:    "obsidian",
+		Location:  vaultPath,
+		Content:   c.content(),
+		Metadata:  meta,
+	}
+}
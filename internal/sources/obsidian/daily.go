@@ -0,0 +1,312 @@
+package obsidian
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/sources/tasks"
+)
+
+// defaultDailyFormat is Obsidian's default daily note file name format.
+const defaultDailyFormat = "YYYY-MM-DD"
+
+// bulletRegex matches list items ("- x", "* x", "1. x") and captures the text.
+var bulletRegex = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(.*)$`)
+
+// timePrefixRegex matches a leading clock time in a log line, e.g. "14:30 call".
+var timePrefixRegex = regexp.MustCompile(`^(\d{1,2}):(\d{2})\b\s*[-–]?\s*`)
+
+// dailyConfig locates daily notes inside a vault.
+type dailyConfig struct {
+	Folder string `json:"folder"`
+	Format string `json:"format"`
+}
+
+// periodicNotesConfig is the subset of the Periodic Notes plugin settings we read.
+type periodicNotesConfig struct {
+	Daily struct {
+		Enabled bool   `json:"enabled"`
+		Folder  string `json:"folder"`
+		Format  string `json:"format"`
+	} `json:"daily"`
+}
+
+// loadDailyConfig reads the daily note settings of a vault. The Periodic
+// Notes plugin takes precedence over the core Daily Notes plugin. Returns
+// false if neither is configured or enabled.
+func loadDailyConfig(vaultPath string) (dailyConfig, bool) {
+	cfgDir := filepath.Join(vaultPath, ".obsidian")
+
+	if data, err := os.ReadFile(filepath.Join(cfgDir, "plugins", "periodic-notes", "data.json")); err == nil {
+		var pn periodicNotesConfig
+		if err := json.Unmarshal(data, &pn); err == nil && pn.Daily.Enabled {
+			return normalizeDailyConfig(dailyConfig{Folder: pn.Daily.Folder, Format: pn.Daily.Format}), true
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(cfgDir, "daily-notes.json")); err == nil {
+		var dc dailyConfig
+		if err := json.Unmarshal(data, &dc); err == nil {
+			return normalizeDailyConfig(dc), true
+		}
+	}
+
+	// The core plugin works with defaults before its settings are ever saved.
+	if data, err := os.ReadFile(filepath.Join(cfgDir, "core-plugins.json")); err == nil {
+		var enabled []string
+		if err := json.Unmarshal(data, &enabled); err == nil && slices.Contains(enabled, "daily-notes") {
+			return normalizeDailyConfig(dailyConfig{}), true
+		}
+	}
+
+	return dailyConfig{}, false
+}
+
+func normalizeDailyConfig(dc dailyConfig) dailyConfig {
+	dc.Folder = strings.Trim(dc.Folder, "/")
+	if dc.Format == "" {
+		dc.Format = defaultDailyFormat
+	}
+	return dc
+}
+
+// notePath returns the vault-relative path of the daily note for day.
+func (dc dailyConfig) notePath(day time.Time) string {
+	name := formatMoment(day, dc.Format) + ".md"
+	if dc.Folder == "" {
+		return name
+	}
+	return path.Join(dc.Folder, name)
+}
+
+// momentTokens lists the supported moment.js tokens, longest first so that
+// "YYYY" is matched before "YY".
+var momentTokens = []string{"YYYY", "GGGG", "gggg", "MMMM", "MMM", "MM", "M", "DDDD", "DDD", "Do", "DD", "D", "dddd", "ddd", "YY", "ww", "WW", "w", "W", "E", "e"}
+
+// formatMoment formats t using the moment.js format syntax that Obsidian uses
+// for daily note names. Text in [brackets] is copied literally.
+func formatMoment(t time.Time, format string) string {
+	var out strings.Builder
+	for i := 0; i < len(format); {
+		if format[i] == '[' {
+			if end := strings.IndexByte(format[i:], ']'); end != -1 {
+				out.WriteString(format[i+1 : i+end])
+				i += end + 1
+				continue
+			}
+		}
+
+		matched := false
+		for _, tok := range momentTokens {
+			if strings.HasPrefix(format[i:], tok) {
+				out.WriteString(momentToken(t, tok))
+				i += len(tok)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			out.WriteByte(format[i])
+			i++
+		}
+	}
+	return out.String()
+}
+
+func momentToken(t time.Time, tok string) string {
+	isoYear, isoWeek := t.ISOWeek()
+	switch tok {
+	case "YYYY":
+		return t.Format("2006")
+	case "YY":
+		return t.Format("06")
+	case "GGGG", "gggg":
+		return strconv.Itoa(isoYear)
+	case "MMMM":
+		return t.Format("January")
+	case "MMM":
+		return t.Format("Jan")
+	case "MM":
+		return t.Format("01")
+	case "M":
+		return strconv.Itoa(int(t.Month()))
+	case "DDDD":
+		return fmt.Sprintf("%03d", t.YearDay())
+	case "DDD":
+		return strconv.Itoa(t.YearDay())
+	case "DD":
+		return t.Format("02")
+	case "D":
+		return strconv.Itoa(t.Day())
+	case "Do":
+		return ordinal(t.Day())
+	case "dddd":
+		return t.Format("Monday")
+	case "ddd":
+		return t.Format("Mon")
+	case "ww", "WW":
+		return fmt.Sprintf("%02d", isoWeek)
+	case "w", "W":
+		return strconv.Itoa(isoWeek)
+	case "E":
+		return strconv.Itoa((int(t.Weekday())+6)%7 + 1)
+	case "e":
+		return strconv.Itoa(int(t.Weekday()))
+	}
+	return tok
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// dailyItem is one structural element of a daily note.
+type dailyItem struct {
+	Kind    string // "bullet", "task" or "text"
+	Text    string
+	Heading string
+	Line    int
+	Clock   time.Duration // time of day from a "HH:MM" prefix; -1 if absent
+	Task    *tasks.Task
+}
+
+// parseDailyNote splits a daily note into bullets, tasks and text lines,
+// each tagged with the heading it appears under. Frontmatter, headings,
+// blank lines and fenced code are skipped.
+func parseDailyNote(data []byte) []dailyItem {
+	body := data
+	if _, rest, ok := splitFrontmatter(data); ok {
+		body = rest
+	}
+
+	var items []dailyItem
+	var heading string
+	inCode := false
+
+	for i, line := range strings.Split(string(body), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if h := headingTitle(trimmed); h != "" {
+			heading = h
+			continue
+		}
+
+		item := dailyItem{Heading: heading, Line: i + 1, Clock: -1}
+		if t, ok := tasks.Parse(line); ok {
+			item.Kind = "task"
+			item.Text = t.Text
+			item.Task = &t
+		} else if m := bulletRegex.FindStringSubmatch(line); m != nil {
+			item.Kind = "bullet"
+			item.Text = strings.TrimSpace(m[1])
+		} else {
+			item.Kind = "text"
+			item.Text = trimmed
+		}
+		if item.Text == "" {
+			continue
+		}
+
+		if m := timePrefixRegex.FindStringSubmatch(item.Text); m != nil {
+			h, _ := strconv.Atoi(m[1])
+			mins, _ := strconv.Atoi(m[2])
+			if h < 24 && mins < 60 {
+				item.Clock = time.Duration(h)*time.Hour + time.Duration(mins)*time.Minute
+				item.Text = strings.TrimSpace(item.Text[len(m[0]):])
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// dailyEntries extracts the daily notes for each day in [from, to] as
+// structured entries, independent of file mtime. It returns the entries and
+// the vault-relative paths of the notes that were read.
+func (o *ObsidianSource) dailyEntries(dc dailyConfig, from, to time.Time, index *noteIndex) ([]sources.Entry, map[string]bool) {
+	var entries []sources.Entry
+	read := make(map[string]bool)
+
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for day := start; !day.After(to); day = day.AddDate(0, 0, 1) {
+		relPath := dc.notePath(day)
+		n, ok := index.notes[relPath]
+		if !ok || !o.allowed(index, relPath) {
+			continue
+		}
+
+		data, err := os.ReadFile(n.path)
+		if err != nil {
+			continue
+		}
+		read[relPath] = true
+
+		info, _ := index.info(relPath)
+		for _, item := range parseDailyNote(data) {
+			entries = append(entries, dailyItemEntry(o.vaultPath, relPath, day, item, info))
+		}
+	}
+
+	return entries, read
+}
+
+func dailyItemEntry(vaultPath, relPath string, day time.Time, item dailyItem, info noteInfo) sources.Entry {
+	meta := info.metadata()
+	content := item.Text
+	if item.Task != nil {
+		maps.Copy(meta, item.Task.Metadata())
+		content = item.Task.Content()
+	}
+	meta["kind"] = "daily"
+	meta["item"] = item.Kind
+	meta["date"] = day.Format("2006-01-02")
+	meta["file"] = path.Base(relPath)
+	meta["path"] = relPath
+	meta["line"] = strconv.Itoa(item.Line)
+	if item.Heading != "" {
+		meta["heading"] = item.Heading
+	}
+
+	ts := day
+	if item.Clock >= 0 {
+		ts = day.Add(item.Clock)
+		meta["time"] = ts.Format("15:04")
+	}
+
+	return sources.Entry{
+		Timestamp: ts,
+		Source:    "obsidian",
+		Location:  vaultPath,
+		Content:   content,
+		Metadata:  meta,
+	}
+}
@@ -0,0 +1,159 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFormatMoment(t *testing.T) {
+	day := time.Date(2026, time.April, 7, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"YYYY-MM-DD", "2026-04-07"},
+		{"YYYY/MM/YYYY-MM-DD", "2026/04/2026-04-07"},
+		{"DD.MM.YY", "07.04.26"},
+		{"dddd, MMMM Do YYYY", "Tuesday, April 7th 2026"},
+		{"ddd D MMM", "Tue 7 Apr"},
+		{"[Journal] YYYY-[W]ww", "Journal 2026-W15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := formatMoment(day, tt.format); got != tt.want {
+				t.Errorf("formatMoment(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd", 31: "31st"} {
+		if got := ordinal(n); got != want {
+			t.Errorf("ordinal(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestLoadDailyConfig(t *testing.T) {
+	vaultPath := setupVault(t)
+	cfgDir := filepath.Join(vaultPath, ".obsidian")
+
+	if _, ok := loadDailyConfig(vaultPath); ok {
+		t.Error("expected no daily config in a fresh vault")
+	}
+
+	if err := os.WriteFile(filepath.Join(cfgDir, "core-plugins.json"), []byte(`["daily-notes","graph"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dc, ok := loadDailyConfig(vaultPath)
+	if !ok || dc.Folder != "" || dc.Format != defaultDailyFormat {
+		t.Errorf("core plugin defaults: got %+v, %v", dc, ok)
+	}
+
+	if err := os.WriteFile(filepath.Join(cfgDir, "daily-notes.json"), []byte(`{"folder":"Daily/","format":"DD.MM.YYYY"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dc, _ = loadDailyConfig(vaultPath)
+	if dc.Folder != "Daily" || dc.Format != "DD.MM.YYYY" {
+		t.Errorf("daily-notes.json: got %+v", dc)
+	}
+
+	pluginDir := filepath.Join(cfgDir, "plugins", "periodic-notes")
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pluginDir, "data.json"), []byte(`{"daily":{"enabled":true,"folder":"Journal","format":"YYYY/YYYY-MM-DD"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dc, _ = loadDailyConfig(vaultPath)
+	if dc.Folder != "Journal" || dc.notePath(time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)) != "Journal/2026/2026-04-07.md" {
+		t.Errorf("periodic notes: got %+v", dc)
+	}
+}
+
+func TestParseDailyNote(t *testing.T) {
+	content := `---
+tags: daily
+---
+# Log
+- 09:15 standup
+- reviewed PR for [[Acme]]
+  - nested detail
+
+## Tasks
+- [x] send invoice ✅ 2026-04-07
+- [ ] call bank
+
+Some reflection.
+`
+	items := parseDailyNote([]byte(content))
+	if len(items) != 6 {
+		t.Fatalf("expected 6 items, got %d: %+v", len(items), items)
+	}
+
+	if items[0].Kind != "bullet" || items[0].Text != "standup" || items[0].Clock != 9*time.Hour+15*time.Minute {
+		t.Errorf("unexpected first item: %+v", items[0])
+	}
+	if items[2].Text != "nested detail" || items[2].Heading != "Log" {
+		t.Errorf("unexpected nested item: %+v", items[2])
+	}
+	if items[3].Kind != "task" || !items[3].Task.Done() || items[3].Heading != "Tasks" {
+		t.Errorf("unexpected task item: %+v", items[3])
+	}
+	if items[5].Kind != "text" || items[5].Text != "Some reflection." {
+		t.Errorf("unexpected text item: %+v", items[5])
+	}
+}
+
+func TestObsidianSource_DailyNotes(t *testing.T) {
+	vaultPath := setupVault(t)
+	if err := os.WriteFile(filepath.Join(vaultPath, ".obsidian", "daily-notes.json"), []byte(`{"folder":"Daily"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2026, time.April, 7, 0, 0, 0, 0, time.Local)
+	// Edited long after the day it describes: mtime must not matter.
+	writeNote(t, vaultPath, "Daily/2026-04-07.md", "# Work\n- 14:30 call with Acme\n- [x] ship pricing page\n", time.Now())
+	writeNote(t, vaultPath, "Daily/2026-04-09.md", "- outside range\n", time.Now())
+
+	entries, err := NewObsidianSource(vaultPath).GetEntries(t.Context(), day, day.AddDate(0, 0, 2).Add(-time.Second))
+	if err != nil {
+		t.Fatalf("GetEntries failed: %v", err)
+	}
+
+	if len(entries) != 2 {
+		for _, e := range entries {
+			t.Logf("  %s", e.Content)
+		}
+		t.Fatalf("expected 2 daily entries, got %d", len(entries))
+	}
+
+	var bullet, task bool
+	for _, e := range entries {
+		if e.Metadata["kind"] != "daily" || e.Metadata["date"] != "2026-04-07" {
+			t.Errorf("unexpected metadata: %v", e.Metadata)
+		}
+		switch e.Metadata["item"] {
+		case "bullet":
+			bullet = e.Content == "call with Acme" && e.Timestamp.Equal(day.Add(14*time.Hour+30*time.Minute)) && e.Metadata["heading"] == "Work"
+		case "task":
+			task = e.Content == "done: ship pricing page"
+		}
+	}
+	if !bullet || !task {
+		t.Errorf("missing expected bullet (%v) or task (%v) entry", bullet, task)
+	}
+
+	disabled, err := NewObsidianSource(vaultPath).WithDailyNotes(false).GetEntries(t.Context(), day, day.AddDate(0, 0, 2).Add(-time.Second))
+	if err != nil {
+		t.Fatalf("GetEntries failed: %v", err)
+	}
+	if len(disabled) != 0 {
+		t.Errorf("expected no entries with daily notes disabled, got %d", len(disabled))
+	}
+}
@@ -0,0 +1,63 @@
+package obsidian
+
+import (
+	"strings"
+)
+
+// Filters restricts which notes of a vault are reported. Folders are vault-
+// relative paths ("Journal", "2 Areas/Private"); tags are given without '#'
+// and also match nested tags ("work" matches "work/acme"). Empty lists impose
+// no restriction.
+type Filters struct {
+	IncludeFolders []string
+	ExcludeFolders []string
+	IncludeTags    []string
+	ExcludeTags    []string
+}
+
+// allowsPath applies the folder filters to a vault-relative note path.
+func (f Filters) allowsPath(relPath string) bool {
+	if len(f.IncludeFolders) > 0 && !inAnyFolder(relPath, f.IncludeFolders) {
+		return false
+	}
+	return !inAnyFolder(relPath, f.ExcludeFolders)
+}
+
+// allowsTags applies the tag filters to a note's tags. known is false when
+// the note can no longer be read (e.g. it was deleted); such notes cannot
+// prove an included tag, but are kept when only exclusions are configured.
+func (f Filters) allowsTags(tags []string, known bool) bool {
+	if len(f.IncludeTags) > 0 && (!known || !hasAnyTag(tags, f.IncludeTags)) {
+		return false
+	}
+	return !hasAnyTag(tags, f.ExcludeTags)
+}
+
+func inAnyFolder(relPath string, folders []string) bool {
+	for _, folder := range folders {
+		folder = strings.Trim(folder, "/")
+		if folder == "" {
+			continue
+		}
+		if relPath == folder || strings.HasPrefix(relPath, folder+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyTag(tags, wanted []string) bool {
+	for _, tag := range tags {
+		for _, w := range wanted {
+			w = strings.TrimPrefix(w, "#")
+			if strings.EqualFold(tag, w) || hasPrefixFold(tag, w+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
@@ -0,0 +1,175 @@
+package obsidian
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commitMarker prefixes the per-commit header line in our git log format so
+// it cannot be confused with diff content.
+const commitMarker = "\x1ecommit "
+
+// isGitVault reports whether the vault root is itself a git work tree.
+func isGitVault(vaultPath string) bool {
+	info, err := os.Stat(filepath.Join(vaultPath, ".git"))
+	return err == nil && info.IsDir()
+}
+
+// gitChanges reads note changes in [from, to] from the vault's git history.
+// Each commit that touches a note yields one change for that note.
+func gitChanges(ctx context.Context, vaultPath string, from, to time.Time) ([]change, error) {
+	cmd := exec.CommandContext(ctx, "git", "-C", vaultPath, "-c", "core.quotePath=false", "log",
+		"--format="+commitMarker+"%H|%at",
+		"--since="+from.Format(time.RFC3339),
+		"--until="+to.Format(time.RFC3339),
+		"-p", "-U0", "-M", "--no-color", "--no-ext-diff",
+		"--", "*.md")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault git history: %w", err)
+	}
+
+	changes := parseGitLog(output)
+
+	// Client-side filtering: --since/--until match committer dates, but we
+	// report author dates.
+	filtered := changes[:0]
+	for _, c := range changes {
+		if !c.Time.Before(from) && !c.Time.After(to) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered, nil
+}
+
+// parseGitLog parses `git log -p -U0 -M` output produced with commitMarker
+// headers into one change per file per commit.
+func parseGitLog(output []byte) []change {
+	var changes []change
+	var commit string
+	var commitTime time.Time
+	var cur *change
+	inHunk := false
+
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		// Obsidian "deletes" by moving notes into .trash.
+		if cur.Action == actionRenamed && isSkippedDir(topDir(cur.Path)) {
+			cur.Action = actionDeleted
+			cur.Path, cur.PreviousPath = cur.PreviousPath, ""
+		}
+		if strings.HasSuffix(cur.Path, ".md") && !isSkippedDir(topDir(cur.Path)) {
+			changes = append(changes, *cur)
+		}
+		cur = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, commitMarker):
+			flush()
+			parts := strings.SplitN(strings.TrimPrefix(line, commitMarker), "|", 2)
+			commit = parts[0]
+			commitTime = time.Time{}
+			if len(parts) == 2 {
+				if ts, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+					commitTime = time.Unix(ts, 0)
+				}
+			}
+			continue
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			cur = &change{
+				Time:       commitTime,
+				Action:     actionModified,
+				DetectedBy: "git",
+				Commit:     commit,
+			}
+			inHunk = false
+			continue
+		}
+
+		if cur == nil {
+			continue
+		}
+
+		if !inHunk {
+			switch {
+			case strings.HasPrefix(line, "new file mode"):
+				cur.Action = actionCreated
+			case strings.HasPrefix(line, "deleted file mode"):
+				cur.Action = actionDeleted
+			case strings.HasPrefix(line, "rename from "):
+				cur.Action = actionRenamed
+				cur.PreviousPath = strings.TrimPrefix(line, "rename from ")
+			case strings.HasPrefix(line, "rename to "):
+				cur.Path = strings.TrimPrefix(line, "rename to ")
+			case strings.HasPrefix(line, "--- a/"):
+				if cur.Path == "" {
+					cur.Path = trimDiffPath(strings.TrimPrefix(line, "--- a/"))
+				}
+			case strings.HasPrefix(line, "+++ b/"):
+				cur.Path = trimDiffPath(strings.TrimPrefix(line, "+++ b/"))
+			case strings.HasPrefix(line, "@@"):
+				inHunk = true
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			// next hunk of the same file
+		case strings.HasPrefix(line, "+"):
+			cur.LinesAdded++
+			if h := headingTitle(line[1:]); h != "" {
+				cur.HeadingsAdded = append(cur.HeadingsAdded, h)
+			}
+		case strings.HasPrefix(line, "-"):
+			cur.LinesRemoved++
+			if h := headingTitle(line[1:]); h != "" {
+				cur.HeadingsRemoved = append(cur.HeadingsRemoved, h)
+			}
+		}
+	}
+	flush()
+
+	// A heading that merely moved shows up as both added and removed, so
+	// drop those. Created and deleted notes list no sections at all.
+	for i := range changes {
+		c := &changes[i]
+		if c.Action == actionCreated || c.Action == actionDeleted {
+			c.HeadingsAdded, c.HeadingsRemoved = nil, nil
+			continue
+		}
+		added := diffHeadings(c.HeadingsAdded, c.HeadingsRemoved)
+		removed := diffHeadings(c.HeadingsRemoved, c.HeadingsAdded)
+		c.HeadingsAdded, c.HeadingsRemoved = added, removed
+	}
+
+	return changes
+}
+
+// topDir returns the first component of a slash-separated relative path.
+func topDir(p string) string {
+	dir, _, _ := strings.Cut(p, "/")
+	return dir
+}
+
+// trimDiffPath strips the trailing tab git appends to paths containing spaces.
+func trimDiffPath(p string) string {
+	return strings.TrimRight(p, "\t")
+}
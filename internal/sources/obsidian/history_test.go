@@ -0,0 +1,113 @@
+package obsidian
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestParseGitLog(t *testing.T) {
+	output := commitMarker + "abc123|1775559600\n" +
+		"\n" +
+		"diff --git a/1 Projects/Acme.md b/1 Projects/Acme.md\n" +
+		"index 111..222 100644\n" +
+		"--- a/1 Projects/Acme.md\n" +
+		"+++ b/1 Projects/Acme.md\n" +
+		"@@ -3,0 +4,3 @@\n" +
+		"+## Pricing\n" +
+		"+- 10k\n" +
+		"--- old bullet\n" +
+		"diff --git a/draft.md b/final.md\n" +
+		"similarity index 100%\n" +
+		"rename from draft.md\n" +
+		"rename to final.md\n" +
+		"diff --git a/Idea.md b/.trash/Idea.md\n" +
+		"similarity index 100%\n" +
+		"rename from Idea.md\n" +
+		"rename to .trash/Idea.md\n" +
+		"diff --git a/New.md b/New.md\n" +
+		"new file mode 100644\n" +
+		"--- /dev/null\n" +
+		"+++ b/New.md\n" +
+		"@@ -0,0 +1 @@\n" +
+		"+# New\n"
+
+	changes := parseGitLog([]byte(output))
+	if len(changes) != 4 {
+		t.Fatalf("expected 4 changes, got %d: %+v", len(changes), changes)
+	}
+
+	mod := changes[0]
+	if mod.Path != "1 Projects/Acme.md" || mod.Action != actionModified || mod.Commit != "abc123" {
+		t.Errorf("unexpected modified change: %+v", mod)
+	}
+	if mod.LinesAdded != 2 || mod.LinesRemoved != 1 {
+		t.Errorf("expected +2/-1 lines, got +%d/-%d", mod.LinesAdded, mod.LinesRemoved)
+	}
+	if len(mod.HeadingsAdded) != 1 || mod.HeadingsAdded[0] != "Pricing" {
+		t.Errorf("expected heading Pricing, got %v", mod.HeadingsAdded)
+	}
+	if !mod.Time.Equal(time.Unix(1775559600, 0)) {
+		t.Errorf("unexpected time: %v", mod.Time)
+	}
+
+	if changes[1].Action != actionRenamed || changes[1].PreviousPath != "draft.md" || changes[1].Path != "final.md" {
+		t.Errorf("unexpected rename: %+v", changes[1])
+	}
+	if changes[2].Action != actionDeleted || changes[2].Path != "Idea.md" {
+		t.Errorf("move to .trash should be a deletion: %+v", changes[2])
+	}
+	if changes[3].Action != actionCreated || changes[3].Path != "New.md" || len(changes[3].HeadingsAdded) != 0 {
+		t.Errorf("unexpected creation: %+v", changes[3])
+	}
+}
+
+func TestObsidianSource_GitVault(t *testing.T) {
+	vaultPath := setupVault(t)
+	gitRun(t, vaultPath, "init")
+	gitRun(t, vaultPath, "config", "user.name", "Test User")
+	gitRun(t, vaultPath, "config", "user.email", "test@example.com")
+
+	if err := os.WriteFile(filepath.Join(vaultPath, "Acme.md"), []byte("# Acme\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, vaultPath, "add", ".")
+	gitRun(t, vaultPath, "commit", "-m", "add acme")
+
+	if err := os.WriteFile(filepath.Join(vaultPath, "Acme.md"), []byte("# Acme\n\n## Pricing\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, vaultPath, "commit", "-am", "pricing")
+
+	source := NewObsidianSource(vaultPath).EnableSnapshots(filepath.Join(t.TempDir(), "unused.json"))
+	entries, err := source.GetEntries(t.Context(), time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetEntries failed: %v", err)
+	}
+
+	actions := make(map[string]string)
+	for _, e := range entries {
+		if e.Metadata["detected_by"] != "git" {
+			t.Errorf("expected detected_by=git, got %q", e.Metadata["detected_by"])
+		}
+		actions[e.Metadata["action"]] = e.Content
+	}
+	if actions["created"] != "Acme.md (created)" {
+		t.Errorf("unexpected created entry: %q", actions["created"])
+	}
+	if actions["modified"] != `Acme.md (modified): added section "Pricing", +2/-0 lines` {
+		t.Errorf("unexpected modified entry: %q", actions["modified"])
+	}
+}
@@ -0,0 +1,168 @@
+package obsidian
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// inlineTagRegex matches Obsidian inline tags such as #work or #project/acme.
+// Tags must be preceded by start of line or whitespace so that URL fragments
+// and headings ("# Title") are not mistaken for tags.
+var inlineTagRegex = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_/-]+)`)
+
+// wikilinkRegex matches [[Target]], [[Target|Alias]], [[Target#Heading]] and
+// embeds (![[Target]]), capturing the link target.
+var wikilinkRegex = regexp.MustCompile(`\[\[([^\]|#^]+)[^\]]*\]\]`)
+
+// noteInfo holds the metadata extracted from a note's content.
+type noteInfo struct {
+	Tags    []string // frontmatter and inline tags, without '#', deduplicated
+	Project string
+	Status  string
+	Aliases []string
+	Links   []string // outgoing wikilink targets, deduplicated
+}
+
+// metadata returns the note info as entry metadata keys. Empty fields are
+// omitted.
+func (n noteInfo) metadata() map[string]string {
+	meta := make(map[string]string)
+	if len(n.Tags) > 0 {
+		meta["tags"] = strings.Join(n.Tags, ",")
+	}
+	if n.Project != "" {
+		meta["project"] = n.Project
+	}
+	if n.Status != "" {
+		meta["status"] = n.Status
+	}
+	if len(n.Aliases) > 0 {
+		meta["aliases"] = strings.Join(n.Aliases, ",")
+	}
+	if len(n.Links) > 0 {
+		meta["links"] = strings.Join(n.Links, ",")
+	}
+	return meta
+}
+
+// parseNote extracts frontmatter fields, inline tags and outgoing links.
+// Malformed frontmatter is ignored; the body is still scanned.
+func parseNote(data []byte) noteInfo {
+	var info noteInfo
+
+	body := data
+	if fm, rest, ok := splitFrontmatter(data); ok {
+		body = rest
+		var raw map[string]any
+		if err := yaml.Unmarshal(fm, &raw); err == nil {
+			info.Tags = listField(raw["tags"], true)
+			if len(info.Tags) == 0 {
+				info.Tags = listField(raw["tag"], true)
+			}
+			info.Aliases = listField(raw["aliases"], false)
+			info.Project = stringField(raw["project"])
+			info.Status = stringField(raw["status"])
+		}
+	}
+	for i, t := range info.Tags {
+		info.Tags[i] = strings.TrimPrefix(t, "#")
+	}
+
+	inCode := false
+	for line := range strings.SplitSeq(string(body), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+		for _, m := range inlineTagRegex.FindAllStringSubmatch(line, -1) {
+			if isTag(m[1]) {
+				info.Tags = appendUnique(info.Tags, m[1])
+			}
+		}
+		for _, m := range wikilinkRegex.FindAllStringSubmatch(line, -1) {
+			info.Links = appendUnique(info.Links, strings.TrimSpace(m[1]))
+		}
+	}
+
+	return info
+}
+
+// splitFrontmatter separates a leading "---" YAML block from the body.
+func splitFrontmatter(data []byte) (fm, body []byte, ok bool) {
+	rest, found := bytes.CutPrefix(data, []byte("---\n"))
+	if !found {
+		rest, found = bytes.CutPrefix(data, []byte("---\r\n"))
+	}
+	if !found {
+		return nil, data, false
+	}
+
+	end := bytes.Index(rest, []byte("\n---"))
+	if end == -1 {
+		return nil, data, false
+	}
+
+	body = rest[end+len("\n---"):]
+	if i := bytes.IndexByte(body, '\n'); i != -1 {
+		body = body[i+1:]
+	} else {
+		body = nil
+	}
+	return rest[:end], body, true
+}
+
+// listField normalizes a YAML value that may be a list or a comma separated
+// string ("tags: work, acme" and "tags: [work, acme]" are both valid). Tags
+// cannot contain spaces, so for them spaces separate values too.
+func listField(v any, splitSpaces bool) []string {
+	var result []string
+	switch val := v.(type) {
+	case []any:
+		for _, item := range val {
+			if s := stringField(item); s != "" {
+				result = appendUnique(result, s)
+			}
+		}
+	case string:
+		for _, s := range strings.FieldsFunc(val, func(r rune) bool { return r == ',' || (splitSpaces && r == ' ') }) {
+			if s = strings.TrimSpace(s); s != "" {
+				result = appendUnique(result, s)
+			}
+		}
+	}
+	return result
+}
+
+// stringField converts a scalar YAML value to a string. Wikilink values such
+// as "[[Acme]]" are reduced to their target.
+func stringField(v any) string {
+	if v == nil {
+		return ""
+	}
+	s := strings.TrimSpace(fmt.Sprint(v))
+	if m := wikilinkRegex.FindStringSubmatch(s); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return s
+}
+
+// isTag reports whether s is a valid Obsidian tag: at least one character
+// that is not a digit, so "#123" (issue references) is not a tag.
+func isTag(s string) bool {
+	return strings.ContainsFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+}
+
+func appendUnique(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
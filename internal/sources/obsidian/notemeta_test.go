@@ -0,0 +1,81 @@
+package obsidian
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseNote(t *testing.T) {
+	content := `---
+tags: [work, "#client/acme"]
+project: "[[Acme]]"
+status: active
+aliases: Acme deal, Pricing
+---
+# Acme proposal
+
+Talked to #sales about [[Pricing Model|pricing]] and ![[diagram.png]].
+See [[Acme#Contacts]] and issue #123.
+
+` + "```" + `
+#not-a-tag [[not-a-link]]
+` + "```" + `
+`
+
+	info := parseNote([]byte(content))
+
+	wantTags := []string{"work", "client/acme", "sales"}
+	if !slices.Equal(info.Tags, wantTags) {
+		t.Errorf("tags = %v, want %v", info.Tags, wantTags)
+	}
+	if info.Project != "Acme" {
+		t.Errorf("project = %q, want Acme", info.Project)
+	}
+	if info.Status != "active" {
+		t.Errorf("status = %q, want active", info.Status)
+	}
+	if !slices.Equal(info.Aliases, []string{"Acme deal", "Pricing"}) {
+		t.Errorf("aliases = %v", info.Aliases)
+	}
+	wantLinks := []string{"Pricing Model", "diagram.png", "Acme"}
+	if !slices.Equal(info.Links, wantLinks) {
+		t.Errorf("links = %v, want %v", info.Links, wantLinks)
+	}
+}
+
+func TestParseNote_NoFrontmatter(t *testing.T) {
+	info := parseNote([]byte("plain #idea\n"))
+	if !slices.Equal(info.Tags, []string{"idea"}) {
+		t.Errorf("tags = %v", info.Tags)
+	}
+	if len(info.metadata()) != 1 {
+		t.Errorf("expected only tags in metadata, got %v", info.metadata())
+	}
+}
+
+func TestFilters(t *testing.T) {
+	f := Filters{
+		ExcludeFolders: []string{"Journal/"},
+		IncludeTags:    []string{"work"},
+		ExcludeTags:    []string{"#private"},
+	}
+
+	if f.allowsPath("Journal/2026-04-07.md") {
+		t.Error("Journal should be excluded")
+	}
+	if !f.allowsPath("Journaling tips.md") {
+		t.Error("folder prefix must match whole path components")
+	}
+	if !f.allowsTags([]string{"work/acme"}, true) {
+		t.Error("nested tag should match include filter")
+	}
+	if f.allowsTags([]string{"work", "Private"}, true) {
+		t.Error("excluded tag should win, case-insensitively")
+	}
+	if f.allowsTags(nil, false) {
+		t.Error("unreadable notes cannot satisfy an include filter")
+	}
+	if !(Filters{ExcludeTags: []string{"private"}}).allowsTags(nil, false) {
+		t.Error("unreadable notes pass exclude-only filters")
+	}
+}
@@ -1,29 +1,79 @@
 package obsidian
 
 import (
+	"context"
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/sources/tasks"
 )
 
 // ObsidianSource implements the Source interface for Obsidian vaults.
-// It tracks which markdown files were created or modified in a time range.
+// It reports which notes were created, modified, renamed or deleted in a time
+// range, including which sections changed. Vaults under git are read from
+// their history; other vaults are compared against a content snapshot taken
+// on the previous run.
 type ObsidianSource struct {
-	vaultPath string
+	vaultPath    string
+	snapshotPath string           // optional: enables snapshot-based change detection
+	now          func() time.Time // injectable clock for snapshot timestamps
+
+	filters    Filters // folder and tag restrictions
+	dailyNotes bool    // extract daily note content when the vault configures daily notes
+
+	taskMode  bool   // also emit completed/due tasks from vault notes
+	taskState string // optional state file for detecting checkbox changes
 }
 
 // NewObsidianSource creates a new Obsidian vault source.
 func NewObsidianSource(vaultPath string) *ObsidianSource {
 	return &ObsidianSource{
-		vaultPath: vaultPath,
+		vaultPath:  vaultPath,
+		now:        time.Now,
+		dailyNotes: true,
 	}
 }
 
+// EnableSnapshots stores a content snapshot of the vault at path after each
+// run. Comparing against it reveals real creations, renames and deletions and
+// which sections of a note changed. Vaults under git ignore this and use
+// their history instead.
+func (o *ObsidianSource) EnableSnapshots(path string) *ObsidianSource {
+	o.snapshotPath = path
+	return o
+}
+
+// WithFilters restricts the reported notes by folder and tag. The .obsidian,
+// .trash and .git folders are always skipped.
+func (o *ObsidianSource) WithFilters(f Filters) *ObsidianSource {
+	o.filters = f
+	return o
+}
+
+// WithDailyNotes turns daily note extraction on or off. It is on by default
+// and only takes effect in vaults that configure the Daily Notes or Periodic
+// Notes plugin.
+func (o *ObsidianSource) WithDailyNotes(enabled bool) *ObsidianSource {
+	o.dailyNotes = enabled
+	return o
+}
+
+// EnableTasks makes the source emit Obsidian Tasks entries alongside file
+// changes: tasks completed in the range (via "✅ date" or a detected checkbox
+// flip) and open tasks due in it. statePath, if non-empty, stores task status
+// between runs.
+func (o *ObsidianSource) EnableTasks(statePath string) *ObsidianSource {
+	o.taskMode = true
+	o.taskState = statePath
+	return o
+}
+
 func (o *ObsidianSource) Type() string {
 	return "obsidian"
 }
@@ -50,24 +100,160 @@ func (o *ObsidianSource) Validate() error {
 	return nil
 }
 
-func (o *ObsidianSource) GetEntries(from, to time.Time) ([]sources.Entry, error) {
+func (o *ObsidianSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	var state *tasks.State
+	if o.taskMode && o.taskState != "" {
+		var err error
+		state, err = tasks.LoadState(o.taskState)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	notes, err := o.walkNotes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk vault: %w", err)
+	}
+
+	index := newNoteIndex(notes)
+
 	var entries []sources.Entry
 
+	// Daily notes are the work log: report their content for every day in
+	// range, regardless of when the file was last touched.
+	var dailyRead map[string]bool
+	if o.dailyNotes {
+		if dc, ok := loadDailyConfig(o.vaultPath); ok {
+			var daily []sources.Entry
+			daily, dailyRead = o.dailyEntries(dc, from, to, index)
+			entries = append(entries, daily...)
+		}
+	}
+
+	// Tasks ticked in range may live in notes edited after it; notes
+	// untouched since the range started only contribute open tasks due in
+	// it. Daily notes already report their tasks.
+	if o.taskMode {
+		for _, n := range notes {
+			if dailyRead[n.relPath] || !o.allowed(index, n.relPath) {
+				continue
+			}
+			info, _ := index.info(n.relPath)
+			entries = append(entries, o.taskEntries(n, info, from, to, state)...)
+		}
+		if err := state.Save(); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := o.detectChanges(ctx, notes, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Notes modified in range without a detected change (uncommitted edits,
+	// or no snapshot yet) fall back to a plain mtime-based entry.
+	covered := make(map[string]bool, len(changes))
+	for _, c := range changes {
+		covered[c.Path] = true
+	}
+	for _, n := range notes {
+		if covered[n.relPath] || n.modTime.Before(from) || n.modTime.After(to) {
+			continue
+		}
+		changes = append(changes, change{
+			Time:       n.modTime,
+			Action:     actionModified,
+			Path:       n.relPath,
+			DetectedBy: "mtime",
+		})
+	}
+
+	for _, c := range changes {
+		if !o.allowed(index, c.Path) {
+			continue
+		}
+		// A daily note's content says more than "modified".
+		if c.Action == actionModified && dailyRead[c.Path] {
+			continue
+		}
+		entry := c.toEntry(o.vaultPath)
+		if info, ok := index.info(c.Path); ok {
+			maps.Copy(entry.Metadata, info.metadata())
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// allowed applies the configured folder and tag filters to a note path.
+// Notes that no longer exist are checked against folder filters only.
+func (o *ObsidianSource) allowed(index *noteIndex, relPath string) bool {
+	if !o.filters.allowsPath(relPath) {
+		return false
+	}
+	info, ok := index.info(relPath)
+	return o.filters.allowsTags(info.Tags, ok)
+}
+
+// noteIndex gives lazy, cached access to the parsed metadata of the notes
+// currently in the vault.
+type noteIndex struct {
+	notes map[string]note
+	infos map[string]noteInfo
+}
+
+func newNoteIndex(notes []note) *noteIndex {
+	idx := &noteIndex{
+		notes: make(map[string]note, len(notes)),
+		infos: make(map[string]noteInfo),
+	}
+	for _, n := range notes {
+		idx.notes[n.relPath] = n
+	}
+	return idx
+}
+
+// info returns the parsed metadata of a note, or false if the note is not in
+// the vault (anymore) or cannot be read.
+func (idx *noteIndex) info(relPath string) (noteInfo, bool) {
+	if info, ok := idx.infos[relPath]; ok {
+		return info, true
+	}
+	n, ok := idx.notes[relPath]
+	if !ok {
+		return noteInfo{}, false
+	}
+	data, err := os.ReadFile(n.path)
+	if err != nil {
+		return noteInfo{}, false
+	}
+	info := parseNote(data)
+	idx.infos[relPath] = info
+	return info, true
+}
+
+// walkNotes returns all markdown notes in the vault, skipping Obsidian's own
+// config and trash folders.
+func (o *ObsidianSource) walkNotes(ctx context.Context) ([]note, error) {
+	var notes []note
+
 	err := filepath.WalkDir(o.vaultPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-		// Skip directories
 		if d.IsDir() {
-			// Skip .obsidian and .trash folders
-			if d.Name() == ".obsidian" || d.Name() == ".trash" {
+			if isSkippedDir(d.Name()) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		// Only process markdown files
 		if !strings.HasSuffix(path, ".md") {
 			return nil
 		}
@@ -77,46 +263,92 @@ func (o *ObsidianSource) GetEntries(from, to time.Time) ([]sources.Entry, error)
 			return nil
 		}
 
-		modTime := info.ModTime()
-
-		// Check if file was modified in the time range
-		if modTime.Before(from) || modTime.After(to) {
-			return nil
-		}
-
-		// Get relative path within vault
 		relPath, err := filepath.Rel(o.vaultPath, path)
 		if err != nil {
 			relPath = path
 		}
 
-		// Determine if file was created or modified in this period
-		// We consider it "created" if mtime is very close to the period start,
-		// otherwise "modified"
-		action := "modified"
-		if modTime.Sub(from) < 5*time.Minute {
-			action = "created"
+		notes = append(notes, note{
+			path:    path,
+			relPath: filepath.ToSlash(relPath),
+			modTime: info.ModTime(),
+			size:    info.Size(),
+		})
+		return nil
+	})
+
+	return notes, err
+}
+
+// detectChanges returns content-level changes in [from, to]. Vaults under
+// git are read from their history; otherwise the vault is diffed against the
+// snapshot from the previous run, if snapshots are enabled. Only ranges that
+// reach the present take a new snapshot; recaps of the past read the changes
+// recorded so far.
+func (o *ObsidianSource) detectChanges(ctx context.Context, notes []note, from, to time.Time) ([]change, error) {
+	if isGitVault(o.vaultPath) {
+		return gitChanges(ctx, o.vaultPath, from, to)
+	}
+
+	if o.snapshotPath == "" {
+		return nil, nil
+	}
+
+	snap, err := loadSnapshot(o.snapshotPath)
+	if err != nil {
+		return nil, err
+	}
+	if now := o.now(); !to.Before(now) {
+		snap.update(notes, now)
+		if err := snap.save(o.snapshotPath); err != nil {
+			return nil, err
 		}
+	}
 
-		entry := sources.Entry{
-			Timestamp: modTime,
-			Source:    "obsidian",
-			Location:  o.vaultPath,
-			Content:   fmt.Sprintf("%s (%s)", relPath, action),
-			Metadata: map[string]string{
-				"file":   filepath.Base(path),
-				"path":   relPath,
-				"action": action,
-			},
+	var changes []change
+	for _, c := range snap.Changes {
+		if !c.Time.Before(from) && !c.Time.After(to) {
+			changes = append(changes, c)
 		}
+	}
+	return changes, nil
+}
 
-		entries = append(entries, entry)
-		return nil
-	})
+// isSkippedDir reports whether a vault folder holds no user notes.
+func isSkippedDir(name string) bool {
+	return name == ".obsidian" || name == ".trash" || name == ".git"
+}
 
+// taskEntries parses the tasks in one note and converts the relevant ones
+// into entries. Unreadable notes yield no entries.
+func (o *ObsidianSource) taskEntries(n note, info noteInfo, from, to time.Time, state *tasks.State) []sources.Entry {
+	data, err := os.ReadFile(n.path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to walk vault: %w", err)
+		return nil
 	}
 
-	return entries, nil
+	list := tasks.ParseLines(strings.Split(string(data), "\n"))
+	var relevant []tasks.Task
+	if n.modTime.Before(from) {
+		relevant = tasks.Due(list, from, to)
+		state.Keep(n.relPath, list)
+	} else {
+		relevant = tasks.Relevant(n.relPath, list, n.modTime, from, to, state)
+	}
+
+	var entries []sources.Entry
+	for _, t := range relevant {
+		meta := info.metadata()
+		maps.Copy(meta, t.Metadata())
+		meta["file"] = filepath.Base(n.path)
+		meta["path"] = n.relPath
+		entries = append(entries, sources.Entry{
+			Timestamp: t.Timestamp(),
+			Source:    "obsidian",
+			Location:  o.vaultPath,
+			Content:   fmt.Sprintf("%s: %s", n.relPath, t.Content()),
+			Metadata:  meta,
+		})
+	}
+	return entries
 }
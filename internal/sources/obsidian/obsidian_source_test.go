@@ -76,7 +76,7 @@ func TestObsidianSource_GetEntries(t *testing.T) {
 	from := yesterday.Add(-1 * time.Hour)
 	to := now.Add(1 * time.Hour)
 
-	entries, err := source.GetEntries(from, to)
+	entries, err := source.GetEntries(t.Context(), from, to)
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -148,7 +148,7 @@ func TestObsidianSource_SkipHiddenFolders(t *testing.T) {
 	from := now.Add(-1 * time.Hour)
 	to := now.Add(1 * time.Hour)
 
-	entries, err := source.GetEntries(from, to)
+	entries, err := source.GetEntries(t.Context(), from, to)
 	if err != nil {
 		t.Fatalf("GetEntries failed: %v", err)
 	}
@@ -162,3 +162,92 @@ func TestObsidianSource_SkipHiddenFolders(t *testing.T) {
 		t.Errorf("expected visible.md, got %s", entries[0].Metadata["file"])
 	}
 }
+
+func TestObsidianSource_GetEntries_TaskMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	vaultPath := filepath.Join(tmpDir, "test-vault")
+	if err := os.MkdirAll(filepath.Join(vaultPath, ".obsidian"), 0755); err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	content := "- [x] send proposal ✅ " + yesterday.Format("2006-01-02") + "\n- [ ] follow up\n"
+
+	// The note was edited today, after the queried range ended.
+	notePath := filepath.Join(vaultPath, "Acme.md")
+	if err := os.WriteFile(notePath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write note: %v", err)
+	}
+
+	source := NewObsidianSource(vaultPath).EnableTasks(filepath.Join(tmpDir, "state.json"))
+
+	from := time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, 1).Add(-time.Second)
+
+	entries, err := source.GetEntries(t.Context(), from, to)
+	if err != nil {
+		t.Fatalf("GetEntries failed: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 task entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Content != "Acme.md: done: send proposal" {
+		t.Errorf("unexpected content: %q", e.Content)
+	}
+	if e.Metadata["path"] != "Acme.md" || e.Metadata["done_date"] != yesterday.Format("2006-01-02") {
+		t.Errorf("unexpected metadata: %v", e.Metadata)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "state.json")); err != nil {
+		t.Errorf("expected task state to be saved: %v", err)
+	}
+}
+
+func TestObsidianSource_MetadataAndFilters(t *testing.T) {
+	vaultPath := setupVault(t)
+	now := time.Now()
+
+	writeNote(t, vaultPath, "Projects/Acme.md", "---\ntags: [work]\nproject: Acme\n---\nSee [[Pricing]]\n", now)
+	writeNote(t, vaultPath, "Journal/2026-04-07.md", "#work dear diary\n", now)
+	writeNote(t, vaultPath, "Ideas.md", "#private\n", now)
+
+	source := NewObsidianSource(vaultPath).WithFilters(Filters{
+		ExcludeFolders: []string{"Journal"},
+		ExcludeTags:    []string{"private"},
+	})
+
+	entries, err := source.GetEntries(t.Context(), now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetEntries failed: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	meta := entries[0].Metadata
+	if meta["path"] != "Projects/Acme.md" || meta["tags"] != "work" || meta["project"] != "Acme" || meta["links"] != "Pricing" {
+		t.Errorf("unexpected metadata: %v", meta)
+	}
+}
+
+func TestObsidianSource_TaskModeUntouchedNote(t *testing.T) {
+	vaultPath := setupVault(t)
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	due := from.AddDate(0, 0, 3).Format("2006-01-02")
+
+	// Last edited a month ago, long before the range.
+	writeNote(t, vaultPath, "Backlog.md", "- [ ] renew certificate 📅 "+due+"\n- [ ] someday\n", from.AddDate(0, -1, 0))
+
+	source := NewObsidianSource(vaultPath).EnableTasks(filepath.Join(t.TempDir(), "state.json"))
+	entries, err := source.GetEntries(t.Context(), from, from.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("GetEntries failed: %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].Content != "Backlog.md: open: renew certificate (due "+due+")" {
+		t.Errorf("expected the open task due in range, got %+v", entries)
+	}
+}
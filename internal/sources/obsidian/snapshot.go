@@ -0,0 +1,252 @@
+package obsidian
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// changeRetention bounds how long detected changes are kept in the snapshot
+// file. Recaps rarely look back further than a year.
+const changeRetention = 366 * 24 * time.Hour
+
+// note is a markdown file found while walking the vault.
+type note struct {
+	path    string // absolute path
+	relPath string // path relative to the vault root
+	modTime time.Time
+	size    int64
+}
+
+// fileState is the content fingerprint of one note at snapshot time.
+type fileState struct {
+	Hash     string    `json:"hash"`
+	ModTime  time.Time `json:"mtime"`
+	Size     int64     `json:"size"`
+	Headings []string  `json:"headings,omitempty"`
+	Lines    []uint32  `json:"lines,omitempty"` // per-line hashes for counting changed lines
+}
+
+// snapshot is the persisted state of a vault: the fingerprint of every note
+// as of the last run, plus the changes detected between runs.
+type snapshot struct {
+	Taken   time.Time            `json:"taken"`
+	Files   map[string]fileState `json:"files"`
+	Changes []change             `json:"changes,omitempty"`
+}
+
+// loadSnapshot reads the snapshot file at path. A missing file yields an
+// empty snapshot with a zero Taken time.
+func loadSnapshot(path string) (*snapshot, error) {
+	snap := &snapshot{Files: make(map[string]fileState)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snap, nil
+		}
+		return nil, fmt.Errorf("failed to read vault snapshot: %w", err)
+	}
+
+	if err := json.Unmarshal(data, snap); err != nil {
+		return nil, fmt.Errorf("failed to parse vault snapshot: %w", err)
+	}
+	if snap.Files == nil {
+		snap.Files = make(map[string]fileState)
+	}
+	return snap, nil
+}
+
+func (s *snapshot) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vault snapshot: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write vault snapshot: %w", err)
+	}
+	return nil
+}
+
+// readFileState fingerprints a note. Unchanged notes (same mtime and size as
+// in prev) are not re-read.
+func readFileState(n note, prev fileState, havePrev bool) (fileState, error) {
+	if havePrev && prev.ModTime.Equal(n.modTime) && prev.Size == n.size {
+		return prev, nil
+	}
+
+	data, err := os.ReadFile(n.path)
+	if err != nil {
+		return fileState{}, err
+	}
+
+	sum := sha256.Sum256(data)
+	fs := fileState{
+		Hash:    hex.EncodeToString(sum[:]),
+		ModTime: n.modTime,
+		Size:    n.size,
+	}
+
+	for line := range strings.SplitSeq(string(data), "\n") {
+		if h := headingTitle(line); h != "" {
+			fs.Headings = append(fs.Headings, h)
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		hasher := fnv.New32a()
+		_, _ = hasher.Write([]byte(line))
+		fs.Lines = append(fs.Lines, hasher.Sum32())
+	}
+
+	return fs, nil
+}
+
+// update fingerprints the current notes, records the changes since the
+// previous snapshot and replaces the stored file states. The first run only
+// establishes a baseline and records no changes.
+func (s *snapshot) update(notes []note, now time.Time) {
+	current := make(map[string]fileState, len(notes))
+	mtimes := make(map[string]time.Time, len(notes))
+	for _, n := range notes {
+		prev, ok := s.Files[n.relPath]
+		fs, err := readFileState(n, prev, ok)
+		if err != nil {
+			continue
+		}
+		current[n.relPath] = fs
+		mtimes[n.relPath] = n.modTime
+	}
+
+	if !s.Taken.IsZero() {
+		s.Changes = append(s.Changes, diffSnapshots(s.Files, current, mtimes, s.Taken, now)...)
+	}
+
+	cutoff := now.Add(-changeRetention)
+	s.Changes = slices.DeleteFunc(s.Changes, func(c change) bool {
+		return c.Time.Before(cutoff)
+	})
+
+	s.Files = current
+	s.Taken = now
+}
+
+// diffSnapshots compares two sets of file states. Notes that vanished and
+// reappeared elsewhere with identical content are reported as renames.
+// Deletions and renames carry no mtime of their own, so they are only known
+// to have happened between the snapshots, in (prevTaken, now], unless the
+// renamed note was touched afterwards.
+func diffSnapshots(prev, current map[string]fileState, mtimes map[string]time.Time, prevTaken, now time.Time) []change {
+	var created, deleted []string
+	var changes []change
+
+	for path, cur := range current {
+		old, ok := prev[path]
+		if !ok {
+			created = append(created, path)
+			continue
+		}
+		if old.Hash == cur.Hash {
+			continue
+		}
+		added, removed := diffLines(old.Lines, cur.Lines)
+		changes = append(changes, change{
+			Time:            mtimes[path],
+			Action:          actionModified,
+			Path:            path,
+			HeadingsAdded:   diffHeadings(cur.Headings, old.Headings),
+			HeadingsRemoved: diffHeadings(old.Headings, cur.Headings),
+			LinesAdded:      added,
+			LinesRemoved:    removed,
+			DetectedBy:      "snapshot",
+		})
+	}
+	for path := range prev {
+		if _, ok := current[path]; !ok {
+			deleted = append(deleted, path)
+		}
+	}
+
+	slices.Sort(created)
+	slices.Sort(deleted)
+
+	renamedFrom := make(map[string]bool)
+	for _, path := range created {
+		cur := current[path]
+		c := change{
+			Time:       mtimes[path],
+			Action:     actionCreated,
+			Path:       path,
+			DetectedBy: "snapshot",
+		}
+		for _, old := range deleted {
+			if !renamedFrom[old] && prev[old].Hash == cur.Hash {
+				renamedFrom[old] = true
+				c.Action = actionRenamed
+				c.PreviousPath = old
+				if !c.Time.After(prevTaken) {
+					c.Time, c.Since = now, prevTaken
+				}
+				break
+			}
+		}
+		changes = append(changes, c)
+	}
+	for _, path := range deleted {
+		if renamedFrom[path] {
+			continue
+		}
+		changes = append(changes, change{
+			Time:       now,
+			Since:      prevTaken,
+			Action:     actionDeleted,
+			Path:       path,
+			DetectedBy: "snapshot",
+		})
+	}
+
+	return changes
+}
+
+// diffHeadings returns the headings in a that do not appear in b.
+func diffHeadings(a, b []string) []string {
+	var result []string
+	for _, h := range a {
+		if !slices.Contains(b, h) {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// diffLines counts lines added and removed between two line-hash lists,
+// treating them as multisets. Moved lines count as neither.
+func diffLines(old, cur []uint32) (added, removed int) {
+	counts := make(map[uint32]int, len(old))
+	for _, h := range old {
+		counts[h]++
+	}
+	for _, h := range cur {
+		if counts[h] > 0 {
+			counts[h]--
+			continue
+		}
+		added++
+	}
+	for _, n := range counts {
+		removed += n
+	}
+	return added, removed
+}
@@ -0,0 +1,168 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupVault(t *testing.T) string {
+	t.Helper()
+
+	vaultPath := filepath.Join(t.TempDir(), "vault")
+	if err := os.MkdirAll(filepath.Join(vaultPath, ".obsidian"), 0755); err != nil {
+		t.Fatalf("failed to create vault: %v", err)
+	}
+	return vaultPath
+}
+
+func writeNote(t *testing.T, vaultPath, rel, content string, modTime time.Time) {
+	t.Helper()
+
+	path := filepath.Join(vaultPath, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir for %s: %v", rel, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", rel, err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("failed to change times for %s: %v", rel, err)
+	}
+}
+
+func TestObsidianSource_Snapshots(t *testing.T) {
+	vaultPath := setupVault(t)
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.json")
+
+	base := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	writeNote(t, vaultPath, "Acme proposal.md", "# Acme\n\nintro\n", base)
+	writeNote(t, vaultPath, "old name.md", "same content\n", base)
+	writeNote(t, vaultPath, "gone.md", "bye\n", base)
+
+	clock := base.Add(time.Hour)
+	source := NewObsidianSource(vaultPath).EnableSnapshots(snapshotPath)
+	source.now = func() time.Time { return clock }
+
+	// Baseline run: no snapshot yet, nothing modified in range.
+	entries, err := source.GetEntries(t.Context(), base.Add(30*time.Minute), clock)
+	if err != nil {
+		t.Fatalf("GetEntries failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no entries on baseline run, got %d", len(entries))
+	}
+
+	// Edit, rename, delete and create notes.
+	edited := base.Add(2 * time.Hour)
+	writeNote(t, vaultPath, "Acme proposal.md", "# Acme\n\nintro\n\n## Pricing\n\n- 10k\n- 20k\n", edited)
+	if err := os.Rename(filepath.Join(vaultPath, "old name.md"), filepath.Join(vaultPath, "new name.md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(vaultPath, "gone.md")); err != nil {
+		t.Fatal(err)
+	}
+	writeNote(t, vaultPath, "Fresh.md", "new\n", edited)
+
+	clock = base.Add(3 * time.Hour)
+	entries, err = source.GetEntries(t.Context(), base.Add(90*time.Minute), clock)
+	if err != nil {
+		t.Fatalf("GetEntries failed: %v", err)
+	}
+
+	byPath := make(map[string]string)
+	for _, e := range entries {
+		byPath[e.Metadata["path"]] = e.Content
+		approximate := e.Metadata["path"] == "new name.md" || e.Metadata["path"] == "gone.md"
+		if (e.Metadata["time_approximate"] == "true") != approximate {
+			t.Errorf("%s: unexpected time_approximate=%q", e.Metadata["path"], e.Metadata["time_approximate"])
+		}
+		if e.Metadata["detected_by"] != "snapshot" {
+			t.Errorf("%s: expected detected_by=snapshot, got %q", e.Metadata["path"], e.Metadata["detected_by"])
+		}
+	}
+
+	// The rename kept the old mtime and the deletion has none, so both are
+	// only known to have happened since the previous snapshot.
+	since := base.Add(time.Hour).Format("2006-01-02 15:04")
+	want := map[string]string{
+		"Acme proposal.md": `Acme proposal.md (modified): added section "Pricing", +3/-0 lines`,
+		"new name.md":      "old name.md -> new name.md (renamed): at some point since " + since,
+		"gone.md":          "gone.md (deleted): at some point since " + since,
+		"Fresh.md":         "Fresh.md (created)",
+	}
+	if len(byPath) != len(want) {
+		t.Errorf("expected %d entries, got %d: %v", len(want), len(byPath), byPath)
+	}
+	for path, content := range want {
+		if byPath[path] != content {
+			t.Errorf("%s: got %q, want %q", path, byPath[path], content)
+		}
+	}
+
+	// Detected changes are remembered for later recaps of the same range.
+	clock = base.Add(4 * time.Hour)
+	entries, err = source.GetEntries(t.Context(), base.Add(90*time.Minute), base.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("GetEntries failed: %v", err)
+	}
+	if len(entries) != len(want) {
+		t.Errorf("expected %d remembered entries, got %d", len(want), len(entries))
+	}
+
+	// A recap of the past neither records new changes nor moves the
+	// baseline of the next diff.
+	writeNote(t, vaultPath, "Fresh.md", "new\nand more\n", base.Add(210*time.Minute))
+	if _, err := source.GetEntries(t.Context(), base, base.Add(3*time.Hour)); err != nil {
+		t.Fatalf("GetEntries failed: %v", err)
+	}
+	snap, err := loadSnapshot(snapshotPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !snap.Taken.Equal(base.Add(3*time.Hour)) || len(snap.Changes) != len(want) {
+		t.Errorf("past recap updated the snapshot: taken %s, %d changes", snap.Taken, len(snap.Changes))
+	}
+}
+
+func TestObsidianSource_NoCreatedHeuristic(t *testing.T) {
+	vaultPath := setupVault(t)
+
+	from := time.Now().Add(-time.Hour)
+	writeNote(t, vaultPath, "note.md", "content", from.Add(time.Minute))
+
+	entries, err := NewObsidianSource(vaultPath).GetEntries(t.Context(), from, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetEntries failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Metadata["action"] != "modified" || entries[0].Metadata["detected_by"] != "mtime" {
+		t.Errorf("without history, expected mtime-based 'modified', got %v", entries[0].Metadata)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	added, removed := diffLines([]uint32{1, 2, 3, 3}, []uint32{3, 1, 4, 5})
+	if added != 2 || removed != 2 {
+		t.Errorf("diffLines = +%d/-%d, want +2/-2", added, removed)
+	}
+}
+
+func TestChangeContent(t *testing.T) {
+	c := change{
+		Action:          actionModified,
+		Path:            "1 Projects/Acme.md",
+		HeadingsAdded:   []string{"Pricing"},
+		HeadingsRemoved: []string{"Draft"},
+		LinesAdded:      12,
+		LinesRemoved:    3,
+	}
+	got := c.content()
+	if !strings.Contains(got, `added section "Pricing"`) || !strings.Contains(got, `removed section "Draft"`) || !strings.Contains(got, "+12/-3 lines") {
+		t.Errorf("unexpected content: %q", got)
+	}
+}
@@ -0,0 +1,408 @@
+package org
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/sources/timetrack"
+)
+
+// defaultTodoKeywords are the workflow states recognised when a file does not
+// declare its own via #+TODO.
+var defaultTodoKeywords = []string{"TODO", "NEXT", "STARTED", "WAITING", "HOLD", "DONE", "CANCELLED", "CANCELED"}
+
+var (
+	headingRegex  = regexp.MustCompile(`^(\*+)\s+(.*?)\s*$`)
+	tagsRegex     = regexp.MustCompile(`\s+(:[^\s:]+(?::[^\s:]+)*:)$`)
+	priorityRegex = regexp.MustCompile(`^\[#[A-Za-z0-9]\]\s*`)
+	todoDeclRegex = regexp.MustCompile(`^#\+(?:SEQ_|TYP_)?TODO:\s*(.*)$`)
+	closedRegex   = regexp.MustCompile(`CLOSED:\s*(\[[^\]]+\])`)
+	clockRegex    = regexp.MustCompile(`^\s*CLOCK:\s*(\[[^\]]+\])--(\[[^\]]+\])`)
+	stateRegex    = regexp.MustCompile(`^\s*-\s+State\s+"([^"]+)"\s+from\s+"([^"]*)"\s+(\[[^\]]+\])`)
+	timestampBody = regexp.MustCompile(`^[\[<](\d{4}-\d{2}-\d{2})(?:\s+[^\d\s\]>]+)?(?:\s+(\d{1,2}:\d{2})(?::(\d{2}))?)?`)
+	datetreeRegex = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\b`)
+	clockPrefix   = regexp.MustCompile(`^(\d{1,2}):(\d{2})\s+`)
+	journalFile   = regexp.MustCompile(`^(\d{8})(?:\.org)?$`)
+)
+
+// Item kinds produced by the parser.
+const (
+	KindClosed  = "closed"  // heading closed with a CLOSED: timestamp
+	KindClock   = "clock"   // CLOCK: interval logged under a heading
+	KindState   = "state"   // state change recorded in a logbook
+	KindJournal = "journal" // entry under a datetree day or in an org-journal file
+)
+
+// Item is one dated event parsed from an org file.
+type Item struct {
+	Kind    string
+	Title   string   // heading text without keyword, priority and tags
+	State   string   // current TODO keyword of the heading, or the new state for KindState
+	From    string   // previous state for KindState
+	Tags    []string // heading tags
+	Outline []string // titles of the parent headings
+	Time    time.Time
+	End     time.Time // end of a CLOCK interval
+	Line    int
+
+	heading int // line of the owning heading
+}
+
+// OrgSource implements the Source interface for org-mode files. It reports
+// closed headings, clocked time, logged state changes and journal entries.
+type OrgSource struct {
+	path string
+}
+
+// NewOrgSource creates a source for an org file or a directory of org files.
+func NewOrgSource(path string) *OrgSource {
+	return &OrgSource{path: path}
+}
+
+func (o *OrgSource) Type() string {
+	return "org"
+}
+
+func (o *OrgSource) Location() string {
+	return o.path
+}
+
+func (o *OrgSource) Validate() error {
+	if _, err := os.Stat(o.path); err != nil {
+		return fmt.Errorf("path not accessible: %w", err)
+	}
+	return nil
+}
+
+func (o *OrgSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	files, err := o.orgFiles(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []sources.Entry
+	for _, path := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		items, err := Parse(f, filepath.Base(path))
+		_ = f.Close()
+		if err != nil {
+			continue
+		}
+
+		for _, item := range items {
+			if item.Time.Before(from) || item.Time.After(to) {
+				continue
+			}
+			entries = append(entries, itemToEntry(item, path))
+		}
+	}
+
+	return entries, nil
+}
+
+// orgFiles returns the org files to parse. Files last modified before from
+// cannot contain anything logged in range and are skipped.
+func (o *OrgSource) orgFiles(ctx context.Context, from time.Time) ([]string, error) {
+	info, err := os.Stat(o.path)
+	if err != nil {
+		return nil, fmt.Errorf("path not accessible: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{o.path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(o.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != o.path && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".org") && !journalFile.MatchString(d.Name()) {
+			return nil
+		}
+		if fi, err := d.Info(); err == nil && fi.ModTime().Before(from) {
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory: %w", err)
+	}
+	return files, nil
+}
+
+func itemToEntry(item Item, path string) sources.Entry {
+	meta := map[string]string{
+		"kind": item.Kind,
+		"file": filepath.Base(path),
+		"line": strconv.Itoa(item.Line),
+	}
+	if item.State != "" {
+		meta["state"] = item.State
+	}
+	if len(item.Tags) > 0 {
+		meta["tags"] = strings.Join(item.Tags, ",")
+	}
+	if len(item.Outline) > 0 {
+		meta["outline"] = strings.Join(item.Outline, " / ")
+	}
+
+	var content string
+	switch item.Kind {
+	case KindClosed:
+		content = fmt.Sprintf("%s %s", item.State, item.Title)
+	case KindClock:
+		minutes := int(item.End.Sub(item.Time).Round(time.Minute).Minutes())
+		meta["duration_minutes"] = strconv.Itoa(minutes)
+		meta["clock_end"] = item.End.Format(time.RFC3339)
+		content = fmt.Sprintf("%s (clocked %s)", item.Title, timetrack.FormatMinutes(minutes))
+	case KindState:
+		meta["from_state"] = item.From
+		content = fmt.Sprintf("%s: %s -> %s", item.Title, item.From, item.State)
+	default:
+		content = item.Title
+	}
+
+	return sources.Entry{
+		Timestamp: item.Time,
+		Source:    "org",
+		Location:  path,
+		Content:   content,
+		Metadata:  meta,
+	}
+}
+
+// heading is the parser's view of the current outline node.
+type heading struct {
+	level int
+	title string
+	state string
+	tags  []string
+	line  int
+	date  time.Time // set for datetree day headings
+}
+
+// Parse reads an org document and returns its dated items. name is the file
+// name; org-journal files (named YYYYMMDD) date their headings from it.
+func Parse(r io.Reader, name string) ([]Item, error) {
+	keywords := slices.Clone(defaultTodoKeywords)
+
+	var fileDate time.Time
+	if m := journalFile.FindStringSubmatch(name); m != nil {
+		fileDate, _ = time.ParseInLocation("20060102", m[1], time.Local)
+	}
+
+	var items []Item
+	var stack []heading
+	closedAt := make(map[int]time.Time) // heading line -> CLOSED time, for dedup
+
+	current := func() *heading {
+		if len(stack) == 0 {
+			return nil
+		}
+		return &stack[len(stack)-1]
+	}
+	outline := func() []string {
+		var titles []string
+		for _, h := range stack[:max(len(stack)-1, 0)] {
+			titles = append(titles, h.title)
+		}
+		return titles
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNum++
+
+		if m := todoDeclRegex.FindStringSubmatch(line); m != nil {
+			for _, kw := range strings.Fields(m[1]) {
+				kw = strings.SplitN(kw, "(", 2)[0]
+				if kw != "|" && !slices.Contains(keywords, kw) {
+					keywords = append(keywords, kw)
+				}
+			}
+			continue
+		}
+
+		if m := headingRegex.FindStringSubmatch(line); m != nil {
+			h := parseHeading(len(m[1]), m[2], keywords)
+			h.line = lineNum
+			for len(stack) > 0 && stack[len(stack)-1].level >= h.level {
+				stack = stack[:len(stack)-1]
+			}
+
+			if dm := datetreeRegex.FindStringSubmatch(h.title); dm != nil {
+				h.date, _ = time.ParseInLocation("2006-01-02", dm[1], time.Local)
+			}
+
+			// A heading below a datetree day (or level 2+ in an org-journal
+			// file) is a journal entry for that day.
+			day := fileDate
+			if fileDate.IsZero() || h.level < 2 {
+				day = time.Time{}
+			}
+			for _, parent := range stack {
+				if !parent.date.IsZero() {
+					day = parent.date
+				}
+			}
+			stack = append(stack, h)
+
+			if !day.IsZero() && h.date.IsZero() {
+				title := h.title
+				ts := day
+				if cm := clockPrefix.FindStringSubmatch(title); cm != nil {
+					hh, _ := strconv.Atoi(cm[1])
+					mm, _ := strconv.Atoi(cm[2])
+					ts = day.Add(time.Duration(hh)*time.Hour + time.Duration(mm)*time.Minute)
+					title = strings.TrimSpace(title[len(cm[0]):])
+				}
+				items = append(items, Item{
+					Kind: KindJournal, Title: title, State: h.state, Tags: h.tags,
+					Outline: outline(), Time: ts, Line: lineNum,
+				})
+			}
+			continue
+		}
+
+		h := current()
+		if h == nil {
+			continue
+		}
+
+		if m := closedRegex.FindStringSubmatch(line); m != nil {
+			if ts, ok := ParseTimestamp(m[1]); ok {
+				closedAt[h.line] = ts
+				items = append(items, Item{
+					Kind: KindClosed, Title: h.title, State: h.state, Tags: h.tags,
+					Outline: outline(), Time: ts, Line: h.line,
+				})
+			}
+			continue
+		}
+
+		if start, end, ok := ParseClock(line); ok {
+			items = append(items, Item{
+				Kind: KindClock, Title: h.title, State: h.state, Tags: h.tags,
+				Outline: outline(), Time: start, End: end, Line: lineNum,
+			})
+			continue
+		}
+
+		if m := stateRegex.FindStringSubmatch(line); m != nil {
+			ts, ok := ParseTimestamp(m[3])
+			if !ok {
+				continue
+			}
+			items = append(items, Item{
+				Kind: KindState, Title: h.title, State: m[1], From: m[2], Tags: h.tags,
+				Outline: outline(), Time: ts, Line: lineNum, heading: h.line,
+			})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// A state change logged at the CLOSED time repeats the closed item.
+	items = slices.DeleteFunc(items, func(it Item) bool {
+		ts, ok := closedAt[it.heading]
+		return it.Kind == KindState && ok && ts.Equal(it.Time)
+	})
+
+	return items, nil
+}
+
+// parseHeading splits heading text into TODO keyword, title and tags.
+func parseHeading(level int, text string, keywords []string) heading {
+	h := heading{level: level}
+
+	if m := tagsRegex.FindStringSubmatch(text); m != nil {
+		text = strings.TrimSpace(strings.TrimSuffix(text, m[0]))
+		h.tags = strings.FieldsFunc(m[1], func(r rune) bool { return r == ':' })
+	}
+
+	if first, rest, ok := strings.Cut(text, " "); ok && slices.Contains(keywords, first) {
+		h.state = first
+		text = rest
+	} else if slices.Contains(keywords, text) {
+		h.state = text
+		text = ""
+	}
+
+	h.title = strings.TrimSpace(priorityRegex.ReplaceAllString(strings.TrimSpace(text), ""))
+	return h
+}
+
+// ParseTimestamp parses an org timestamp such as "[2026-04-07 Tue 14:30]" or
+// "<2026-04-07 Tue>". Times are interpreted in the local time zone.
+func ParseTimestamp(s string) (time.Time, bool) {
+	m := timestampBody.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return time.Time{}, false
+	}
+
+	layout, value := "2006-01-02", m[1]
+	if m[2] != "" {
+		layout, value = "2006-01-02 15:04", m[1]+" "+m[2]
+		if m[3] != "" {
+			layout, value = "2006-01-02 15:04:05", value+":"+m[3]
+		}
+	}
+
+	t, err := time.ParseInLocation(layout, value, time.Local)
+	if err != nil {
+		// single-digit hours ("9:05") need a different layout
+		t, err = time.ParseInLocation(strings.Replace(layout, "15:", "3:", 1), value, time.Local)
+		if err != nil {
+			return time.Time{}, false
+		}
+	}
+	return t, true
+}
+
+// ParseClock parses a closed CLOCK line ("CLOCK: [start]--[end] =>  1:30").
+// Logseq logbooks use the same syntax. Running clocks without an end are
+// not reported.
+func ParseClock(line string) (start, end time.Time, ok bool) {
+	m := clockRegex.FindStringSubmatch(line)
+	if m == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	start, ok1 := ParseTimestamp(m[1])
+	end, ok2 := ParseTimestamp(m[2])
+	if !ok1 || !ok2 || end.Before(start) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
@@ -0,0 +1,184 @@
+package org
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d, hh, mm int) time.Time {
+	return time.Date(y, m, d, hh, mm, 0, 0, time.Local)
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Time
+		ok    bool
+	}{
+		{"[2026-04-07 Tue 14:30]", date(2026, 4, 7, 14, 30), true},
+		{"<2026-04-07 Tue>", date(2026, 4, 7, 0, 0), true},
+		{"[2026-04-07 Di. 9:05]", date(2026, 4, 7, 9, 5), true},
+		{"[2026-04-07]", date(2026, 4, 7, 0, 0), true},
+		{"not a timestamp", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ParseTimestamp(tt.input)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	start, end, ok := ParseClock("  CLOCK: [2026-04-07 Tue 09:00]--[2026-04-07 Tue 10:30] =>  1:30")
+	if !ok {
+		t.Fatal("expected clock line to parse")
+	}
+	if !start.Equal(date(2026, 4, 7, 9, 0)) || !end.Equal(date(2026, 4, 7, 10, 30)) {
+		t.Errorf("got %v--%v", start, end)
+	}
+
+	if _, _, ok := ParseClock("CLOCK: [2026-04-07 Tue 09:00]"); ok {
+		t.Error("running clock should not parse")
+	}
+}
+
+const sampleOrg = `#+TITLE: Work
+#+TODO: TODO REVIEW | DONE
+
+* Projects
+** DONE [#A] Ship pricing page                                  :acme:web:
+   CLOSED: [2026-04-07 Tue 16:00]
+   :LOGBOOK:
+   - State "DONE"       from "REVIEW"     [2026-04-07 Tue 16:00]
+   - State "REVIEW"     from "TODO"       [2026-04-06 Mon 11:00]
+   CLOCK: [2026-04-06 Mon 09:00]--[2026-04-06 Mon 10:45] =>  1:45
+   :END:
+** REVIEW Migrate billing
+* Journal
+** 2026
+*** 2026-04 April
+**** 2026-04-07 Tuesday
+***** 10:15 Standup with team
+***** Notes on the outage
+`
+
+func TestParse(t *testing.T) {
+	items, err := Parse(strings.NewReader(sampleOrg), "work.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byKind := make(map[string][]Item)
+	for _, it := range items {
+		byKind[it.Kind] = append(byKind[it.Kind], it)
+	}
+
+	closed := byKind[KindClosed]
+	if len(closed) != 1 {
+		t.Fatalf("expected 1 closed item, got %d", len(closed))
+	}
+	if closed[0].Title != "Ship pricing page" || closed[0].State != "DONE" {
+		t.Errorf("unexpected closed item: %+v", closed[0])
+	}
+	if strings.Join(closed[0].Tags, ",") != "acme,web" {
+		t.Errorf("tags = %v", closed[0].Tags)
+	}
+	if strings.Join(closed[0].Outline, "/") != "Projects" {
+		t.Errorf("outline = %v", closed[0].Outline)
+	}
+
+	// The DONE transition duplicates CLOSED; only REVIEW remains.
+	states := byKind[KindState]
+	if len(states) != 1 || states[0].State != "REVIEW" || states[0].From != "TODO" {
+		t.Errorf("unexpected state items: %+v", states)
+	}
+
+	clocks := byKind[KindClock]
+	if len(clocks) != 1 || clocks[0].End.Sub(clocks[0].Time) != 105*time.Minute {
+		t.Errorf("unexpected clock items: %+v", clocks)
+	}
+
+	journal := byKind[KindJournal]
+	if len(journal) != 2 {
+		t.Fatalf("expected 2 journal items, got %d: %+v", len(journal), journal)
+	}
+	if journal[0].Title != "Standup with team" || !journal[0].Time.Equal(date(2026, 4, 7, 10, 15)) {
+		t.Errorf("unexpected journal item: %+v", journal[0])
+	}
+	if !journal[1].Time.Equal(date(2026, 4, 7, 0, 0)) {
+		t.Errorf("expected day timestamp, got %v", journal[1].Time)
+	}
+}
+
+func TestParse_OrgJournalFile(t *testing.T) {
+	content := "* Tuesday, 04/07/26\n** 09:30 Planning\n** Wrote RFC\n"
+	items, err := Parse(strings.NewReader(content), "20260407")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d: %+v", len(items), items)
+	}
+	if items[0].Title != "Planning" || !items[0].Time.Equal(date(2026, 4, 7, 9, 30)) {
+		t.Errorf("unexpected item: %+v", items[0])
+	}
+}
+
+func TestOrgSource_GetEntries(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "work.org"), []byte(sampleOrg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewOrgSource(dir)
+	if err := source.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	from := date(2026, 4, 7, 0, 0)
+	to := date(2026, 4, 7, 23, 59)
+	entries, err := source.GetEntries(t.Context(), from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// closed item + two journal entries; the clock and REVIEW change are on 04-06
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d: %+v", len(entries), entries)
+	}
+	for _, e := range entries {
+		if e.Source != "org" {
+			t.Errorf("unexpected source %q", e.Source)
+		}
+	}
+	if entries[0].Content != "DONE Ship pricing page" {
+		t.Errorf("content = %q", entries[0].Content)
+	}
+
+	entries, err = source.GetEntries(t.Context(), date(2026, 4, 6, 0, 0), date(2026, 4, 6, 23, 59))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clock bool
+	for _, e := range entries {
+		if e.Metadata["kind"] == KindClock {
+			clock = true
+			if e.Metadata["duration_minutes"] != "105" {
+				t.Errorf("duration_minutes = %q", e.Metadata["duration_minutes"])
+			}
+		}
+	}
+	if !clock {
+		t.Error("expected a clock entry on 2026-04-06")
+	}
+}
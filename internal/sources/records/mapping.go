@@ -0,0 +1,133 @@
+package records
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Config metadata keys understood by ParseMapping. Extra metadata fields
+// are stored as FieldPrefix + name.
+const (
+	KeyTimestamp  = "timestamp"
+	KeyTimeFormat = "time_format"
+	KeyContent    = "content"
+	KeyLocation   = "location"
+	KeyDelimiter  = "delimiter"
+	FieldPrefix   = "field."
+)
+
+// Named time formats accepted besides Go reference layouts.
+const (
+	FormatRFC3339 = "rfc3339"
+	FormatUnix    = "unix"
+	FormatUnixMs  = "unix_ms"
+)
+
+// placeholder matches {field} references in a content template.
+var placeholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Mapping describes how the fields of a record become an entry. Field names
+// are CSV column headers or dot-separated JSON paths ("deploy.env",
+// "tags.0").
+type Mapping struct {
+	Timestamp  string
+	TimeFormat string
+	// Content is a field name, or a template such as "{service} {version}"
+	// when it contains placeholders.
+	Content  string
+	Location string
+	// Fields maps entry metadata keys to record fields.
+	Fields    map[string]string
+	Delimiter rune
+}
+
+// ParseMapping reads a Mapping from source config metadata. The timestamp
+// field defaults to "timestamp" and its format to RFC 3339; content is
+// required.
+func ParseMapping(meta map[string]string) (Mapping, error) {
+	m := Mapping{
+		Timestamp:  cmp.Or(meta[KeyTimestamp], "timestamp"),
+		TimeFormat: cmp.Or(meta[KeyTimeFormat], FormatRFC3339),
+		Content:    meta[KeyContent],
+		Location:   meta[KeyLocation],
+		Fields:     make(map[string]string),
+		Delimiter:  ',',
+	}
+	if m.Content == "" {
+		return Mapping{}, fmt.Errorf("no content field mapped (set %q)", KeyContent)
+	}
+
+	for key, field := range meta {
+		if name, ok := strings.CutPrefix(key, FieldPrefix); ok && name != "" && field != "" {
+			m.Fields[name] = field
+		}
+	}
+
+	if d := meta[KeyDelimiter]; d != "" {
+		if d == `\t` {
+			d = "\t"
+		}
+		r, size := utf8.DecodeRuneInString(d)
+		if size != len(d) || r == '"' || r == '\n' {
+			return Mapping{}, fmt.Errorf("invalid delimiter %q: must be a single character", d)
+		}
+		m.Delimiter = r
+	}
+	return m, nil
+}
+
+// lookup returns the value of a field in a record and whether it exists.
+type lookup func(field string) (string, bool)
+
+// content renders the entry content for a record.
+func (m Mapping) content(get lookup) string {
+	if !placeholder.MatchString(m.Content) {
+		v, _ := get(m.Content)
+		return v
+	}
+	return placeholder.ReplaceAllStringFunc(m.Content, func(ref string) string {
+		v, _ := get(ref[1 : len(ref)-1])
+		return v
+	})
+}
+
+// metadata collects the mapped extra fields that are present in a record.
+func (m Mapping) metadata(get lookup) map[string]string {
+	meta := make(map[string]string, len(m.Fields))
+	for name, field := range m.Fields {
+		if v, ok := get(field); ok && v != "" {
+			meta[name] = v
+		}
+	}
+	return meta
+}
+
+// ParseTime parses a timestamp value according to format: "rfc3339",
+// "unix" (seconds, fractions allowed), "unix_ms", or a Go reference layout
+// interpreted in local time.
+func ParseTime(value, format string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	switch format {
+	case "", FormatRFC3339:
+		return time.Parse(time.RFC3339Nano, value)
+	case FormatUnix:
+		sec, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid unix timestamp %q", value)
+		}
+		return time.UnixMilli(int64(sec * 1000)), nil
+	case FormatUnixMs:
+		ms, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid unix millisecond timestamp %q", value)
+		}
+		return time.UnixMilli(ms), nil
+	default:
+		return time.ParseInLocation(format, value, time.Local)
+	}
+}
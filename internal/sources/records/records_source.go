@@ -0,0 +1,290 @@
+package records
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// Source formats, used as source types.
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+)
+
+// maxLineSize bounds a single JSONL record.
+const maxLineSize = 10 * 1024 * 1024
+
+// extensions lists the file extensions read from a directory per format.
+var extensions = map[string][]string{
+	FormatJSONL: {".jsonl", ".ndjson"},
+	FormatCSV:   {".csv"},
+}
+
+// RecordsSource implements the Source interface for generic record files:
+// JSON Lines or CSV dumps from deploy logs, paging exports, CI histories
+// and similar tools. A Mapping from the source config decides which fields
+// become timestamp, content, location and metadata.
+type RecordsSource struct {
+	format  string
+	path    string
+	mapping Mapping
+	err     error // mapping error, reported by Validate and GetEntries
+}
+
+// NewRecordsSource creates a source for a file or a directory of files in
+// format (FormatJSONL or FormatCSV), mapped by the given config metadata.
+func NewRecordsSource(format, path string, meta map[string]string) *RecordsSource {
+	m, err := ParseMapping(meta)
+	return &RecordsSource{format: format, path: path, mapping: m, err: err}
+}
+
+func (s *RecordsSource) Type() string {
+	return s.format
+}
+
+func (s *RecordsSource) Location() string {
+	return s.path
+}
+
+func (s *RecordsSource) Validate() error {
+	if s.err != nil {
+		return s.err
+	}
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no %s files found in %s", strings.Join(extensions[s.format], " or "), s.path)
+	}
+	return nil
+}
+
+func (s *RecordsSource) files() ([]string, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("path not accessible: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{s.path}, nil
+	}
+
+	var files []string
+	for _, ext := range extensions[s.format] {
+		matches, err := filepath.Glob(filepath.Join(s.path, "*"+ext))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s files: %w", ext, err)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+func (s *RecordsSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []sources.Entry
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+		}
+
+		collect := func(line int, get lookup) {
+			if e, ok := s.entry(path, line, get); ok && !e.Timestamp.Before(from) && !e.Timestamp.After(to) {
+				entries = append(entries, e)
+			}
+		}
+		if s.format == FormatCSV {
+			err = readCSV(f, s.mapping.Delimiter, collect)
+		} else {
+			err = readJSONL(f, collect)
+		}
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+		}
+	}
+	return entries, nil
+}
+
+// entry maps one record. Records without a parsable timestamp or with
+// empty content are skipped.
+func (s *RecordsSource) entry(path string, line int, get lookup) (sources.Entry, bool) {
+	raw, ok := get(s.mapping.Timestamp)
+	if !ok {
+		return sources.Entry{}, false
+	}
+	ts, err := ParseTime(raw, s.mapping.TimeFormat)
+	if err != nil {
+		return sources.Entry{}, false
+	}
+	content := strings.TrimSpace(s.mapping.content(get))
+	if content == "" {
+		return sources.Entry{}, false
+	}
+
+	location := path
+	if s.mapping.Location != "" {
+		if v, ok := get(s.mapping.Location); ok && v != "" {
+			location = v
+		}
+	}
+
+	metadata := map[string]string{
+		"file": path,
+		"line": strconv.Itoa(line),
+	}
+	maps.Copy(metadata, s.mapping.metadata(get))
+
+	return sources.Entry{
+		Timestamp: ts,
+		Source:    s.format,
+		Location:  location,
+		Content:   content,
+		Metadata:  metadata,
+	}, true
+}
+
+// readJSONL calls fn for every JSON object line. Lines that are not valid
+// JSON objects are skipped, since log dumps often mix in plain text.
+func readJSONL(r io.Reader, fn func(line int, get lookup)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 || data[0] != '{' {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var record map[string]any
+		if err := dec.Decode(&record); err != nil {
+			continue
+		}
+		fn(line, func(field string) (string, bool) {
+			v, ok := jsonField(record, field)
+			if !ok {
+				return "", false
+			}
+			return jsonString(v), true
+		})
+	}
+	return scanner.Err()
+}
+
+// jsonField resolves a dot-separated path. Keys that themselves contain
+// dots are matched before the path is split.
+func jsonField(v any, path string) (any, bool) {
+	if path == "" {
+		return v, v != nil
+	}
+	switch node := v.(type) {
+	case map[string]any:
+		if val, ok := node[path]; ok {
+			return val, val != nil
+		}
+		head, rest, found := strings.Cut(path, ".")
+		if !found {
+			return nil, false
+		}
+		return jsonField(node[head], rest)
+	case []any:
+		head, rest, _ := strings.Cut(path, ".")
+		i, err := strconv.Atoi(head)
+		if err != nil || i < 0 || i >= len(node) {
+			return nil, false
+		}
+		return jsonField(node[i], rest)
+	}
+	return nil, false
+}
+
+// jsonString renders a JSON value as text. Objects and arrays are kept as
+// compact JSON.
+func jsonString(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+}
+
+// readCSV calls fn for every row after the header. Columns are looked up
+// by header name, falling back to a case-insensitive match.
+func readCSV(r io.Reader, delimiter rune, fn func(line int, get lookup)) error {
+	br := bufio.NewReader(r)
+	if bom, _ := br.Peek(3); string(bom) == "\ufeff" {
+		_, _ = br.Discard(3)
+	}
+
+	reader := csv.NewReader(br)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+	index := func(field string) int {
+		for i, h := range header {
+			if strings.TrimSpace(h) == field {
+				return i
+			}
+		}
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), field) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := reader.FieldPos(0)
+		fn(line, func(field string) (string, bool) {
+			i := index(field)
+			if i < 0 || i >= len(record) {
+				return "", false
+			}
+			return strings.TrimSpace(record[i]), true
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package records
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRecordsSource_JSONL(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "deploys.jsonl", `{"ts": "2026-04-07T09:15:00Z", "service": "billing", "deploy": {"version": "1.4.2", "env.name": "prod"}, "host": "ci-3"}
+not json, a stray log line
+{"ts": "2026-04-07T14:00:00Z", "service": "auth", "deploy": {"version": "2.0.0"}}
+{"ts": "yesterday", "service": "broken"}
+{"ts": "2026-04-06T23:59:00Z", "service": "old", "deploy": {"version": "0.1"}}
+`)
+
+	source := NewRecordsSource(FormatJSONL, dir, map[string]string{
+		KeyTimestamp:            "ts",
+		KeyContent:              "deployed {service} {deploy.version}",
+		KeyLocation:             "host",
+		FieldPrefix + "env":     "deploy.env.name",
+		FieldPrefix + "service": "service",
+	})
+	if err := source.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)
+	entries, err := source.GetEntries(t.Context(), day, day.Add(24*time.Hour-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+
+	first := entries[0]
+	if first.Content != "deployed billing 1.4.2" || first.Location != "ci-3" || first.Source != "jsonl" {
+		t.Errorf("unexpected first entry: %+v", first)
+	}
+	if first.Metadata["env"] != "prod" || first.Metadata["service"] != "billing" || first.Metadata["line"] != "1" {
+		t.Errorf("unexpected metadata: %+v", first.Metadata)
+	}
+	if entries[1].Location != path {
+		t.Errorf("location without mapped field = %q, want file path", entries[1].Location)
+	}
+	if _, ok := entries[1].Metadata["env"]; ok {
+		t.Error("missing field should not be set in metadata")
+	}
+}
+
+func TestRecordsSource_CSV(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "pages.csv", "\ufeffCreated At;Summary;Urgency\n"+
+		"1775553300;Disk full on db-1;high\n"+
+		"1775556000;\"Latency; p99 > 2s\";low\n")
+
+	source := NewRecordsSource(FormatCSV, path, map[string]string{
+		KeyTimestamp:            "created at",
+		KeyTimeFormat:           FormatUnix,
+		KeyContent:              "Summary",
+		KeyDelimiter:            ";",
+		FieldPrefix + "urgency": "Urgency",
+	})
+
+	entries, err := source.GetEntries(t.Context(), time.Unix(1775550000, 0), time.Unix(1775560000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+	if !entries[0].Timestamp.Equal(time.Unix(1775553300, 0)) || entries[0].Metadata["urgency"] != "high" {
+		t.Errorf("unexpected entry: %+v", entries[0])
+	}
+	if entries[1].Content != "Latency; p99 > 2s" || entries[1].Metadata["line"] != "3" {
+		t.Errorf("unexpected entry: %+v", entries[1])
+	}
+}
+
+func TestRecordsSource_InvalidMapping(t *testing.T) {
+	source := NewRecordsSource(FormatJSONL, t.TempDir(), map[string]string{KeyTimestamp: "ts"})
+	if err := source.Validate(); err == nil {
+		t.Error("expected error without a content mapping")
+	}
+	if _, err := source.GetEntries(t.Context(), time.Time{}, time.Now()); err == nil {
+		t.Error("expected GetEntries to report the mapping error")
+	}
+}
+
+func TestParseMapping_Delimiter(t *testing.T) {
+	tests := []struct {
+		delimiter string
+		want      rune
+		wantErr   bool
+	}{
+		{"", ',', false},
+		{";", ';', false},
+		{`\t`, '\t', false},
+		{"||", 0, true},
+		{`"`, 0, true},
+	}
+	for _, tt := range tests {
+		m, err := ParseMapping(map[string]string{KeyContent: "msg", KeyDelimiter: tt.delimiter})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("delimiter %q: err = %v, wantErr %v", tt.delimiter, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && m.Delimiter != tt.want {
+			t.Errorf("delimiter %q: got %q, want %q", tt.delimiter, m.Delimiter, tt.want)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2026, 4, 7, 9, 15, 30, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		format string
+	}{
+		{"2026-04-07T09:15:30Z", FormatRFC3339},
+		{"2026-04-07T11:15:30+02:00", ""},
+		{"1775553330", FormatUnix},
+		{"1775553330.0", FormatUnix},
+		{"1775553330000", FormatUnixMs},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.value, tt.format)
+		if err != nil {
+			t.Errorf("ParseTime(%q, %q): %v", tt.value, tt.format, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("ParseTime(%q, %q) = %v, want %v", tt.value, tt.format, got, want)
+		}
+	}
+
+	local, err := ParseTime("07/04/2026 09:15", "02/01/2006 15:04")
+	if err != nil || local.Location() != time.Local || local.Day() != 7 {
+		t.Errorf("layout format: got %v, %v", local, err)
+	}
+	if _, err := ParseTime("soon", FormatUnix); err == nil {
+		t.Error("expected error for non-numeric unix timestamp")
+	}
+}
@@ -1,6 +1,9 @@
 package sources
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Entry represents a single activity entry from any source.
 type Entry struct {
@@ -17,7 +20,7 @@ type Source interface {
 	Type() string
 
 	// GetEntries retrieves all entries within the given time range.
-	GetEntries(from, to time.Time) ([]Entry, error)
+	GetEntries(ctx context.Context, from, to time.Time) ([]Entry, error)
 
 	// Validate checks if the source configuration is valid and accessible.
 	Validate() error
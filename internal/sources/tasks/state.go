@@ -0,0 +1,163 @@
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// State remembers the last observed status of each task. Plain GFM checkboxes
+// carry no done date, so the only way to tell when one was ticked is to notice
+// that it was open on a previous run and is done now.
+type State struct {
+	path  string
+	Tasks map[string]taskRecord `json:"tasks"`
+
+	// live holds the keys of tasks seen in this run; Save drops the rest,
+	// which belong to deleted files or removed tasks.
+	live map[string]bool
+}
+
+type taskRecord struct {
+	Done   bool      `json:"done"`
+	DoneAt time.Time `json:"done_at,omitzero"`
+}
+
+// LoadState reads the task state file at path. A missing file yields an
+// empty state that is created on Save.
+func LoadState(path string) (*State, error) {
+	s := &State{path: path, Tasks: make(map[string]taskRecord), live: make(map[string]bool)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read task state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse task state: %w", err)
+	}
+	if s.Tasks == nil {
+		s.Tasks = make(map[string]taskRecord)
+	}
+	return s, nil
+}
+
+// Save writes the state back to disk, keeping only the tasks passed to
+// Relevant or Keep since LoadState. A nil state is a no-op.
+func (s *State) Save() error {
+	if s == nil {
+		return nil
+	}
+	maps.DeleteFunc(s.Tasks, func(key string, _ taskRecord) bool { return !s.live[key] })
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create task state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal task state: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write task state: %w", err)
+	}
+	return nil
+}
+
+// observe records the task's current status and returns the time it was
+// detected as completed. The first sighting of an already-done task only
+// establishes a baseline and returns the zero time.
+func (s *State) observe(key string, done bool, modTime time.Time) time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+
+	s.live[key] = true
+	prev, seen := s.Tasks[key]
+	switch {
+	case !done:
+		s.Tasks[key] = taskRecord{}
+		return time.Time{}
+	case prev.Done:
+		return prev.DoneAt
+	case seen:
+		// Was open last time, done now: the file's mtime is our best guess.
+		s.Tasks[key] = taskRecord{Done: true, DoneAt: modTime}
+		return modTime
+	default:
+		s.Tasks[key] = taskRecord{Done: true}
+		return time.Time{}
+	}
+}
+
+// Keep marks the tasks of a file as still present without checking them,
+// for files that are not passed to Relevant because they have not changed.
+func (s *State) Keep(file string, list []Task) {
+	if s == nil {
+		return
+	}
+	for _, key := range stateKeys(file, list) {
+		s.live[key] = true
+	}
+}
+
+// Relevant returns the tasks from one file that belong in [from, to]:
+// tasks completed in range, either by explicit done date or by a detected
+// state change, and open tasks due in range. file identifies the document in
+// the state and should be stable across runs (e.g. a path relative to the
+// source root). state may be nil, in which case only done dates are used.
+func Relevant(file string, list []Task, modTime, from, to time.Time, state *State) []Task {
+	var result []Task
+	keys := stateKeys(file, list)
+	for i, t := range list {
+		key := keys[i]
+		switch {
+		case t.Done() && !t.DoneDate.IsZero():
+			state.observe(key, true, modTime)
+			if InRange(t.DoneDate, from, to) {
+				t.CompletedAt = t.DoneDate
+				t.CompletedBy = "done_date"
+				result = append(result, t)
+			}
+		case t.Done():
+			at := state.observe(key, true, modTime)
+			if !at.IsZero() && !at.Before(from) && !at.After(to) {
+				t.CompletedAt = at
+				t.CompletedBy = "state_change"
+				result = append(result, t)
+			}
+		default:
+			state.observe(key, false, modTime)
+			if t.DueIn(from, to) {
+				result = append(result, t)
+			}
+		}
+	}
+	return result
+}
+
+// stateKeys returns the state key of each task in a file. A key identifies
+// the nth task (from 0) with a given text; counting occurrences keeps
+// identical tasks apart without depending on line numbers, which shift
+// whenever lines are added above.
+func stateKeys(file string, list []Task) []string {
+	keys := make([]string, len(list))
+	occurrences := make(map[string]int)
+	for i, t := range list {
+		key := file + "\x00" + t.Text
+		if n := occurrences[t.Text]; n > 0 {
+			key += "\x00" + strconv.Itoa(n)
+		}
+		occurrences[t.Text]++
+		keys[i] = key
+	}
+	return keys
+}
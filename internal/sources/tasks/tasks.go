@@ -0,0 +1,227 @@
+package tasks
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status values for a parsed task.
+const (
+	StatusOpen       = "open"
+	StatusDone       = "done"
+	StatusInProgress = "in_progress"
+	StatusCancelled  = "cancelled"
+)
+
+// dateLayout is the date format used by Obsidian Tasks and Dataview fields.
+const dateLayout = "2006-01-02"
+
+// checkboxRegex matches GFM task list items: "- [ ] text", "* [x] text", "1. [X] text".
+var checkboxRegex = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[(.)\]\s+(.*)$`)
+
+// headingRegex matches ATX headings: one to six "#" followed by a space.
+var headingRegex = regexp.MustCompile(`^#{1,6}(?:\s|$)`)
+
+// fenceRegex matches the opening or closing line of a fenced code block.
+var fenceRegex = regexp.MustCompile("^ {0,3}(```|~~~)")
+
+// emojiDateRegex matches Obsidian Tasks date markers such as "✅ 2026-04-07".
+var emojiDateRegex = regexp.MustCompile(`(✅|📅|⏳|🛫|➕|❌)️?\s*(\d{4}-\d{2}-\d{2})`)
+
+// fieldDateRegex matches Dataview inline fields such as "[completion:: 2026-04-07]".
+var fieldDateRegex = regexp.MustCompile(`\[(completion|due|scheduled|start|created|cancelled)::\s*(\d{4}-\d{2}-\d{2})\s*\]`)
+
+// Task is a single checkbox item parsed from a markdown line.
+type Task struct {
+	Text      string    // task description with date markers removed
+	Status    string    // StatusOpen, StatusDone, StatusInProgress or StatusCancelled
+	DoneDate  time.Time // from "✅ date" or "[completion:: date]"; zero if absent
+	DueDate   time.Time // from "📅 date" or "[due:: date]"; zero if absent
+	Scheduled time.Time // from "⏳ date" or "[scheduled:: date]"; zero if absent
+	Line      int       // 1-based line number within the file
+	Heading   string    // closest heading above the task, if any
+
+	// Set by Relevant for completed tasks.
+	CompletedAt time.Time // done date or detected completion time
+	CompletedBy string    // "done_date" or "state_change"
+}
+
+// Done reports whether the task is checked off.
+func (t Task) Done() bool {
+	return t.Status == StatusDone
+}
+
+// DueIn reports whether t is an open task due in [from, to].
+func (t Task) DueIn(from, to time.Time) bool {
+	return !t.Done() && t.Status != StatusCancelled && InRange(t.DueDate, from, to)
+}
+
+// Due returns the open tasks due in [from, to]. It is all Relevant can find
+// in a file untouched since before the range: no task there was ticked in it.
+func Due(list []Task, from, to time.Time) []Task {
+	var result []Task
+	for _, t := range list {
+		if t.DueIn(from, to) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// Timestamp returns the time an entry for this task should carry: the
+// completion time for done tasks, the due date for open ones.
+func (t Task) Timestamp() time.Time {
+	if !t.CompletedAt.IsZero() {
+		return t.CompletedAt
+	}
+	return t.DueDate
+}
+
+// Content returns a one-line description such as "done: ship pricing page".
+func (t Task) Content() string {
+	if t.Done() {
+		return "done: " + t.Text
+	}
+	if !t.DueDate.IsZero() {
+		return fmt.Sprintf("open: %s (due %s)", t.Text, t.DueDate.Format(dateLayout))
+	}
+	return "open: " + t.Text
+}
+
+// Metadata returns the task fields as entry metadata. Sources add their own
+// location keys (file, path) on top.
+func (t Task) Metadata() map[string]string {
+	meta := map[string]string{
+		"kind":        "task",
+		"task_status": t.Status,
+		"line":        strconv.Itoa(t.Line),
+	}
+	if t.Heading != "" {
+		meta["heading"] = t.Heading
+	}
+	if !t.DoneDate.IsZero() {
+		meta["done_date"] = t.DoneDate.Format(dateLayout)
+	}
+	if !t.DueDate.IsZero() {
+		meta["due_date"] = t.DueDate.Format(dateLayout)
+	}
+	if !t.Scheduled.IsZero() {
+		meta["scheduled_date"] = t.Scheduled.Format(dateLayout)
+	}
+	if t.CompletedBy != "" {
+		meta["completed_by"] = t.CompletedBy
+	}
+	return meta
+}
+
+// Parse extracts a task from a single markdown line.
+// Returns false if the line is not a task list item.
+func Parse(line string) (Task, bool) {
+	m := checkboxRegex.FindStringSubmatch(line)
+	if m == nil {
+		return Task{}, false
+	}
+
+	task := Task{Status: statusFromMarker(m[1])}
+	text := m[2]
+
+	for _, dm := range emojiDateRegex.FindAllStringSubmatch(text, -1) {
+		setDate(&task, emojiField(dm[1]), dm[2])
+	}
+	for _, fm := range fieldDateRegex.FindAllStringSubmatch(text, -1) {
+		setDate(&task, fm[1], fm[2])
+	}
+
+	text = emojiDateRegex.ReplaceAllString(text, "")
+	text = fieldDateRegex.ReplaceAllString(text, "")
+	task.Text = strings.Join(strings.Fields(text), " ")
+
+	return task, true
+}
+
+// ParseLines parses all tasks in a markdown document, tracking the closest
+// heading above each task. Lines in fenced code blocks are skipped, so a
+// shell comment or an example checkbox there is neither heading nor task.
+func ParseLines(lines []string) []Task {
+	var result []Task
+	var heading, fence string
+	for i, line := range lines {
+		if m := fenceRegex.FindStringSubmatch(line); m != nil {
+			switch fence {
+			case "":
+				fence = m[1]
+			case m[1]:
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+		if headingRegex.MatchString(line) {
+			heading = line
+			continue
+		}
+		if task, ok := Parse(line); ok {
+			task.Line = i + 1
+			task.Heading = heading
+			result = append(result, task)
+		}
+	}
+	return result
+}
+
+// InRange reports whether the calendar day of d overlaps [from, to].
+// Task dates carry no time of day, so a done date of today matches a range
+// that starts at noon.
+func InRange(d, from, to time.Time) bool {
+	if d.IsZero() {
+		return false
+	}
+	endOfDay := d.AddDate(0, 0, 1)
+	return endOfDay.After(from) && !d.After(to)
+}
+
+func statusFromMarker(marker string) string {
+	switch marker {
+	case "x", "X":
+		return StatusDone
+	case "/":
+		return StatusInProgress
+	case "-":
+		return StatusCancelled
+	default:
+		return StatusOpen
+	}
+}
+
+func emojiField(emoji string) string {
+	switch emoji {
+	case "✅":
+		return "completion"
+	case "📅":
+		return "due"
+	case "⏳":
+		return "scheduled"
+	default:
+		return ""
+	}
+}
+
+func setDate(task *Task, field, value string) {
+	d, err := time.ParseInLocation(dateLayout, value, time.Local)
+	if err != nil {
+		return
+	}
+	switch field {
+	case "completion":
+		task.DoneDate = d
+	case "due":
+		task.DueDate = d
+	case "scheduled":
+		task.Scheduled = d
+	}
+}
@@ -0,0 +1,270 @@
+package tasks
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	d, _ := time.ParseInLocation(dateLayout, s, time.Local)
+	return d
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		ok       bool
+		text     string
+		status   string
+		doneDate string
+		dueDate  string
+	}{
+		{"not a task", "- plain bullet", false, "", "", "", ""},
+		{"heading", "## Tasks", false, "", "", "", ""},
+		{"open gfm", "- [ ] write docs", true, "write docs", StatusOpen, "", ""},
+		{"done gfm", "* [x] write docs", true, "write docs", StatusDone, "", ""},
+		{"done upper", "1. [X] numbered", true, "numbered", StatusDone, "", ""},
+		{"in progress", "- [/] halfway", true, "halfway", StatusInProgress, "", ""},
+		{"cancelled", "- [-] dropped", true, "dropped", StatusCancelled, "", ""},
+		{"tasks plugin done", "- [x] ship pricing ✅ 2026-04-07", true, "ship pricing", StatusDone, "2026-04-07", ""},
+		{"tasks plugin due", "  - [ ] review PR 📅 2026-04-10", true, "review PR", StatusOpen, "", "2026-04-10"},
+		{"both dates", "- [x] migrate db 📅 2026-04-06 ✅ 2026-04-07", true, "migrate db", StatusDone, "2026-04-07", "2026-04-06"},
+		{"dataview fields", "- [x] call Acme [completion:: 2026-04-07] [due:: 2026-04-08]", true, "call Acme", StatusDone, "2026-04-07", "2026-04-08"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, ok := Parse(tt.line)
+			if ok != tt.ok {
+				t.Fatalf("Parse(%q) ok = %v, want %v", tt.line, ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if task.Text != tt.text {
+				t.Errorf("text = %q, want %q", task.Text, tt.text)
+			}
+			if task.Status != tt.status {
+				t.Errorf("status = %q, want %q", task.Status, tt.status)
+			}
+			if got := formatDate(task.DoneDate); got != tt.doneDate {
+				t.Errorf("done date = %q, want %q", got, tt.doneDate)
+			}
+			if got := formatDate(task.DueDate); got != tt.dueDate {
+				t.Errorf("due date = %q, want %q", got, tt.dueDate)
+			}
+		})
+	}
+}
+
+func formatDate(d time.Time) string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(dateLayout)
+}
+
+func TestParseLines_TracksHeading(t *testing.T) {
+	lines := []string{
+		"# Project",
+		"- [ ] first",
+		"## Done",
+		"some text",
+		"- [x] second",
+	}
+
+	got := ParseLines(lines)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(got))
+	}
+	if got[0].Heading != "# Project" || got[0].Line != 2 {
+		t.Errorf("first task: heading=%q line=%d", got[0].Heading, got[0].Line)
+	}
+	if got[1].Heading != "## Done" || got[1].Line != 5 {
+		t.Errorf("second task: heading=%q line=%d", got[1].Heading, got[1].Line)
+	}
+}
+
+func TestParseLines_SkipsCodeAndHashtags(t *testing.T) {
+	lines := []string{
+		"## Sprint",
+		"#review pending",
+		"```sh",
+		"# not a heading",
+		"- [ ] not a task",
+		"```",
+		"- [ ] real task",
+	}
+
+	got := ParseLines(lines)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 task, got %+v", got)
+	}
+	if got[0].Text != "real task" || got[0].Heading != "## Sprint" {
+		t.Errorf("task: text=%q heading=%q", got[0].Text, got[0].Heading)
+	}
+}
+
+func TestInRange(t *testing.T) {
+	from := date("2026-04-07").Add(12 * time.Hour)
+	to := date("2026-04-08").Add(12 * time.Hour)
+
+	if !InRange(date("2026-04-07"), from, to) {
+		t.Error("date on the first day of a range starting mid-day should match")
+	}
+	if !InRange(date("2026-04-08"), from, to) {
+		t.Error("date on the last day should match")
+	}
+	if InRange(date("2026-04-06"), from, to) {
+		t.Error("date before range should not match")
+	}
+	if InRange(date("2026-04-09"), from, to) {
+		t.Error("date after range should not match")
+	}
+	if InRange(time.Time{}, from, to) {
+		t.Error("zero date should not match")
+	}
+}
+
+func TestRelevant_DoneDatesAndDue(t *testing.T) {
+	from := date("2026-04-06")
+	to := date("2026-04-12").Add(-time.Second)
+	modTime := date("2026-04-08")
+
+	list := ParseLines([]string{
+		"- [x] in range ✅ 2026-04-07",
+		"- [x] too old ✅ 2026-03-01",
+		"- [ ] due this week 📅 2026-04-10",
+		"- [ ] due later 📅 2026-05-01",
+		"- [-] cancelled 📅 2026-04-10",
+		"- [x] no date",
+	})
+
+	got := Relevant("note.md", list, modTime, from, to, nil)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 relevant tasks, got %d: %+v", len(got), got)
+	}
+	if got[0].Text != "in range" || got[0].CompletedBy != "done_date" {
+		t.Errorf("unexpected first task: %+v", got[0])
+	}
+	if !got[0].Timestamp().Equal(date("2026-04-07")) {
+		t.Errorf("done task timestamp = %v", got[0].Timestamp())
+	}
+	if got[1].Text != "due this week" || got[1].Content() != "open: due this week (due 2026-04-10)" {
+		t.Errorf("unexpected second task: %+v", got[1])
+	}
+}
+
+func TestRelevant_DetectsStateChange(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state", "tasks.json")
+	from := date("2026-04-06")
+	to := date("2026-04-12")
+
+	// First run: task is open, nothing to report.
+	state, err := LoadState(statePath)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	open := ParseLines([]string{"- [ ] write report", "- [x] already done"})
+	if got := Relevant("a.md", open, date("2026-04-06"), from, to, state); len(got) != 0 {
+		t.Fatalf("expected no tasks on baseline run, got %+v", got)
+	}
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Second run: checkbox was ticked.
+	state, err = LoadState(statePath)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	ticked := date("2026-04-08").Add(15 * time.Hour)
+	done := ParseLines([]string{"- [x] write report", "- [x] already done"})
+	got := Relevant("a.md", done, ticked, from, to, state)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 completed task, got %+v", got)
+	}
+	if got[0].Text != "write report" || got[0].CompletedBy != "state_change" || !got[0].CompletedAt.Equal(ticked) {
+		t.Errorf("unexpected task: %+v", got[0])
+	}
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Third run: completion time is remembered even though mtime moved on.
+	state, err = LoadState(statePath)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	got = Relevant("a.md", done, date("2026-04-11"), from, to, state)
+	if len(got) != 1 || !got[0].CompletedAt.Equal(ticked) {
+		t.Errorf("expected remembered completion at %v, got %+v", ticked, got)
+	}
+}
+
+func TestRelevant_DuplicateTaskText(t *testing.T) {
+	state, err := LoadState(filepath.Join(t.TempDir(), "tasks.json"))
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	from := date("2026-04-06")
+	to := date("2026-04-12")
+
+	// Two tasks with the same text, one of them already done.
+	Relevant("a.md", ParseLines([]string{"- [x] review PR", "- [ ] review PR"}), date("2026-04-06"), from, to, state)
+
+	// Ticking the second must not reset or date the first.
+	ticked := date("2026-04-09")
+	got := Relevant("a.md", ParseLines([]string{"- [x] review PR", "- [x] review PR"}), ticked, from, to, state)
+	if len(got) != 1 || got[0].Line != 2 || !got[0].CompletedAt.Equal(ticked) {
+		t.Fatalf("expected only the second task completed at %v, got %+v", ticked, got)
+	}
+
+	got = Relevant("a.md", ParseLines([]string{"- [x] review PR", "- [x] review PR"}), date("2026-04-11"), from, to, state)
+	if len(got) != 1 || got[0].Line != 2 || !got[0].CompletedAt.Equal(ticked) {
+		t.Errorf("expected the remembered completion of the second task, got %+v", got)
+	}
+}
+
+func TestState_SavePrunesMissingTasks(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "tasks.json")
+	from := date("2026-04-06")
+	to := date("2026-04-12")
+
+	state, err := LoadState(statePath)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	Relevant("a.md", ParseLines([]string{"- [ ] kept", "- [ ] removed"}), from, from, to, state)
+	Relevant("b.md", ParseLines([]string{"- [ ] untouched"}), from, from, to, state)
+	Relevant("gone.md", ParseLines([]string{"- [ ] deleted file"}), from, from, to, state)
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Next run: a task was removed from a.md, b.md did not change and
+	// gone.md was deleted.
+	state, err = LoadState(statePath)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	Relevant("a.md", ParseLines([]string{"- [ ] kept"}), to, from, to, state)
+	state.Keep("b.md", ParseLines([]string{"- [ ] untouched"}))
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	state, err = LoadState(statePath)
+	if err != nil {
+		t.Fatalf("LoadState: %v", err)
+	}
+	got := slices.Sorted(maps.Keys(state.Tasks))
+	want := []string{"a.md\x00kept", "b.md\x00untouched"}
+	if !slices.Equal(got, want) {
+		t.Errorf("state keys = %q, want %q", got, want)
+	}
+}
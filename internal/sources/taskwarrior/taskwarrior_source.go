@@ -0,0 +1,352 @@
+package taskwarrior
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// dateLayout is the timestamp format of `task export`.
+const dateLayout = "20060102T150405Z"
+
+// Event kinds emitted per task.
+const (
+	KindCompleted = "completed"
+	KindStarted   = "started"
+	KindAnnotated = "annotated"
+)
+
+// Task is the subset of a Taskwarrior task that ikno reports on.
+type Task struct {
+	UUID        string
+	ID          int
+	Description string
+	Project     string
+	Tags        []string
+	Status      string // pending, completed, deleted, waiting, recurring
+	Urgency     float64
+	Start       time.Time
+	End         time.Time
+	Annotations []Annotation
+}
+
+// Annotation is a timestamped note attached to a task.
+type Annotation struct {
+	Entry       time.Time
+	Description string
+}
+
+// DefaultDataDir returns Taskwarrior's data directory: $TASKDATA if set,
+// else ~/.task.
+func DefaultDataDir() string {
+	if dir := os.Getenv("TASKDATA"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".task")
+}
+
+// TaskwarriorSource implements the Source interface for Taskwarrior. It runs
+// `task export`, or reads the 2.x data files (pending.data, completed.data)
+// directly when the data directory contains them.
+type TaskwarriorSource struct {
+	dataDir string // empty: use the task binary's own configuration
+	bin     string
+}
+
+// NewTaskwarriorSource creates a source for the given data directory. An
+// empty path defers to the task binary and its taskrc.
+func NewTaskwarriorSource(dataDir string) *TaskwarriorSource {
+	return &TaskwarriorSource{dataDir: dataDir, bin: "task"}
+}
+
+func (s *TaskwarriorSource) Type() string {
+	return "taskwarrior"
+}
+
+func (s *TaskwarriorSource) Location() string {
+	if s.dataDir == "" {
+		return "task"
+	}
+	return s.dataDir
+}
+
+func (s *TaskwarriorSource) Validate() error {
+	if s.hasDataFiles() {
+		return nil
+	}
+	if _, err := exec.LookPath(s.bin); err != nil {
+		return fmt.Errorf("taskwarrior not found: %q is not in PATH and %q has no data files", s.bin, s.dataDir)
+	}
+	return nil
+}
+
+func (s *TaskwarriorSource) hasDataFiles() bool {
+	if s.dataDir == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(s.dataDir, "pending.data"))
+	return err == nil
+}
+
+func (s *TaskwarriorSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	var tasks []Task
+	var err error
+	if s.hasDataFiles() {
+		tasks, err = s.readDataFiles()
+	} else {
+		tasks, err = s.export(ctx, from)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []sources.Entry
+	for _, t := range tasks {
+		entries = append(entries, taskEntries(t, from, to, s.Location())...)
+	}
+	return entries, nil
+}
+
+// export runs `task export` limited to tasks modified since from.
+func (s *TaskwarriorSource) export(ctx context.Context, from time.Time) ([]Task, error) {
+	args := []string{"rc.hooks=off", "rc.verbose=nothing", "rc.confirmation=off"}
+	if s.dataDir != "" {
+		args = append(args, "rc.data.location="+s.dataDir)
+	}
+	args = append(args, "modified.after:"+from.UTC().Format("2006-01-02T15:04:05Z"), "export")
+
+	cmd := exec.CommandContext(ctx, s.bin, args...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run task export: %w", err)
+	}
+	return ParseExport(output)
+}
+
+// exportTask mirrors one object of `task export` output.
+type exportTask struct {
+	ID          int      `json:"id"`
+	UUID        string   `json:"uuid"`
+	Description string   `json:"description"`
+	Project     string   `json:"project"`
+	Tags        []string `json:"tags"`
+	Status      string   `json:"status"`
+	Urgency     float64  `json:"urgency"`
+	Start       string   `json:"start"`
+	End         string   `json:"end"`
+	Annotations []struct {
+		Entry       string `json:"entry"`
+		Description string `json:"description"`
+	} `json:"annotations"`
+}
+
+// ParseExport parses the JSON array printed by `task export`. Older
+// versions print one object per line without the enclosing array.
+func ParseExport(data []byte) ([]Task, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if data[0] != '[' {
+		data = []byte("[" + strings.Join(strings.Split(strings.TrimRight(string(data), ",\n"), "\n"), "") + "]")
+	}
+
+	var raw []exportTask
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse task export: %w", err)
+	}
+
+	tasks := make([]Task, 0, len(raw))
+	for _, r := range raw {
+		t := Task{
+			UUID:        r.UUID,
+			ID:          r.ID,
+			Description: r.Description,
+			Project:     r.Project,
+			Tags:        r.Tags,
+			Status:      r.Status,
+			Urgency:     r.Urgency,
+			Start:       parseDate(r.Start),
+			End:         parseDate(r.End),
+		}
+		for _, a := range r.Annotations {
+			t.Annotations = append(t.Annotations, Annotation{Entry: parseDate(a.Entry), Description: a.Description})
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+func parseDate(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.Local()
+}
+
+// ff4AttrRegex matches one attribute of a Taskwarrior 2.x data line:
+// name:"value" with \" escapes inside the value.
+var ff4AttrRegex = regexp.MustCompile(`([A-Za-z0-9_.]+):"((?:[^"\\]|\\.)*)"`)
+
+func (s *TaskwarriorSource) readDataFiles() ([]Task, error) {
+	var tasks []Task
+	for _, name := range []string{"pending.data", "completed.data"} {
+		f, err := os.Open(filepath.Join(s.dataDir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			if t, ok := ParseDataLine(scanner.Text()); ok {
+				tasks = append(tasks, t)
+			}
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+	}
+	return tasks, nil
+}
+
+// ParseDataLine parses one line of a Taskwarrior 2.x data file:
+//
+//	[description:"Fix \"login\"" end:"1775556000" project:"infra" status:"completed" tags:"ops,urgent" uuid:"..."]
+//
+// Dates are Unix timestamps; annotations are stored as annotation_<epoch>.
+// Data files carry no urgency, which Taskwarrior computes on export.
+func ParseDataLine(line string) (Task, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return Task{}, false
+	}
+
+	var t Task
+	for _, m := range ff4AttrRegex.FindAllStringSubmatch(line, -1) {
+		value := unescapeFF4(m[2])
+		switch name := m[1]; {
+		case name == "uuid":
+			t.UUID = value
+		case name == "description":
+			t.Description = value
+		case name == "project":
+			t.Project = value
+		case name == "status":
+			t.Status = value
+		case name == "tags":
+			t.Tags = strings.Split(value, ",")
+		case name == "start":
+			t.Start = parseEpoch(value)
+		case name == "end":
+			t.End = parseEpoch(value)
+		case strings.HasPrefix(name, "annotation_"):
+			t.Annotations = append(t.Annotations, Annotation{
+				Entry:       parseEpoch(strings.TrimPrefix(name, "annotation_")),
+				Description: value,
+			})
+		}
+	}
+	if t.UUID == "" {
+		return Task{}, false
+	}
+	slices.SortFunc(t.Annotations, func(a, b Annotation) int { return a.Entry.Compare(b.Entry) })
+	return t, true
+}
+
+func unescapeFF4(s string) string {
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`, `&open;`, "[", `&close;`, "]").Replace(s)
+}
+
+func parseEpoch(s string) time.Time {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(n, 0)
+}
+
+// taskEntries returns one entry per event of t in [from, to]: completion,
+// start and each annotation. Deleted tasks are ignored.
+func taskEntries(t Task, from, to time.Time, location string) []sources.Entry {
+	if t.Status == "deleted" {
+		return nil
+	}
+	inRange := func(ts time.Time) bool {
+		return !ts.IsZero() && !ts.Before(from) && !ts.After(to)
+	}
+
+	var entries []sources.Entry
+	if t.Status == "completed" && inRange(t.End) {
+		entries = append(entries, taskEntry(t, KindCompleted, t.End, t.Description, location))
+	}
+	if inRange(t.Start) {
+		entries = append(entries, taskEntry(t, KindStarted, t.Start, t.Description, location))
+	}
+	for _, a := range t.Annotations {
+		if inRange(a.Entry) {
+			e := taskEntry(t, KindAnnotated, a.Entry, t.Description+" -- "+a.Description, location)
+			e.Metadata["annotation"] = a.Description
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func taskEntry(t Task, kind string, ts time.Time, text, location string) sources.Entry {
+	content := kind + ": " + text
+	if t.Project != "" {
+		content = fmt.Sprintf("[%s] %s", t.Project, content)
+	}
+
+	meta := map[string]string{
+		"kind":        kind,
+		"uuid":        t.UUID,
+		"task_status": t.Status,
+		"description": t.Description,
+	}
+	if t.Project != "" {
+		meta["project"] = t.Project
+	}
+	if len(t.Tags) > 0 {
+		meta["tags"] = strings.Join(t.Tags, ",")
+	}
+	if t.Urgency != 0 {
+		meta["urgency"] = strconv.FormatFloat(t.Urgency, 'f', 1, 64)
+	}
+	if t.ID != 0 {
+		meta["id"] = strconv.Itoa(t.ID)
+	}
+
+	return sources.Entry{
+		Timestamp: ts,
+		Source:    "taskwarrior",
+		Location:  location,
+		Content:   content,
+		Metadata:  meta,
+	}
+}
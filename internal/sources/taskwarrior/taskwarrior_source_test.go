@@ -0,0 +1,133 @@
+package taskwarrior
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	from = time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)
+	to   = time.Date(2026, 4, 7, 23, 59, 59, 0, time.UTC)
+)
+
+const sampleExport = `[
+{"id":0,"description":"Upgrade cluster","end":"20260407T150000Z","entry":"20260401T080000Z","modified":"20260407T150000Z","project":"infra","status":"completed","tags":["ops"],"uuid":"a1","urgency":4.2},
+{"id":3,"description":"Write runbook","entry":"20260405T080000Z","modified":"20260407T100000Z","project":"infra","start":"20260407T093000Z","status":"pending","uuid":"b2","urgency":8.9,
+ "annotations":[{"entry":"20260407T110000Z","description":"covered failover"},{"entry":"20260406T110000Z","description":"outline"}]},
+{"id":0,"description":"Dropped idea","end":"20260407T120000Z","status":"deleted","uuid":"c3"}
+]`
+
+func TestParseExport(t *testing.T) {
+	tasks, err := ParseExport([]byte(sampleExport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("expected 3 tasks, got %d", len(tasks))
+	}
+	if tasks[1].Urgency != 8.9 || len(tasks[1].Annotations) != 2 || tasks[1].Start.IsZero() {
+		t.Errorf("unexpected task: %+v", tasks[1])
+	}
+
+	// Taskwarrior < 2.5 prints comma-separated lines without brackets.
+	legacy := `{"description":"a","status":"pending","uuid":"1"},
+{"description":"b","status":"pending","uuid":"2"}`
+	tasks, err = ParseExport([]byte(legacy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 {
+		t.Errorf("expected 2 legacy tasks, got %d", len(tasks))
+	}
+}
+
+func TestTaskEntries(t *testing.T) {
+	tasks, err := ParseExport([]byte(sampleExport))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var contents []string
+	for _, task := range tasks {
+		for _, e := range taskEntries(task, from, to, "task") {
+			contents = append(contents, e.Content)
+		}
+	}
+
+	want := []string{
+		"[infra] completed: Upgrade cluster",
+		"[infra] started: Write runbook",
+		"[infra] annotated: Write runbook -- covered failover",
+	}
+	if strings.Join(contents, "|") != strings.Join(want, "|") {
+		t.Errorf("contents = %q, want %q", contents, want)
+	}
+
+	entries := taskEntries(tasks[0], from, to, "task")
+	meta := entries[0].Metadata
+	if meta["project"] != "infra" || meta["tags"] != "ops" || meta["urgency"] != "4.2" || meta["kind"] != KindCompleted {
+		t.Errorf("unexpected metadata: %v", meta)
+	}
+}
+
+func TestParseDataLine(t *testing.T) {
+	line := `[annotation_1775559600:"tested on &open;staging&close;" description:"Fix \"login\" bug" end:"1775574000" project:"web" status:"completed" tags:"bug,urgent" uuid:"d4"]`
+	task, ok := ParseDataLine(line)
+	if !ok {
+		t.Fatal("expected line to parse")
+	}
+	if task.Description != `Fix "login" bug` || task.Project != "web" || strings.Join(task.Tags, ",") != "bug,urgent" {
+		t.Errorf("unexpected task: %+v", task)
+	}
+	if !task.End.Equal(time.Unix(1775574000, 0)) {
+		t.Errorf("end = %v", task.End)
+	}
+	if len(task.Annotations) != 1 || task.Annotations[0].Description != "tested on [staging]" {
+		t.Errorf("annotations = %+v", task.Annotations)
+	}
+
+	if _, ok := ParseDataLine("not a task"); ok {
+		t.Error("expected invalid line to be rejected")
+	}
+}
+
+func TestTaskwarriorSource_DataFiles(t *testing.T) {
+	dir := t.TempDir()
+	pending := `[description:"Write runbook" project:"infra" start:"1775554200" status:"pending" uuid:"b2"]` + "\n"
+	completed := `[description:"Upgrade cluster" end:"1775574000" project:"infra" status:"completed" uuid:"a1"]` + "\n" +
+		`[description:"Old" end:"1775000000" status:"completed" uuid:"z9"]` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "pending.data"), []byte(pending), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "completed.data"), []byte(completed), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewTaskwarriorSource(dir)
+	source.bin = "ikno-test-no-such-binary"
+	if err := source.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := source.GetEntries(t.Context(), from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+	if entries[0].Metadata["kind"] != KindStarted || entries[1].Metadata["kind"] != KindCompleted {
+		t.Errorf("unexpected kinds: %s, %s", entries[0].Metadata["kind"], entries[1].Metadata["kind"])
+	}
+}
+
+func TestTaskwarriorSource_Validate_NoBinary(t *testing.T) {
+	source := NewTaskwarriorSource(t.TempDir())
+	source.bin = "ikno-test-no-such-binary"
+	if err := source.Validate(); err == nil {
+		t.Error("expected error without data files or task binary")
+	}
+}
@@ -0,0 +1,212 @@
+package timecsv
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/sources/timetrack"
+)
+
+// dateLayouts are the date formats Toggl and Clockify use in exports,
+// depending on the account's locale settings.
+var dateLayouts = []string{"2006-01-02", "01/02/2006", "02.01.2006", "2006/01/02", "02/01/2006"}
+
+// timeLayouts are the matching time-of-day formats.
+var timeLayouts = []string{"15:04:05", "15:04", "03:04:05 PM", "3:04:05 PM", "03:04 PM", "3:04 PM"}
+
+// columns maps our field names to the header names used by Toggl
+// ("Start date") and Clockify ("Start Date"). Matching is case-insensitive.
+var columns = map[string][]string{
+	"project":     {"project"},
+	"client":      {"client"},
+	"task":        {"task"},
+	"description": {"description"},
+	"tags":        {"tags"},
+	"billable":    {"billable"},
+	"start_date":  {"start date"},
+	"start_time":  {"start time"},
+	"end_date":    {"end date"},
+	"end_time":    {"end time"},
+}
+
+// CSVSource implements the Source interface for detailed time-entry CSV
+// exports from Toggl Track and Clockify.
+type CSVSource struct {
+	kind string // "toggl" or "clockify"
+	path string
+}
+
+// NewCSVSource creates a source for a CSV export file or a directory of
+// exports. kind is the source type the entries are reported under.
+func NewCSVSource(kind, path string) *CSVSource {
+	return &CSVSource{kind: kind, path: path}
+}
+
+func (s *CSVSource) Type() string {
+	return s.kind
+}
+
+func (s *CSVSource) Location() string {
+	return s.path
+}
+
+func (s *CSVSource) Validate() error {
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no .csv files found in %s", s.path)
+	}
+	return nil
+}
+
+func (s *CSVSource) files() ([]string, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("path not accessible: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{s.path}, nil
+	}
+	files, err := filepath.Glob(filepath.Join(s.path, "*.csv"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list csv files: %w", err)
+	}
+	return files, nil
+}
+
+func (s *CSVSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []sources.Entry
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+		}
+		intervals, err := Parse(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+		}
+
+		for _, iv := range intervals {
+			// Exports only contain finished entries, so now is irrelevant.
+			if iv.Overlaps(from, to, iv.End) {
+				entries = append(entries, iv.ToEntry(s.kind, path, iv.End))
+			}
+		}
+	}
+	return entries, nil
+}
+
+// Parse reads a detailed time-entry export. Rows with unparsable start or
+// end times are skipped; a missing required column is an error.
+func Parse(r io.Reader) ([]timetrack.Interval, error) {
+	// Excel-friendly exports start with a UTF-8 byte order mark, which
+	// encoding/csv would treat as part of the first field.
+	br := bufio.NewReader(r)
+	if bom, _ := br.Peek(3); string(bom) == "\ufeff" {
+		_, _ = br.Discard(3)
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	idx := columnIndex(header)
+	for _, required := range []string{"start_date", "start_time", "end_date", "end_time"} {
+		if _, ok := idx[required]; !ok {
+			return nil, fmt.Errorf("missing column %q", columns[required][0])
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := idx[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var intervals []timetrack.Interval
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok1 := parseDateTime(field(record, "start_date"), field(record, "start_time"))
+		end, ok2 := parseDateTime(field(record, "end_date"), field(record, "end_time"))
+		if !ok1 || !ok2 {
+			continue
+		}
+
+		iv := timetrack.Interval{
+			Start:       start,
+			End:         end,
+			Project:     field(record, "project"),
+			Client:      field(record, "client"),
+			Description: field(record, "description"),
+			Billable:    strings.EqualFold(field(record, "billable"), "yes") || strings.EqualFold(field(record, "billable"), "true"),
+		}
+		if task := field(record, "task"); task != "" {
+			if iv.Description == "" {
+				iv.Description = task
+			} else {
+				iv.Description = task + ": " + iv.Description
+			}
+		}
+		for tag := range strings.SplitSeq(field(record, "tags"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				iv.Tags = append(iv.Tags, tag)
+			}
+		}
+		intervals = append(intervals, iv)
+	}
+
+	return intervals, nil
+}
+
+func columnIndex(header []string) map[string]int {
+	idx := make(map[string]int)
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for name, aliases := range columns {
+			if slices.Contains(aliases, h) {
+				idx[name] = i
+			}
+		}
+	}
+	return idx
+}
+
+func parseDateTime(date, clock string) (time.Time, bool) {
+	for _, dl := range dateLayouts {
+		for _, tl := range timeLayouts {
+			if t, err := time.ParseInLocation(dl+" "+tl, date+" "+clock, time.Local); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,110 @@
+package timecsv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const togglExport = `User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount ()
+Jane,jane@example.com,Acme Corp,Website,,Pricing page,Yes,2026-04-07,09:00:00,2026-04-07,10:30:00,01:30:00,"dev, frontend",
+Jane,jane@example.com,,Internal,,Standup,No,2026-04-08,09:00:00,2026-04-08,09:15:00,00:15:00,,
+`
+
+const clockifyExport = "\ufeff" + `"Project","Client","Description","Task","User","Group","Email","Tags","Billable","Start Date","Start Time","End Date","End Time","Duration (h)","Duration (decimal)"
+"Infra","","Upgrade cluster","Maintenance","Jane","","jane@example.com","ops","No","04/07/2026","01:00:00 PM","04/07/2026","02:45:00 PM","01:45:00","1.75"
+`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		count       int
+		project     string
+		description string
+		tags        string
+		minutes     int
+		billable    bool
+	}{
+		{
+			name:        "toggl",
+			input:       togglExport,
+			count:       2,
+			project:     "Website",
+			description: "Pricing page",
+			tags:        "dev,frontend",
+			minutes:     90,
+			billable:    true,
+		},
+		{
+			name:        "clockify with BOM and 12h clock",
+			input:       clockifyExport,
+			count:       1,
+			project:     "Infra",
+			description: "Maintenance: Upgrade cluster",
+			tags:        "ops",
+			minutes:     105,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intervals, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(intervals) != tt.count {
+				t.Fatalf("expected %d intervals, got %d", tt.count, len(intervals))
+			}
+			iv := intervals[0]
+			if iv.Project != tt.project || iv.Description != tt.description {
+				t.Errorf("got project=%q description=%q", iv.Project, iv.Description)
+			}
+			if got := strings.Join(iv.Tags, ","); got != tt.tags {
+				t.Errorf("tags = %q, want %q", got, tt.tags)
+			}
+			if got := iv.DurationMinutes(iv.End); got != tt.minutes {
+				t.Errorf("minutes = %d, want %d", got, tt.minutes)
+			}
+			if iv.Billable != tt.billable {
+				t.Errorf("billable = %v, want %v", iv.Billable, tt.billable)
+			}
+		})
+	}
+}
+
+func TestParse_MissingColumns(t *testing.T) {
+	if _, err := Parse(strings.NewReader("Project,Description\nA,B\n")); err == nil {
+		t.Error("expected error for export without start/end columns")
+	}
+}
+
+func TestCSVSource_GetEntries(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "toggl.csv"), []byte(togglExport), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewCSVSource("toggl", dir)
+	if err := source.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2026, 4, 7, 0, 0, 0, 0, time.Local)
+	entries, err := source.GetEntries(t.Context(), from, from.Add(24*time.Hour-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Source != "toggl" || e.Content != "[Website] Pricing page -- 1h30m" {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if e.Metadata["client"] != "Acme Corp" || e.Metadata["billable"] != "true" {
+		t.Errorf("unexpected metadata: %v", e.Metadata)
+	}
+}
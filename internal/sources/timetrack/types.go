@@ -0,0 +1,108 @@
+package timetrack
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// Interval is one tracked block of time as recorded by a time tracker.
+// Sources produce these; ToEntry converts them to sources.Entry.
+type Interval struct {
+	Start       time.Time
+	End         time.Time // zero for a running interval
+	Project     string
+	Client      string
+	Description string
+	Tags        []string
+	Billable    bool
+	ID          string // tracker-specific identifier, if any
+}
+
+// DurationMinutes returns the interval length rounded to the nearest minute.
+// A running interval counts up to now.
+func (iv Interval) DurationMinutes(now time.Time) int {
+	end := iv.End
+	if end.IsZero() {
+		end = now
+	}
+	return int(math.Round(end.Sub(iv.Start).Minutes()))
+}
+
+// Overlaps reports whether the interval intersects [from, to]. Running
+// intervals extend to now.
+func (iv Interval) Overlaps(from, to, now time.Time) bool {
+	end := iv.End
+	if end.IsZero() {
+		end = now
+	}
+	return !iv.Start.After(to) && !end.Before(from)
+}
+
+// ToEntry converts an interval to an entry for the given source type.
+// Content reads "[project] description -- 1h30m"; metadata carries
+// duration_minutes, start/end times, project and tags.
+func (iv Interval) ToEntry(sourceType, location string, now time.Time) sources.Entry {
+	minutes := iv.DurationMinutes(now)
+
+	label := iv.Description
+	if label == "" {
+		label = strings.Join(iv.Tags, ", ")
+	}
+	if label == "" {
+		label = "(no description)"
+	}
+	content := fmt.Sprintf("%s -- %s", label, FormatMinutes(minutes))
+	if iv.Project != "" {
+		content = fmt.Sprintf("[%s] %s", iv.Project, content)
+	}
+
+	meta := map[string]string{
+		"kind":             "time",
+		"duration_minutes": strconv.Itoa(minutes),
+		"start":            iv.Start.Format(time.RFC3339),
+	}
+	if iv.End.IsZero() {
+		meta["running"] = "true"
+	} else {
+		meta["end"] = iv.End.Format(time.RFC3339)
+	}
+	if iv.Project != "" {
+		meta["project"] = iv.Project
+	}
+	if iv.Client != "" {
+		meta["client"] = iv.Client
+	}
+	if iv.Description != "" {
+		meta["description"] = iv.Description
+	}
+	if len(iv.Tags) > 0 {
+		meta["tags"] = strings.Join(iv.Tags, ",")
+	}
+	if iv.Billable {
+		meta["billable"] = "true"
+	}
+	if iv.ID != "" {
+		meta["id"] = iv.ID
+	}
+
+	return sources.Entry{
+		Timestamp: iv.Start,
+		Source:    sourceType,
+		Location:  location,
+		Content:   content,
+		Metadata:  meta,
+	}
+}
+
+// FormatMinutes renders a duration as "45m" or "2h05m".
+func FormatMinutes(m int) string {
+	if m < 60 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh%02dm", m/60, m%60)
+}
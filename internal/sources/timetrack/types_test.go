@@ -0,0 +1,83 @@
+package timetrack
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInterval_ToEntry(t *testing.T) {
+	start := time.Date(2026, 4, 7, 9, 0, 0, 0, time.UTC)
+	now := start.Add(3 * time.Hour)
+
+	tests := []struct {
+		name     string
+		iv       Interval
+		content  string
+		duration string
+		running  bool
+	}{
+		{
+			name:     "project and description",
+			iv:       Interval{Start: start, End: start.Add(90 * time.Minute), Project: "acme", Description: "pricing page", Tags: []string{"dev"}},
+			content:  "[acme] pricing page -- 1h30m",
+			duration: "90",
+		},
+		{
+			name:     "tags only",
+			iv:       Interval{Start: start, End: start.Add(20 * time.Minute), Tags: []string{"meeting", "sync"}},
+			content:  "meeting, sync -- 20m",
+			duration: "20",
+		},
+		{
+			name:     "running interval counts to now",
+			iv:       Interval{Start: start, Description: "debugging"},
+			content:  "debugging -- 3h00m",
+			duration: "180",
+			running:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := tt.iv.ToEntry("watson", "/frames", now)
+			if e.Content != tt.content {
+				t.Errorf("content = %q, want %q", e.Content, tt.content)
+			}
+			if e.Metadata["duration_minutes"] != tt.duration {
+				t.Errorf("duration_minutes = %q, want %q", e.Metadata["duration_minutes"], tt.duration)
+			}
+			if (e.Metadata["running"] == "true") != tt.running {
+				t.Errorf("running = %q, want %v", e.Metadata["running"], tt.running)
+			}
+			if e.Source != "watson" || !e.Timestamp.Equal(start) {
+				t.Errorf("unexpected entry: %+v", e)
+			}
+		})
+	}
+}
+
+func TestInterval_Overlaps(t *testing.T) {
+	day := time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)
+	from, to := day, day.Add(24*time.Hour-time.Second)
+	now := day.Add(48 * time.Hour)
+
+	tests := []struct {
+		name string
+		iv   Interval
+		want bool
+	}{
+		{"inside", Interval{Start: day.Add(9 * time.Hour), End: day.Add(10 * time.Hour)}, true},
+		{"starts before, ends inside", Interval{Start: day.Add(-time.Hour), End: day.Add(time.Hour)}, true},
+		{"entirely before", Interval{Start: day.Add(-2 * time.Hour), End: day.Add(-time.Hour)}, false},
+		{"entirely after", Interval{Start: day.Add(25 * time.Hour), End: day.Add(26 * time.Hour)}, false},
+		{"running since yesterday", Interval{Start: day.Add(-time.Hour)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.iv.Overlaps(from, to, now); got != tt.want {
+				t.Errorf("Overlaps = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
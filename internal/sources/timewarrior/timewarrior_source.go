@@ -0,0 +1,193 @@
+package timewarrior
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/sources/timetrack"
+)
+
+// timeLayout is the UTC timestamp format used in Timewarrior data files.
+const timeLayout = "20060102T150405Z"
+
+// DefaultDataDir returns the Timewarrior data directory: $TIMEWARRIORDB/data
+// if set, else ~/.timewarrior/data, else the XDG location.
+func DefaultDataDir() string {
+	if db := os.Getenv("TIMEWARRIORDB"); db != "" {
+		return filepath.Join(db, "data")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	legacy := filepath.Join(home, ".timewarrior", "data")
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy
+	}
+	return filepath.Join(home, ".local", "share", "timewarrior", "data")
+}
+
+// TimewarriorSource implements the Source interface for Timewarrior's
+// monthly data files (YYYY-MM.data).
+type TimewarriorSource struct {
+	dataDir string
+	now     func() time.Time
+}
+
+// NewTimewarriorSource creates a source for the given data directory. An
+// empty path uses DefaultDataDir.
+func NewTimewarriorSource(dataDir string) *TimewarriorSource {
+	if dataDir == "" {
+		dataDir = DefaultDataDir()
+	}
+	return &TimewarriorSource{dataDir: dataDir, now: time.Now}
+}
+
+func (s *TimewarriorSource) Type() string {
+	return "timewarrior"
+}
+
+func (s *TimewarriorSource) Location() string {
+	return s.dataDir
+}
+
+func (s *TimewarriorSource) Validate() error {
+	info, err := os.Stat(s.dataDir)
+	if err != nil {
+		return fmt.Errorf("timewarrior data directory not accessible: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("not a directory: %s", s.dataDir)
+	}
+	return nil
+}
+
+func (s *TimewarriorSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	var entries []sources.Entry
+
+	// An interval may start in the month before from, so include it.
+	first := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	for month := first; !month.After(to); month = month.AddDate(0, 1, 0) {
+		path := filepath.Join(s.dataDir, month.Format("2006-01")+".data")
+		intervals, err := readDataFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, iv := range intervals {
+			if iv.Overlaps(from, to, now) {
+				entries = append(entries, iv.ToEntry("timewarrior", s.dataDir, now))
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+func readDataFile(path string) ([]timetrack.Interval, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var intervals []timetrack.Interval
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if iv, ok := ParseLine(scanner.Text()); ok {
+			intervals = append(intervals, iv)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	return intervals, nil
+}
+
+// ParseLine parses one interval line of a Timewarrior data file:
+//
+//	inc 20260407T090000Z - 20260407T103000Z # tag1 "tag two" # "annotation"
+//
+// An interval without an end is still running. Timewarrior has no project
+// concept; the first tag is reported as project.
+func ParseLine(line string) (timetrack.Interval, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), "inc ")
+	if !ok {
+		return timetrack.Interval{}, false
+	}
+
+	span, tagPart, _ := strings.Cut(rest, " # ")
+	startStr, endStr, hasEnd := strings.Cut(strings.TrimSpace(span), " - ")
+
+	var iv timetrack.Interval
+	start, err := time.Parse(timeLayout, strings.TrimSpace(startStr))
+	if err != nil {
+		return timetrack.Interval{}, false
+	}
+	iv.Start = start.Local()
+	if hasEnd {
+		end, err := time.Parse(timeLayout, strings.TrimSpace(endStr))
+		if err != nil {
+			return timetrack.Interval{}, false
+		}
+		iv.End = end.Local()
+	}
+
+	tags, annotation, _ := strings.Cut(tagPart, " # ")
+	iv.Tags = splitQuoted(tags)
+	if words := splitQuoted(annotation); len(words) > 0 {
+		iv.Description = strings.Join(words, " ")
+	}
+	if len(iv.Tags) > 0 {
+		iv.Project = iv.Tags[0]
+	}
+
+	return iv, true
+}
+
+// splitQuoted splits on spaces, keeping "quoted words" together and
+// unescaping \" inside them.
+func splitQuoted(s string) []string {
+	var words []string
+	var cur strings.Builder
+	inQuote, escaped, started := false, false, false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && inQuote:
+			escaped = true
+		case r == '"':
+			inQuote = !inQuote
+			started = true
+		case r == ' ' && !inQuote:
+			if started {
+				words = append(words, cur.String())
+				cur.Reset()
+				started = false
+			}
+		default:
+			cur.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		words = append(words, cur.String())
+	}
+	return words
+}
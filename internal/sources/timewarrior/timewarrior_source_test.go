@@ -0,0 +1,101 @@
+package timewarrior
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		ok          bool
+		tags        string
+		description string
+		running     bool
+	}{
+		{
+			name: "closed interval with tags",
+			line: `inc 20260407T090000Z - 20260407T103000Z # acme "code review"`,
+			ok:   true,
+			tags: "acme|code review",
+		},
+		{
+			name:        "annotation",
+			line:        `inc 20260407T090000Z - 20260407T093000Z # infra # "fixed \"flaky\" test"`,
+			ok:          true,
+			tags:        "infra",
+			description: `fixed "flaky" test`,
+		},
+		{
+			name:    "running interval",
+			line:    `inc 20260407T140000Z # acme`,
+			ok:      true,
+			tags:    "acme",
+			running: true,
+		},
+		{
+			name: "no tags",
+			line: `inc 20260407T090000Z - 20260407T100000Z`,
+			ok:   true,
+		},
+		{
+			name: "not an interval",
+			line: `# comment`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iv, ok := ParseLine(tt.line)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if got := strings.Join(iv.Tags, "|"); got != tt.tags {
+				t.Errorf("tags = %q, want %q", got, tt.tags)
+			}
+			if iv.Description != tt.description {
+				t.Errorf("description = %q, want %q", iv.Description, tt.description)
+			}
+			if iv.End.IsZero() != tt.running {
+				t.Errorf("running = %v, want %v", iv.End.IsZero(), tt.running)
+			}
+		})
+	}
+}
+
+func TestTimewarriorSource_GetEntries(t *testing.T) {
+	dir := t.TempDir()
+	march := "inc 20260331T220000Z - 20260331T230000Z # old\n"
+	april := "inc 20260407T090000Z - 20260407T103000Z # acme review\ninc 20260420T090000Z - 20260420T100000Z # later\n"
+	if err := os.WriteFile(filepath.Join(dir, "2026-03.data"), []byte(march), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "2026-04.data"), []byte(april), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewTimewarriorSource(dir)
+	from := time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 7, 23, 59, 59, 0, time.UTC)
+	entries, err := source.GetEntries(t.Context(), from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %+v", len(entries), entries)
+	}
+	e := entries[0]
+	if e.Metadata["duration_minutes"] != "90" || e.Metadata["project"] != "acme" || e.Metadata["tags"] != "acme,review" {
+		t.Errorf("unexpected metadata: %v", e.Metadata)
+	}
+	if e.Source != "timewarrior" {
+		t.Errorf("source = %q", e.Source)
+	}
+}
@@ -0,0 +1,251 @@
+package todotxt
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	priorityRegex = regexp.MustCompile(`^\(([A-Z])\)\s+`)
+	dateRegex     = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\s+`)
+	keyValueRegex = regexp.MustCompile(`^([^\s:]+):([^\s:]+)$`)
+)
+
+// Task is one line of a todo.txt file.
+type Task struct {
+	Done       bool
+	Priority   string
+	Completed  time.Time
+	Created    time.Time
+	Text       string // description with key:value pairs removed
+	Projects   []string
+	Contexts   []string
+	Due        time.Time
+	Attributes map[string]string // remaining key:value pairs
+	Line       int
+}
+
+// ParseLine parses a todo.txt line following the format described at
+// https://github.com/todotxt/todo.txt. Returns false for blank lines.
+func ParseLine(line string) (Task, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return Task{}, false
+	}
+
+	var task Task
+	if rest, ok := strings.CutPrefix(line, "x "); ok {
+		task.Done = true
+		line = rest
+		if m := dateRegex.FindStringSubmatch(line); m != nil {
+			task.Completed, _ = time.ParseInLocation(dateLayout, m[1], time.Local)
+			line = line[len(m[0]):]
+		}
+	}
+	if m := priorityRegex.FindStringSubmatch(line); m != nil {
+		task.Priority = m[1]
+		line = line[len(m[0]):]
+	}
+	if m := dateRegex.FindStringSubmatch(line); m != nil {
+		task.Created, _ = time.ParseInLocation(dateLayout, m[1], time.Local)
+		line = line[len(m[0]):]
+	}
+
+	var words []string
+	for _, word := range strings.Fields(line) {
+		switch {
+		case len(word) > 1 && word[0] == '+':
+			task.Projects = append(task.Projects, word[1:])
+			continue
+		case len(word) > 1 && word[0] == '@':
+			task.Contexts = append(task.Contexts, word[1:])
+			continue
+		case keyValueRegex.MatchString(word):
+			m := keyValueRegex.FindStringSubmatch(word)
+			if m[1] == "due" {
+				if due, err := time.ParseInLocation(dateLayout, m[2], time.Local); err == nil {
+					task.Due = due
+					continue
+				}
+			}
+			if task.Attributes == nil {
+				task.Attributes = make(map[string]string)
+			}
+			task.Attributes[m[1]] = m[2]
+			continue
+		}
+		words = append(words, word)
+	}
+	task.Text = strings.Join(words, " ")
+
+	return task, true
+}
+
+// TodoTxtSource implements the Source interface for todo.txt files. It
+// reports tasks completed in range (from todo.txt and done.txt) and open
+// tasks due in range.
+type TodoTxtSource struct {
+	path string
+}
+
+// NewTodoTxtSource creates a source for a todo.txt file or a directory
+// containing todo.txt and done.txt.
+func NewTodoTxtSource(path string) *TodoTxtSource {
+	return &TodoTxtSource{path: path}
+}
+
+func (s *TodoTxtSource) Type() string {
+	return "todotxt"
+}
+
+func (s *TodoTxtSource) Location() string {
+	return s.path
+}
+
+func (s *TodoTxtSource) Validate() error {
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no todo.txt or done.txt found in %s", s.path)
+	}
+	return nil
+}
+
+// files returns the todo.txt files to read. A directory contributes its
+// todo.txt and done.txt; a file path is used as is, together with a done.txt
+// next to it.
+func (s *TodoTxtSource) files() ([]string, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("path not accessible: %w", err)
+	}
+
+	var candidates []string
+	if info.IsDir() {
+		candidates = []string{filepath.Join(s.path, "todo.txt"), filepath.Join(s.path, "done.txt")}
+	} else {
+		candidates = []string{s.path}
+		if done := filepath.Join(filepath.Dir(s.path), "done.txt"); done != s.path {
+			candidates = append(candidates, done)
+		}
+	}
+
+	var files []string
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			files = append(files, c)
+		}
+	}
+	return files, nil
+}
+
+func (s *TodoTxtSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []sources.Entry
+	for _, path := range files {
+		fileEntries, err := readFile(path, from, to)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries, nil
+}
+
+func readFile(path string, from, to time.Time) ([]sources.Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []sources.Entry
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		task, ok := ParseLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		task.Line = lineNum
+
+		var ts time.Time
+		var content string
+		switch {
+		case task.Done && !task.Completed.IsZero():
+			ts, content = task.Completed, "done: "+task.Text
+		case !task.Done && !task.Due.IsZero():
+			ts, content = task.Due, fmt.Sprintf("open: %s (due %s)", task.Text, task.Due.Format(dateLayout))
+		default:
+			continue
+		}
+		// Dates have day precision, so compare against the start of from's day.
+		if ts.Before(startOfDay(from)) || ts.After(to) {
+			continue
+		}
+
+		entries = append(entries, sources.Entry{
+			Timestamp: ts,
+			Source:    "todotxt",
+			Location:  path,
+			Content:   content,
+			Metadata:  taskMetadata(task, path),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	return entries, nil
+}
+
+func taskMetadata(task Task, path string) map[string]string {
+	meta := make(map[string]string, len(task.Attributes)+6)
+	for k, v := range task.Attributes {
+		meta["attr_"+k] = v
+	}
+	meta["file"] = filepath.Base(path)
+	meta["line"] = strconv.Itoa(task.Line)
+	if task.Done {
+		meta["task_status"] = "done"
+	} else {
+		meta["task_status"] = "open"
+	}
+	if task.Priority != "" {
+		meta["priority"] = task.Priority
+	}
+	if len(task.Projects) > 0 {
+		meta["projects"] = strings.Join(task.Projects, ",")
+	}
+	if len(task.Contexts) > 0 {
+		meta["contexts"] = strings.Join(task.Contexts, ",")
+	}
+	if !task.Created.IsZero() {
+		meta["created_date"] = task.Created.Format(dateLayout)
+	}
+	if !task.Due.IsZero() {
+		meta["due_date"] = task.Due.Format(dateLayout)
+	}
+	return meta
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,123 @@
+package todotxt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		done     bool
+		priority string
+		text     string
+		projects string
+		contexts string
+		due      string
+	}{
+		{
+			name: "plain task",
+			line: "Call mom",
+			text: "Call mom",
+		},
+		{
+			name:     "priority, created date, projects and contexts",
+			line:     "(A) 2026-04-01 Ship pricing page +acme @work due:2026-04-10",
+			priority: "A",
+			text:     "Ship pricing page",
+			projects: "acme",
+			contexts: "work",
+			due:      "2026-04-10",
+		},
+		{
+			name:     "completed task",
+			line:     "x 2026-04-07 2026-04-01 Review PR +acme +billing",
+			done:     true,
+			text:     "Review PR",
+			projects: "acme,billing",
+		},
+		{
+			name: "x without space is not done",
+			line: "xylophone lessons",
+			text: "xylophone lessons",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, ok := ParseLine(tt.line)
+			if !ok {
+				t.Fatal("expected line to parse")
+			}
+			if task.Done != tt.done || task.Priority != tt.priority || task.Text != tt.text {
+				t.Errorf("got done=%v priority=%q text=%q", task.Done, task.Priority, task.Text)
+			}
+			if got := strings.Join(task.Projects, ","); got != tt.projects {
+				t.Errorf("projects = %q, want %q", got, tt.projects)
+			}
+			if got := strings.Join(task.Contexts, ","); got != tt.contexts {
+				t.Errorf("contexts = %q, want %q", got, tt.contexts)
+			}
+			var due string
+			if !task.Due.IsZero() {
+				due = task.Due.Format(dateLayout)
+			}
+			if due != tt.due {
+				t.Errorf("due = %q, want %q", due, tt.due)
+			}
+		})
+	}
+
+	if _, ok := ParseLine("   "); ok {
+		t.Error("blank line should not parse")
+	}
+}
+
+func TestTodoTxtSource_GetEntries(t *testing.T) {
+	dir := t.TempDir()
+	todo := "(A) Write report due:2026-04-07 +acme\nx 2026-04-07 Fix login bug +acme\nNo dates here\n"
+	done := "x 2026-04-07 2026-04-02 Deploy hotfix\nx 2026-03-30 Old task\n"
+	if err := os.WriteFile(filepath.Join(dir, "todo.txt"), []byte(todo), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "done.txt"), []byte(done), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewTodoTxtSource(dir)
+	if err := source.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2026, 4, 7, 0, 0, 0, 0, time.Local)
+	to := time.Date(2026, 4, 7, 23, 59, 59, 0, time.Local)
+	entries, err := source.GetEntries(t.Context(), from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var contents []string
+	for _, e := range entries {
+		contents = append(contents, e.Content)
+	}
+	want := []string{"open: Write report (due 2026-04-07)", "done: Fix login bug", "done: Deploy hotfix"}
+	if strings.Join(contents, "|") != strings.Join(want, "|") {
+		t.Errorf("contents = %v, want %v", contents, want)
+	}
+	if entries[0].Metadata["projects"] != "acme" || entries[0].Metadata["priority"] != "A" {
+		t.Errorf("unexpected metadata: %v", entries[0].Metadata)
+	}
+}
+
+func TestTodoTxtSource_Validate(t *testing.T) {
+	if err := NewTodoTxtSource(t.TempDir()).Validate(); err == nil {
+		t.Error("expected error for directory without todo.txt")
+	}
+	if err := NewTodoTxtSource("/does/not/exist").Validate(); err == nil {
+		t.Error("expected error for missing path")
+	}
+}
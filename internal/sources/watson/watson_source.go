@@ -0,0 +1,147 @@
+package watson
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charemma/ikno/internal/sources"
+	"github.com/charemma/ikno/internal/sources/timetrack"
+)
+
+// DefaultDir returns Watson's application directory: $WATSON_DIR if set,
+// else ~/.config/watson.
+func DefaultDir() string {
+	if dir := os.Getenv("WATSON_DIR"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "watson")
+}
+
+// WatsonSource implements the Source interface for Watson's frames file.
+// The frame currently being tracked is read from the state file next to it.
+type WatsonSource struct {
+	dir string
+	now func() time.Time
+}
+
+// NewWatsonSource creates a source for a Watson directory (containing
+// "frames") or a frames file. An empty path uses DefaultDir.
+func NewWatsonSource(path string) *WatsonSource {
+	if path == "" {
+		path = DefaultDir()
+	}
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		path = filepath.Dir(path)
+	}
+	return &WatsonSource{dir: path, now: time.Now}
+}
+
+func (s *WatsonSource) Type() string {
+	return "watson"
+}
+
+func (s *WatsonSource) Location() string {
+	return s.dir
+}
+
+func (s *WatsonSource) Validate() error {
+	if _, err := os.Stat(filepath.Join(s.dir, "frames")); err != nil {
+		return fmt.Errorf("watson frames file not accessible: %w", err)
+	}
+	return nil
+}
+
+// frame mirrors one element of the frames array:
+// [start, stop, project, id, tags, updated_at].
+type frame struct {
+	Start   int64
+	Stop    int64
+	Project string
+	ID      string
+	Tags    []string
+}
+
+func (f *frame) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) < 3 {
+		return fmt.Errorf("frame has %d fields, want at least 3", len(raw))
+	}
+	var start, stop float64
+	if err := json.Unmarshal(raw[0], &start); err != nil {
+		return fmt.Errorf("invalid frame start: %w", err)
+	}
+	if err := json.Unmarshal(raw[1], &stop); err != nil {
+		return fmt.Errorf("invalid frame stop: %w", err)
+	}
+	if err := json.Unmarshal(raw[2], &f.Project); err != nil {
+		return fmt.Errorf("invalid frame project: %w", err)
+	}
+	f.Start, f.Stop = int64(start), int64(stop)
+	if len(raw) > 3 {
+		_ = json.Unmarshal(raw[3], &f.ID)
+	}
+	if len(raw) > 4 {
+		_ = json.Unmarshal(raw[4], &f.Tags)
+	}
+	return nil
+}
+
+// state is Watson's record of the frame being tracked right now.
+type state struct {
+	Project string   `json:"project"`
+	Start   float64  `json:"start"`
+	Tags    []string `json:"tags"`
+}
+
+func (s *WatsonSource) GetEntries(ctx context.Context, from, to time.Time) ([]sources.Entry, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, "frames"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watson frames: %w", err)
+	}
+	var frames []frame
+	if err := json.Unmarshal(data, &frames); err != nil {
+		return nil, fmt.Errorf("failed to parse watson frames: %w", err)
+	}
+
+	intervals := make([]timetrack.Interval, 0, len(frames)+1)
+	for _, f := range frames {
+		intervals = append(intervals, timetrack.Interval{
+			Start:   time.Unix(f.Start, 0),
+			End:     time.Unix(f.Stop, 0),
+			Project: f.Project,
+			Tags:    f.Tags,
+			ID:      f.ID,
+		})
+	}
+
+	if data, err := os.ReadFile(filepath.Join(s.dir, "state")); err == nil {
+		var st state
+		if err := json.Unmarshal(data, &st); err == nil && st.Project != "" {
+			intervals = append(intervals, timetrack.Interval{
+				Start:   time.Unix(int64(st.Start), 0),
+				Project: st.Project,
+				Tags:    st.Tags,
+			})
+		}
+	}
+
+	now := s.now()
+	var entries []sources.Entry
+	for _, iv := range intervals {
+		if iv.Overlaps(from, to, now) {
+			entries = append(entries, iv.ToEntry("watson", s.dir, now))
+		}
+	}
+	return entries, nil
+}
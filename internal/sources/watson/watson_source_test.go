@@ -0,0 +1,66 @@
+package watson
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatsonSource_GetEntries(t *testing.T) {
+	dir := t.TempDir()
+	day := time.Date(2026, 4, 7, 0, 0, 0, 0, time.UTC)
+	start := day.Add(9 * time.Hour).Unix()
+
+	writeFile(t, dir, "frames", `[
+  [`+itoa(start)+`, `+itoa(start+5400)+`, "acme", "f1", ["review", "billing"], 1775552400],
+  [`+itoa(start-86400)+`, `+itoa(start-82800)+`, "infra", "f0", [], 1775466000]
+]`)
+	writeFile(t, dir, "state", `{"project": "ikno", "start": `+itoa(start+7200)+`, "tags": ["docs"]}`)
+
+	source := NewWatsonSource(filepath.Join(dir, "frames"))
+	if source.Location() != dir {
+		t.Errorf("location = %q, want %q", source.Location(), dir)
+	}
+	if err := source.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	source.now = func() time.Time { return time.Unix(start+9000, 0) }
+
+	entries, err := source.GetEntries(t.Context(), day, day.Add(24*time.Hour-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+
+	if entries[0].Content != "[acme] review, billing -- 1h30m" || entries[0].Metadata["id"] != "f1" {
+		t.Errorf("unexpected frame entry: %+v", entries[0])
+	}
+	running := entries[1]
+	if running.Metadata["running"] != "true" || running.Metadata["duration_minutes"] != "30" || running.Metadata["project"] != "ikno" {
+		t.Errorf("unexpected running entry: %+v", running)
+	}
+}
+
+func TestWatsonSource_InvalidFrames(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "frames", `{"not": "an array"}`)
+
+	if _, err := NewWatsonSource(dir).GetEntries(t.Context(), time.Time{}, time.Now()); err == nil {
+		t.Error("expected error for malformed frames file")
+	}
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
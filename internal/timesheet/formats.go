@@ -0,0 +1,100 @@
+package timesheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// writeCSV writes a plain timesheet with decimal hours.
+func writeCSV(w io.Writer, rows []Row) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"date", "project", "client", "billable", "hours", "estimated_hours", "description"})
+	for _, r := range rows {
+		_ = cw.Write([]string{
+			r.Date.Format("2006-01-02"),
+			r.Project,
+			r.Client,
+			strconv.FormatBool(r.Billable),
+			decimalHours(r.Duration),
+			decimalHours(r.Estimated),
+			r.Description,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeHarvest writes Harvest's time entry import format.
+func writeHarvest(w io.Writer, rows []Row, opts Options) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"Date", "Client", "Project", "Task", "Notes", "Hours", "First name", "Last name"})
+	for _, r := range rows {
+		_ = cw.Write([]string{
+			r.Date.Format("2006-01-02"),
+			r.Client,
+			r.Project,
+			opts.Task,
+			r.Description,
+			decimalHours(r.Duration),
+			opts.FirstName,
+			opts.LastName,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeToggl writes Toggl Track's CSV import format.
+func writeToggl(w io.Writer, rows []Row, opts Options) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"Email", "Start date", "Start time", "Duration", "Project", "Client", "Description", "Billable"})
+	for _, r := range rows {
+		billable := "No"
+		if r.Billable {
+			billable = "Yes"
+		}
+		start := r.start()
+		_ = cw.Write([]string{
+			opts.Email,
+			start.Format("2006-01-02"),
+			start.Format("15:04:05"),
+			clockDuration(r.Duration),
+			r.Project,
+			r.Client,
+			r.Description,
+			billable,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// start is when the row's work began to the minute, or 9:00 on its day
+// when unknown.
+func (r Row) start() time.Time {
+	if !r.Start.IsZero() {
+		return r.Start.Local().Truncate(time.Minute)
+	}
+	return r.Date.Add(9 * time.Hour)
+}
+
+// decimalHours renders a duration as hours with two decimals ("1.25").
+func decimalHours(d time.Duration) string {
+	return strconv.FormatFloat(d.Round(time.Minute).Hours(), 'f', 2, 64)
+}
+
+// clockDuration renders a duration as HH:MM:SS.
+func clockDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// SplitName splits a full name into first and last name at the first space.
+func SplitName(name string) (first, last string) {
+	first, last, _ = strings.Cut(strings.TrimSpace(name), " ")
+	return first, strings.TrimSpace(last)
+}
@@ -0,0 +1,86 @@
+package timesheet
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const icsTimeFormat = "20060102T150405Z"
+
+var uidUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// writeICS writes one event per row, starting at the row's first session
+// and lasting its rounded duration. UIDs are derived from day and project,
+// so a re-import updates events instead of duplicating them.
+func writeICS(w io.Writer, rows []Row, opts Options) error {
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	stamp := now().UTC().Format(icsTimeFormat)
+
+	bw := bufio.NewWriter(w)
+	line := func(s string) { _, _ = bw.WriteString(foldLine(s) + "\r\n") }
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//ikno//timesheet//EN")
+	line("CALSCALE:GREGORIAN")
+	for _, r := range rows {
+		start := r.start()
+		uid := fmt.Sprintf("%s-%s@ikno", r.Date.Format("20060102"), strings.Trim(uidUnsafe.ReplaceAllString(strings.ToLower(r.Project), "-"), "-"))
+
+		summary := r.Project
+		if r.Client != "" {
+			summary += " (" + r.Client + ")"
+		}
+
+		line("BEGIN:VEVENT")
+		line("UID:" + uid)
+		line("DTSTAMP:" + stamp)
+		line("DTSTART:" + start.UTC().Format(icsTimeFormat))
+		line("DTEND:" + start.Add(r.Duration).UTC().Format(icsTimeFormat))
+		line("SUMMARY:" + escapeText(summary))
+		description := fmt.Sprintf("%s (estimated %s)", cmp.Or(r.Description, "no description"), decimalHours(r.Estimated)+"h")
+		line("DESCRIPTION:" + escapeText(description))
+		if r.Billable {
+			line("CATEGORIES:billable")
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11).
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldLine folds content lines longer than 75 octets, without splitting
+// UTF-8 sequences.
+func foldLine(s string) string {
+	const limit = 75
+	if len(s) <= limit {
+		return s
+	}
+	var b strings.Builder
+	width := limit
+	for len(s) > width {
+		cut := width
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		width = limit - 1 // continuation lines start with a space
+	}
+	b.WriteString(s)
+	return b.String()
+}
@@ -0,0 +1,209 @@
+// Package timesheet turns estimated working hours into timesheet rows and
+// writes them as CSV, Harvest or Toggl import files, or iCalendar events.
+package timesheet
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charemma/ikno/internal/projects"
+	"github.com/charemma/ikno/internal/recap"
+	"github.com/charemma/ikno/internal/sources"
+)
+
+// Supported output formats.
+const (
+	FormatCSV     = "csv"
+	FormatHarvest = "harvest"
+	FormatToggl   = "toggl"
+	FormatICS     = "ics"
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{FormatCSV, FormatHarvest, FormatToggl, FormatICS}
+
+// Rounding modes.
+const (
+	RoundUp      = "up"
+	RoundNearest = "nearest"
+	RoundDown    = "down"
+)
+
+// maxDescription bounds the description of a row; import tools truncate
+// or reject longer notes.
+const maxDescription = 500
+
+// Rounding controls how estimated durations become billed durations.
+type Rounding struct {
+	Increment time.Duration // round to multiples of this; 0 keeps minutes
+	Minimum   time.Duration // shortest row; shorter estimates are raised to it
+	Mode      string        // up, nearest or down
+}
+
+// DefaultRounding rounds up to 15 minutes.
+func DefaultRounding() Rounding {
+	return Rounding{Increment: 15 * time.Minute, Minimum: 15 * time.Minute, Mode: RoundUp}
+}
+
+// Validate checks the rounding mode and durations.
+func (r Rounding) Validate() error {
+	switch r.Mode {
+	case RoundUp, RoundNearest, RoundDown:
+	default:
+		return fmt.Errorf("invalid rounding %q (must be up, nearest or down)", r.Mode)
+	}
+	if r.Increment < 0 || r.Minimum < 0 {
+		return fmt.Errorf("rounding increment and minimum must not be negative")
+	}
+	return nil
+}
+
+// Apply rounds an estimated duration.
+func (r Rounding) Apply(d time.Duration) time.Duration {
+	d = d.Round(time.Minute)
+	if r.Increment > 0 {
+		steps := float64(d) / float64(r.Increment)
+		switch r.Mode {
+		case RoundUp:
+			steps = math.Ceil(steps)
+		case RoundDown:
+			steps = math.Floor(steps)
+		default:
+			steps = math.Round(steps)
+		}
+		d = time.Duration(steps) * r.Increment
+	}
+	return max(d, r.Minimum)
+}
+
+// Row is one day's work on one project.
+type Row struct {
+	Date        time.Time
+	Project     string
+	Client      string
+	Billable    bool
+	Start       time.Time     // start of the first session
+	Estimated   time.Duration // before rounding
+	Duration    time.Duration // after rounding
+	Description string
+}
+
+// Build creates one row per day and project of the estimate, oldest first.
+// Client and billable flag come from the configured projects.
+func Build(estimate *recap.HoursEstimate, configured []projects.Project, rounding Rounding) []Row {
+	var rows []Row
+	for _, day := range estimate.Days {
+		for _, ph := range day.Projects {
+			row := Row{
+				Date:        day.Date,
+				Project:     ph.Project,
+				Start:       ph.Start,
+				Estimated:   ph.Duration,
+				Duration:    rounding.Apply(ph.Duration),
+				Description: Describe(ph.Activity),
+			}
+			if p, ok := projects.Find(configured, ph.Project); ok {
+				row.Client = p.Client
+				row.Billable = p.Billable
+			}
+			rows = append(rows, row)
+		}
+	}
+	slices.SortStableFunc(rows, func(a, b Row) int {
+		if c := a.Date.Compare(b.Date); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Project, b.Project)
+	})
+	return rows
+}
+
+// describeOrder ranks sources for descriptions: commits and finished tasks
+// say best what was done.
+var describeOrder = map[string]int{
+	"git": 0, "taskwarrior": 1, "todotxt": 1, "org": 1, "logseq": 1, "git-bug": 1,
+	"claude": 2, "toggl": 2, "clockify": 2, "timewarrior": 2, "watson": 2,
+}
+
+// Describe builds a row description from entry contents: first lines,
+// without "[project]" prefixes and "-- duration" suffixes, deduplicated and
+// joined with "; ". Entries from ci, editor, containers and activitywatch
+// are left out; they describe tools, not work.
+func Describe(entries []sources.Entry) string {
+	entries = slices.Clone(entries)
+	slices.SortStableFunc(entries, func(a, b sources.Entry) int {
+		return rank(a.Source) - rank(b.Source)
+	})
+
+	var parts []string
+	seen := make(map[string]bool)
+	length := 0
+	for _, e := range entries {
+		switch e.Source {
+		case "ci", "editor", "containers", "activitywatch":
+			continue
+		}
+		text := summaryLine(e.Content)
+		key := strings.ToLower(text)
+		if text == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if length+len(text)+2 > maxDescription {
+			break
+		}
+		parts = append(parts, text)
+		length += len(text) + 2
+	}
+	return strings.Join(parts, "; ")
+}
+
+func rank(source string) int {
+	if r, ok := describeOrder[source]; ok {
+		return r
+	}
+	return 3
+}
+
+// summaryLine reduces entry content to a single descriptive line.
+func summaryLine(content string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	if strings.HasPrefix(line, "[") {
+		if _, rest, ok := strings.Cut(line, "] "); ok {
+			line = rest
+		}
+	}
+	if i := strings.LastIndex(line, " -- "); i > 0 {
+		line = line[:i]
+	}
+	return strings.TrimSpace(line)
+}
+
+// Write writes the rows in the given format.
+func Write(w io.Writer, format string, rows []Row, opts Options) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, rows)
+	case FormatHarvest:
+		return writeHarvest(w, rows, opts)
+	case FormatToggl:
+		return writeToggl(w, rows, opts)
+	case FormatICS:
+		return writeICS(w, rows, opts)
+	default:
+		return fmt.Errorf("unsupported format %q (must be one of %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Options holds the per-user fields some import formats require.
+type Options struct {
+	FirstName string // Harvest
+	LastName  string // Harvest
+	Task      string // Harvest task, e.g. "Development"
+	Email     string // Toggl
+	Now       func() time.Time
+}
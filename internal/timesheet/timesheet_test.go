@@ -0,0 +1,142 @@
+package timesheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charemma/ikno/internal/projects"
+	"github.com/charemma/ikno/internal/recap"
+	"github.com/charemma/ikno/internal/sources"
+)
+
+func TestRounding_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		rounding Rounding
+		in       time.Duration
+		want     time.Duration
+	}{
+		{"up", DefaultRounding(), 61 * time.Minute, 75 * time.Minute},
+		{"up exact", DefaultRounding(), 60 * time.Minute, 60 * time.Minute},
+		{"minimum", DefaultRounding(), 3 * time.Minute, 15 * time.Minute},
+		{"nearest", Rounding{Increment: 15 * time.Minute, Mode: RoundNearest}, 67 * time.Minute, 60 * time.Minute},
+		{"down", Rounding{Increment: 30 * time.Minute, Mode: RoundDown}, 89 * time.Minute, 60 * time.Minute},
+		{"exact minutes", Rounding{Mode: RoundUp}, 61*time.Minute + 20*time.Second, 61 * time.Minute},
+		{"raised minimum", Rounding{Increment: 6 * time.Minute, Minimum: time.Hour, Mode: RoundUp}, 20 * time.Minute, time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rounding.Apply(tt.in); got != tt.want {
+				t.Errorf("Apply(%s) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	if err := (Rounding{Mode: "sideways"}).Validate(); err == nil {
+		t.Error("expected error for unknown rounding mode")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	entries := []sources.Entry{
+		{Source: "claude", Content: "[shop] fix the flaky checkout test -- 12 turns, 30 min"},
+		{Source: "git", Content: "fix: stop sharing the test database\n\nLonger body."},
+		{Source: "ci", Content: "[shop] Integration #58 on main: passed after 9m"},
+		{Source: "git", Content: "fix: stop sharing the test database"},
+		{Source: "toggl", Content: "[shop] Checkout review -- 1h"},
+	}
+	got := Describe(entries)
+	want := "fix: stop sharing the test database; fix the flaky checkout test; Checkout review"
+	if got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+}
+
+// fixtureRows builds rows for two days of work on two projects.
+func fixtureRows(t *testing.T) []Row {
+	t.Helper()
+	day := time.Date(2026, 4, 7, 0, 0, 0, 0, time.Local)
+	estimate := &recap.HoursEstimate{Days: []recap.DayHours{{
+		Date: day,
+		Projects: []recap.ProjectHours{
+			{Project: "acme-shop", Duration: 100 * time.Minute, Start: day.Add(9*time.Hour + 10*time.Minute + 42*time.Second),
+				Activity: []sources.Entry{{Source: "git", Content: "feat: coupons, part 1"}}},
+			{Project: "ikno", Duration: 20 * time.Minute, Start: day.Add(14 * time.Hour),
+				Activity: []sources.Entry{{Source: "git", Content: "docs: timesheet export"}}},
+		},
+	}}}
+	configured := []projects.Project{{Name: "acme-shop", Client: "ACME Corp", Billable: true}}
+	return Build(estimate, configured, DefaultRounding())
+}
+
+func TestWrite(t *testing.T) {
+	rows := fixtureRows(t)
+	if len(rows) != 2 || rows[0].Project != "acme-shop" || rows[0].Duration != 105*time.Minute || rows[0].Client != "ACME Corp" {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+
+	opts := Options{FirstName: "Jo", LastName: "Doe", Task: "Development", Email: "jo@example.com",
+		Now: func() time.Time { return time.Date(2026, 4, 8, 12, 0, 0, 0, time.UTC) }}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{FormatCSV, []string{
+			"date,project,client,billable,hours,estimated_hours,description",
+			`2026-04-07,acme-shop,ACME Corp,true,1.75,1.67,"feat: coupons, part 1"`,
+			"2026-04-07,ikno,,false,0.50,0.33,docs: timesheet export",
+		}},
+		{FormatHarvest, []string{
+			"Date,Client,Project,Task,Notes,Hours,First name,Last name",
+			`2026-04-07,ACME Corp,acme-shop,Development,"feat: coupons, part 1",1.75,Jo,Doe`,
+		}},
+		{FormatToggl, []string{
+			"Email,Start date,Start time,Duration,Project,Client,Description,Billable",
+			`jo@example.com,2026-04-07,09:10:00,01:45:00,acme-shop,ACME Corp,"feat: coupons, part 1",Yes`,
+			"jo@example.com,2026-04-07,14:00:00,00:30:00,ikno,,docs: timesheet export,No",
+		}},
+		{FormatICS, []string{
+			"BEGIN:VCALENDAR\r\n",
+			"UID:20260407-acme-shop@ikno\r\n",
+			"DTSTART:" + rows[0].start().UTC().Format(icsTimeFormat) + "\r\n",
+			"DTEND:" + rows[0].start().Add(105*time.Minute).UTC().Format(icsTimeFormat) + "\r\n",
+			"SUMMARY:acme-shop (ACME Corp)\r\n",
+			`DESCRIPTION:feat: coupons\, part 1 (estimated 1.67h)` + "\r\n",
+			"CATEGORIES:billable\r\n",
+			"END:VCALENDAR\r\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tt.format, rows, opts); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+
+	if err := Write(&bytes.Buffer{}, "xlsx", rows, opts); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	long := "DESCRIPTION:" + strings.Repeat("ä", 60)
+	folded := foldLine(long)
+	for _, line := range strings.Split(folded, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %d", len(line))
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != long {
+		t.Error("unfolding should restore the line")
+	}
+}
@@ -4,10 +4,15 @@ import "github.com/charmbracelet/lipgloss"
 
 // Source-type colors. One color per type, used consistently across all renderers.
 var (
-	ColorGit      = lipgloss.AdaptiveColor{Dark: "#82AAFF", Light: "#1A56DB"} // blue
+	ColorGit      = lipgloss.AdaptiveColor{Dark: "#82AAFF", Light: "#1A56DB"} // blue, code work
 	ColorObsidian = lipgloss.AdaptiveColor{Dark: "#C3E88D", Light: "#2E7D32"} // green
 	ColorMarkdown = lipgloss.AdaptiveColor{Dark: "#FFCB6B", Light: "#B45309"} // amber
 	ColorClaude   = lipgloss.AdaptiveColor{Dark: "#F78C6C", Light: "#C05621"} // orange
+	ColorOrg      = lipgloss.AdaptiveColor{Dark: "#C792EA", Light: "#6B21A8"} // purple
+	ColorLogseq   = lipgloss.AdaptiveColor{Dark: "#89DDFF", Light: "#0E7490"} // cyan
+	ColorTodoTxt  = lipgloss.AdaptiveColor{Dark: "#FF5370", Light: "#B91C1C"} // red, all task lists
+	ColorInfra    = lipgloss.AdaptiveColor{Dark: "#7FDBCA", Light: "#0F766E"} // teal, clusters, containers and CI
+	ColorTime     = lipgloss.AdaptiveColor{Dark: "#B2CCD6", Light: "#475569"} // slate, all time trackers
 
 	ColorDay    = lipgloss.AdaptiveColor{Dark: "#666666", Light: "#999999"} // dimmed grey
 	ColorMuted  = lipgloss.AdaptiveColor{Dark: "#4A5568", Light: "#9CA3AF"} // very dimmed
@@ -18,7 +23,7 @@ var (
 // Unknown types fall back to ColorNormal.
 func SourceColor(sourceType string) lipgloss.AdaptiveColor {
 	switch sourceType {
-	case "git":
+	case "git", "editor":
 		return ColorGit
 	case "obsidian":
 		return ColorObsidian
@@ -26,6 +31,16 @@ func SourceColor(sourceType string) lipgloss.AdaptiveColor {
 		return ColorMarkdown
 	case "claude":
 		return ColorClaude
+	case "org":
+		return ColorOrg
+	case "logseq":
+		return ColorLogseq
+	case "todotxt", "taskwarrior", "git-bug":
+		return ColorTodoTxt
+	case "containers", "ci":
+		return ColorInfra
+	case "timewarrior", "watson", "activitywatch", "toggl", "clockify":
+		return ColorTime
 	default:
 		return ColorNormal
 	}